@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"api-to-mcp/internal/utils"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/spf13/cobra"
+)
+
+// benchSample is the outcome of a single tool execution
+type benchSample struct {
+	duration time.Duration
+	err      error
+}
+
+// newBenchCommand creates the bench subcommand which measures upstream latency through a tool
+func newBenchCommand() *cobra.Command {
+	var count int
+	var concurrency int
+	var rawArgs string
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:   "bench <tool>",
+		Short: "Repeatedly execute a tool and report latency percentiles and error rates",
+		Long: "Execute a tool handler repeatedly against the upstream API and report latency percentiles,\n" +
+			"error rate and retry counts, to help choose timeout, retry and rate-limit settings.",
+		Example: `  api-to-mcp bench getpetbyid --n 50 --args '{"petId": 1}'`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if count < 1 || concurrency < 1 {
+				return fmt.Errorf("--n and --concurrency must be positive")
+			}
+
+			var arguments map[string]interface{}
+			if err := json.Unmarshal([]byte(rawArgs), &arguments); err != nil {
+				return fmt.Errorf("invalid --args JSON object: %w", err)
+			}
+
+			tools, err := loadTools(namespace)
+			if err != nil {
+				return err
+			}
+			var tool *mcp.Tool
+			for i := range tools {
+				if tools[i].Name == args[0] {
+					tool = &tools[i]
+					break
+				}
+			}
+			if tool == nil {
+				return fmt.Errorf("tool not found: %s", args[0])
+			}
+
+			retriesBefore := utils.RetryCount()
+			started := time.Now()
+			samples := runBench(tool, arguments, count, concurrency)
+			elapsed := time.Since(started)
+
+			printBenchReport(cmd.OutOrStdout(), tool.Name, samples, elapsed, utils.RetryCount()-retriesBefore)
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&count, "n", 20, "Number of executions")
+	cmd.Flags().IntVar(&concurrency, "concurrency", 1, "Number of concurrent executions")
+	cmd.Flags().StringVarP(&rawArgs, "args", "a", "{}", "Tool arguments as a JSON object")
+	cmd.Flags().StringVar(&namespace, "namespace", "", "Tenant namespace (default: top-level configuration)")
+
+	return cmd
+}
+
+// runBench executes the tool count times using the given number of workers
+func runBench(tool *mcp.Tool, arguments map[string]interface{}, count, concurrency int) []benchSample {
+	samples := make([]benchSample, count)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// Handlers may consume their arguments, so each call gets a copy
+				params := make(map[string]interface{}, len(arguments))
+				for key, value := range arguments {
+					params[key] = value
+				}
+
+				start := time.Now()
+				ctx := utils.WithToolName(utils.WithRequestID(context.Background(), utils.NewRequestID()), tool.Name)
+				_, err := tool.Handler(ctx, params)
+				samples[i] = benchSample{duration: time.Since(start), err: err}
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return samples
+}
+
+// printBenchReport prints latency percentiles, error rate and retry counts
+func printBenchReport(out io.Writer, toolName string, samples []benchSample, elapsed time.Duration, retries int64) {
+	durations := make([]time.Duration, 0, len(samples))
+	var total time.Duration
+	errors := 0
+	var lastErr error
+	for _, sample := range samples {
+		durations = append(durations, sample.duration)
+		total += sample.duration
+		if sample.err != nil {
+			errors++
+			lastErr = sample.err
+		}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	fmt.Fprintf(out, "Tool:        %s\n", toolName)
+	fmt.Fprintf(out, "Requests:    %d in %s (%.1f req/s)\n", len(samples), elapsed.Round(time.Millisecond), float64(len(samples))/elapsed.Seconds())
+	fmt.Fprintf(out, "Errors:      %d (%.1f%%)\n", errors, 100*float64(errors)/float64(len(samples)))
+	fmt.Fprintf(out, "Retries:     %d\n", retries)
+	fmt.Fprintf(out, "Latency:     min %s  mean %s  max %s\n",
+		durations[0].Round(time.Microsecond), (total / time.Duration(len(samples))).Round(time.Microsecond), durations[len(durations)-1].Round(time.Microsecond))
+	fmt.Fprintf(out, "Percentiles: p50 %s  p90 %s  p95 %s  p99 %s\n",
+		percentile(durations, 50), percentile(durations, 90), percentile(durations, 95), percentile(durations, 99))
+	if lastErr != nil {
+		fmt.Fprintf(out, "Last error:  %v\n", lastErr)
+	}
+}
+
+// percentile returns the nearest-rank percentile of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Microsecond)
+}
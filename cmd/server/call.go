@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"api-to-mcp/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// newCallCommand creates the call subcommand which executes a single tool without an MCP client
+func newCallCommand() *cobra.Command {
+	var rawArgs string
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:   "call <tool>",
+		Short: "Invoke a generated tool once and print its result",
+		Example: `  api-to-mcp call getpetbyid --args '{"petId": 1}'
+  api-to-mcp call listissues --namespace github`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var arguments map[string]interface{}
+			if err := json.Unmarshal([]byte(rawArgs), &arguments); err != nil {
+				return fmt.Errorf("invalid --args JSON object: %w", err)
+			}
+			if arguments == nil {
+				arguments = make(map[string]interface{})
+			}
+
+			tools, err := loadTools(namespace)
+			if err != nil {
+				return err
+			}
+
+			for _, tool := range tools {
+				if tool.Name != args[0] {
+					continue
+				}
+
+				ctx := utils.WithToolName(utils.WithRequestID(cmd.Context(), utils.NewRequestID()), tool.Name)
+				result, err := tool.Handler(ctx, arguments)
+				if err != nil {
+					return fmt.Errorf("tool execution failed: %w", err)
+				}
+				return printResult(cmd.OutOrStdout(), result)
+			}
+
+			return fmt.Errorf("tool not found: %s (run 'api-to-mcp tools list' to see available tools)", args[0])
+		},
+	}
+
+	cmd.Flags().StringVarP(&rawArgs, "args", "a", "{}", "Tool arguments as a JSON object")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Tenant namespace (default: top-level configuration)")
+
+	return cmd
+}
+
+// printResult prints a tool result, indenting structured values as JSON
+func printResult(out io.Writer, result interface{}) error {
+	if text, ok := result.(string); ok {
+		_, err := fmt.Fprintln(out, text)
+		return err
+	}
+
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
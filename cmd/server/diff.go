@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/graphql"
+	"api-to-mcp/internal/manifest"
+
+	"github.com/spf13/cobra"
+)
+
+// newDiffCommand creates the diff subcommand which compares two toolsets
+func newDiffCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Compare the toolsets of two specs or exported manifests",
+		Long: "Compare the tools generated from two OpenAPI specs (or previously exported manifests) and\n" +
+			"print added, removed and changed tools. Exits non-zero when breaking changes are found.\n" +
+			"Specs are generated with the filters of --config when it can be loaded.",
+		Example: `  api-to-mcp diff openapi-v1.yaml openapi-v2.yaml
+  api-to-mcp diff tools-release.json openapi.yaml --format json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			oldManifest, err := loadToolset(args[0])
+			if err != nil {
+				return err
+			}
+			newManifest, err := loadToolset(args[1])
+			if err != nil {
+				return err
+			}
+
+			result := manifest.Diff(oldManifest, newManifest)
+
+			switch format {
+			case "text":
+				printDiff(cmd.OutOrStdout(), result)
+			case "json":
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(result); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unsupported format: %s (expected text or json)", format)
+			}
+
+			if result.HasBreakingChanges() {
+				return fmt.Errorf("breaking changes detected")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "text", "Output format: text or json")
+
+	return cmd
+}
+
+// loadToolset loads an exported manifest, or generates one from an OpenAPI spec, AsyncAPI document,
+// WSDL document or GraphQL SDL file
+func loadToolset(path string) (*manifest.Manifest, error) {
+	if m, err := manifest.Load(path); err == nil {
+		return m, nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		// Without a usable config, compare the unfiltered toolsets
+		cfg = &config.Config{OpenAPI: config.OpenAPIConfig{BaseURL: "http://localhost"}}
+	}
+	scoped := *cfg
+	scoped.OpenAPI.SpecPath = path
+	scoped.OpenAPI.GraphQL = config.GraphQLConfig{}
+	scoped.OpenAPI.GRPC = config.GRPCConfig{}
+	scoped.OpenAPI.AsyncAPI = config.AsyncAPIConfig{}
+	if graphql.IsSDLFile(path) {
+		scoped.OpenAPI.GraphQL = config.GraphQLConfig{Enabled: true, SchemaPath: path}
+	}
+
+	tools, err := generateTools(&scoped)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load toolset from %s: %w", path, err)
+	}
+	return manifest.Build(tools, &scoped), nil
+}
+
+// printDiff prints a human-readable diff, marking breaking changes with '!'
+func printDiff(out io.Writer, result *manifest.DiffResult) {
+	if result.IsEmpty() {
+		fmt.Fprintln(out, "No changes")
+		return
+	}
+
+	for _, name := range result.Added {
+		fmt.Fprintf(out, "+ %s\n", name)
+	}
+	for _, name := range result.Removed {
+		fmt.Fprintf(out, "- %s (breaking)\n", name)
+	}
+	for _, tool := range result.Changed {
+		fmt.Fprintf(out, "~ %s\n", tool.Name)
+		for _, change := range tool.Changes {
+			marker := " "
+			if change.Breaking {
+				marker = "!"
+			}
+			fmt.Fprintf(out, "    %s %s\n", marker, change.Message)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"api-to-mcp/internal/asyncapi"
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/manifest"
+	"api-to-mcp/internal/secrets"
+	"api-to-mcp/internal/server"
+	"api-to-mcp/internal/soap"
+	"api-to-mcp/internal/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// doctorTimeout bounds every network check performed by doctor
+const doctorTimeout = 10 * time.Second
+
+// newDoctorCommand creates the doctor subcommand which diagnoses spec, connectivity and auth problems
+func newDoctorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Check spec loading, upstream connectivity, TLS and credentials",
+		Long: "Run diagnostic checks for every namespace: the spec loads and generates tools, the base URL\n" +
+			"resolves, the TLS handshake succeeds and, when openapi.probe_path is set, the configured\n" +
+			"credentials are accepted by the upstream API. Exits non-zero if any check fails.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			cfg, err := loadConfig()
+			if err != nil {
+				reportCheck(out, "config", err, "")
+				return fmt.Errorf("doctor found problems")
+			}
+			reportCheck(out, "config", nil, configPath)
+
+			failed := false
+			if len(cfg.Aggregate) > 0 {
+				for _, upstream := range cfg.Aggregate {
+					if !runDoctorChecks(out, "default/"+upstream.Name, cfg.ForUpstream(upstream)) {
+						failed = true
+					}
+				}
+			} else {
+				failed = !runDoctorChecks(out, "default", cfg)
+			}
+			for _, tenant := range cfg.Tenants {
+				if !runDoctorChecks(out, tenant.Name, cfg.ForTenant(tenant)) {
+					failed = true
+				}
+			}
+
+			if failed {
+				return fmt.Errorf("doctor found problems")
+			}
+			return nil
+		},
+	}
+}
+
+// runDoctorChecks runs all checks for one namespace and reports whether they all passed
+func runDoctorChecks(out io.Writer, namespace string, cfg *config.Config) bool {
+	fmt.Fprintf(out, "\n[%s]\n", namespace)
+	passed := true
+	check := func(name string, detail string, err error) bool {
+		reportCheck(out, name, err, detail)
+		if err != nil {
+			passed = false
+		}
+		return err == nil
+	}
+
+	tools, err := server.BuildTools(cfg, newCLILogger())
+	source := cfg.OpenAPI.SpecPath
+	if cfg.OpenAPI.GraphQL.Enabled {
+		source = "GraphQL " + cfg.OpenAPI.GraphQL.SchemaPath
+		if cfg.OpenAPI.GraphQL.SchemaPath == "" {
+			source = "GraphQL introspection"
+		}
+	}
+	if cfg.OpenAPI.GRPC.Enabled {
+		source = "gRPC " + cfg.OpenAPI.GRPC.Target
+	}
+	events := !cfg.OpenAPI.GraphQL.Enabled && !cfg.OpenAPI.GRPC.Enabled && asyncapi.IsDocument(cfg.OpenAPI.SpecPath)
+	if events {
+		source = "AsyncAPI " + cfg.OpenAPI.SpecPath
+	}
+	wsdl := !cfg.OpenAPI.GraphQL.Enabled && !cfg.OpenAPI.GRPC.Enabled && soap.IsWSDL(cfg.OpenAPI.SpecPath)
+	if wsdl {
+		source = "SOAP " + cfg.OpenAPI.SpecPath
+	}
+	check("spec", fmt.Sprintf("%s (%d tools)", source, len(tools)), err)
+	if err == nil && cfg.OpenAPI.Manifest.Path != "" {
+		check("manifest", fmt.Sprintf("%s signature verified, toolset matches", cfg.OpenAPI.Manifest.Path), manifest.VerifyToolset(cfg.OpenAPI.Manifest, tools))
+	}
+
+	if cfg.OpenAPI.GRPC.Enabled {
+		fmt.Fprintln(out, "  SKIP  base_url: gRPC namespaces connect to openapi.grpc.target")
+		return passed
+	}
+	if events {
+		fmt.Fprintln(out, "  SKIP  base_url: AsyncAPI namespaces connect to the document's server")
+		return passed
+	}
+	if wsdl {
+		fmt.Fprintln(out, "  SKIP  base_url: SOAP namespaces call the WSDL port address or openapi.soap.endpoint")
+		return passed
+	}
+
+	baseURL, err := url.Parse(cfg.OpenAPI.BaseURL)
+	if err == nil && baseURL.Hostname() == "" {
+		err = fmt.Errorf("base URL has no host: %s", cfg.OpenAPI.BaseURL)
+	}
+	if !check("base_url", secrets.ScrubURL(cfg.OpenAPI.BaseURL), err) {
+		return false
+	}
+
+	addrs, err := net.LookupHost(baseURL.Hostname())
+	if !check("dns", fmt.Sprintf("%s → %v", baseURL.Hostname(), addrs), err) {
+		return false
+	}
+
+	if baseURL.Scheme == "https" {
+		detail, err := checkTLS(baseURL)
+		if !check("tls", detail, err) {
+			return false
+		}
+	}
+
+	if cfg.Auth.Type != "" {
+		value, err := cfg.Auth.Token.Reveal()
+		secrets.Wipe(value)
+		if err == nil && len(value) == 0 {
+			err = fmt.Errorf("%s authentication has no token", cfg.Auth.Type)
+		}
+		if !check("credentials", fmt.Sprintf("%s token from %s", cfg.Auth.Type, cfg.Auth.Token.Source()), err) {
+			return false
+		}
+	}
+
+	if cfg.OpenAPI.ProbePath == "" {
+		fmt.Fprintln(out, "  SKIP  auth: set openapi.probe_path to verify credentials")
+		return passed
+	}
+
+	httpClient := utils.NewHTTPClient(cfg.OpenAPI.BaseURL, newCLILogger())
+	if cfg.Auth.Type != "" {
+		httpClient.SetAuth(cfg.Auth.Type, cfg.Auth.Token)
+	}
+	status, err := httpClient.Probe(http.MethodGet, cfg.OpenAPI.ProbePath)
+	if err == nil && (status == http.StatusUnauthorized || status == http.StatusForbidden) {
+		err = fmt.Errorf("credentials rejected with HTTP %d", status)
+	} else if err == nil && status >= 400 {
+		err = fmt.Errorf("probe returned HTTP %d", status)
+	}
+	check("auth", fmt.Sprintf("GET %s → HTTP %d", cfg.OpenAPI.ProbePath, status), err)
+
+	return passed
+}
+
+// checkTLS performs a TLS handshake with the upstream host and describes the peer certificate
+func checkTLS(baseURL *url.URL) (string, error) {
+	port := baseURL.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	dialer := &net.Dialer{Timeout: doctorTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", net.JoinHostPort(baseURL.Hostname(), port), &tls.Config{
+		ServerName: baseURL.Hostname(),
+	})
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return "handshake ok", nil
+	}
+	return fmt.Sprintf("%s, certificate expires %s", tls.VersionName(conn.ConnectionState().Version), certs[0].NotAfter.Format("2006-01-02")), nil
+}
+
+// reportCheck prints a single PASS/FAIL line
+func reportCheck(out io.Writer, name string, err error, detail string) {
+	if err != nil {
+		fmt.Fprintf(out, "  FAIL  %s: %v\n", name, err)
+		return
+	}
+	fmt.Fprintf(out, "  PASS  %s: %s\n", name, detail)
+}
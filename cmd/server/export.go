@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"api-to-mcp/internal/asyncapi"
+	"api-to-mcp/internal/catalog"
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/functions"
+	"api-to-mcp/internal/manifest"
+	"api-to-mcp/internal/parser"
+	"api-to-mcp/internal/soap"
+	"api-to-mcp/internal/specfilter"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/spf13/cobra"
+)
+
+// newExportCommand creates the export subcommand which writes a static tool manifest
+func newExportCommand() *cobra.Command {
+	var output string
+	var namespace string
+	var signKey string
+	var format string
+	var serverURL string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write the generated toolset as a JSON manifest, tool catalog or function definitions",
+		Long: "Write every generated tool (name, description, input schema and upstream endpoint)\n" +
+			"to a JSON manifest that other systems can consume or diff across releases, as a tool\n" +
+			"catalog for agent frameworks such as LangChain and LlamaIndex (--format catalog), as\n" +
+			"function-calling definitions for model APIs (--format openai or anthropic), or as the\n" +
+			"OpenAPI document reduced to the operations tools are generated from (--format openapi).",
+		Example: `  api-to-mcp export --output tools.json
+  api-to-mcp export --namespace github > github-tools.json
+  api-to-mcp export --output tools.json --sign-key manifest.key
+  api-to-mcp export --format catalog --server-url https://tools.example.com > catalog.json
+  api-to-mcp export --format openapi --output exposed.yaml
+  api-to-mcp export --format openai --output openai-tools.json
+  api-to-mcp export --format anthropic --output anthropic-tools.json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadNamespace(namespace)
+			if err != nil {
+				return err
+			}
+
+			toStdout := output == "" || output == "-"
+			if signKey != "" && toStdout {
+				return fmt.Errorf("--sign-key requires --output to name a manifest file")
+			}
+
+			if format != "manifest" && signKey != "" {
+				return fmt.Errorf("--sign-key is only supported with --format manifest")
+			}
+			if format != "catalog" && serverURL != "" {
+				return fmt.Errorf("--server-url is only supported with --format catalog")
+			}
+
+			tools, err := generateTools(cfg)
+			if err != nil {
+				return err
+			}
+			if format == "openapi" {
+				return exportOpenAPI(cmd, cfg, tools, output)
+			}
+			if format == "catalog" {
+				return exportCatalog(cmd, tools, catalog.Server{
+					Name:      cfg.MCP.ServerName,
+					Version:   cfg.MCP.Version,
+					URL:       serverURL,
+					Namespace: namespace,
+				}, output)
+			}
+			if format != "manifest" {
+				return exportFunctions(cmd, tools, format, output)
+			}
+			m := manifest.Build(tools, cfg)
+
+			data, err := m.Marshal()
+			if err != nil {
+				return err
+			}
+			if toStdout {
+				_, err = cmd.OutOrStdout().Write(data)
+				return err
+			}
+
+			if err := os.WriteFile(output, data, 0644); err != nil {
+				return fmt.Errorf("failed to write manifest: %w", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d tools to %s\n", len(m.Tools), output)
+
+			if signKey != "" {
+				key, err := manifest.LoadPrivateKey(signKey)
+				if err != nil {
+					return err
+				}
+				signature := output + manifest.SignatureSuffix
+				if err := os.WriteFile(signature, manifest.Sign(data, key), 0644); err != nil {
+					return fmt.Errorf("failed to write manifest signature: %w", err)
+				}
+				fmt.Fprintf(cmd.ErrOrStderr(), "Wrote signature %s\n", signature)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "-", "Manifest file path ('-' for stdout)")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Tenant namespace (default: top-level configuration)")
+	cmd.Flags().StringVarP(&format, "format", "f", "manifest", "Output format: manifest, catalog, openapi or "+strings.Join(functions.Names(), ", "))
+	cmd.Flags().StringVar(&serverURL, "server-url", "", "Base URL of the bridge recorded in the catalog, e.g. https://tools.example.com")
+	cmd.Flags().StringVar(&signKey, "sign-key", "", "Private key from 'api-to-mcp keygen'; writes a detached signature to <output>.sig")
+
+	return cmd
+}
+
+// exportCatalog writes the tools as a catalog for agent frameworks
+func exportCatalog(cmd *cobra.Command, tools []mcp.Tool, server catalog.Server, output string) error {
+	c, err := catalog.Build(tools, server)
+	if err != nil {
+		return err
+	}
+	data, err := c.Marshal()
+	if err != nil {
+		return err
+	}
+
+	if output == "" || output == "-" {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write catalog: %w", err)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d tools to %s\n", len(c.Tools), output)
+	return nil
+}
+
+// exportOpenAPI writes the configured OpenAPI document reduced to the operations the tools
+// were generated from, as JSON when the output file ends in .json and YAML otherwise
+func exportOpenAPI(cmd *cobra.Command, cfg *config.Config, tools []mcp.Tool, output string) error {
+	if len(cfg.Aggregate) > 0 {
+		return fmt.Errorf("--format openapi does not support aggregated upstreams")
+	}
+	if cfg.OpenAPI.GraphQL.Enabled || cfg.OpenAPI.GRPC.Enabled || asyncapi.IsDocument(cfg.OpenAPI.SpecPath) || soap.IsWSDL(cfg.OpenAPI.SpecPath) {
+		return fmt.Errorf("--format openapi requires an OpenAPI specification")
+	}
+	data, err := os.ReadFile(cfg.OpenAPI.SpecPath)
+	if err != nil {
+		return fmt.Errorf("failed to read specification: %w", err)
+	}
+	if parser.IsPostmanCollection(data) {
+		return fmt.Errorf("--format openapi requires an OpenAPI specification")
+	}
+
+	doc, report, err := specfilter.Filter(data, tools)
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(strings.ToLower(output), ".json") {
+		data, err = doc.JSON()
+	} else {
+		data, err = doc.YAML()
+	}
+	if err != nil {
+		return err
+	}
+
+	if output == "" || output == "-" {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write specification: %w", err)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d of %d operations to %s (%d unused components removed)\n",
+		report.Operations-report.Removed, report.Operations, output, len(report.Components))
+	return nil
+}
+
+// exportFunctions writes the tools as function-calling definitions in the given format
+func exportFunctions(cmd *cobra.Command, tools []mcp.Tool, name, output string) error {
+	format, err := functions.Lookup(name)
+	if err != nil {
+		return err
+	}
+	definitions, err := format.Tools(tools)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(definitions, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode %s tools: %w", format.Name, err)
+	}
+	data = append(data, '\n')
+
+	if output == "" || output == "-" {
+		_, err = cmd.OutOrStdout().Write(data)
+		return err
+	}
+	if err := os.WriteFile(output, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s tools: %w", format.Name, err)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d tools to %s\n", len(tools), output)
+	return nil
+}
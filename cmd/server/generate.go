@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"api-to-mcp/internal/asyncapi"
+	"api-to-mcp/internal/codegen"
+	"api-to-mcp/internal/generator"
+	"api-to-mcp/internal/parser"
+	"api-to-mcp/internal/soap"
+
+	"github.com/spf13/cobra"
+)
+
+// packageNamePattern matches valid Go package names
+var packageNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// newGenerateCommand creates the generate command group
+func newGenerateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate code from the configured toolset",
+	}
+
+	cmd.AddCommand(newGenerateGoCommand())
+
+	return cmd
+}
+
+// newGenerateGoCommand creates the generate go subcommand which emits a standalone Go package
+func newGenerateGoCommand() *cobra.Command {
+	var output string
+	var packageName string
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:   "go",
+		Short: "Emit a self-contained Go package with the tools compiled in",
+		Long: "Emit a Go package exposing Tools() []mcp.Tool with schemas and handlers compiled in,\n" +
+			"so a reviewed, frozen toolset can be vendored into another binary without YAML or spec files.",
+		Example: `  api-to-mcp generate go --output ./internal/petstore --package petstore`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if packageName == "" {
+				packageName = filepath.Base(output)
+			}
+			if !packageNamePattern.MatchString(packageName) {
+				return fmt.Errorf("invalid Go package name: %s (use --package)", packageName)
+			}
+
+			cfg, err := loadNamespace(namespace)
+			if err != nil {
+				return err
+			}
+
+			if cfg.OpenAPI.GraphQL.Enabled || cfg.OpenAPI.GRPC.Enabled || asyncapi.IsDocument(cfg.OpenAPI.SpecPath) || soap.IsWSDL(cfg.OpenAPI.SpecPath) {
+				return fmt.Errorf("code generation requires an OpenAPI specification; GraphQL, gRPC, AsyncAPI and SOAP namespaces are not supported")
+			}
+
+			logger := newCLILogger()
+			spec, err := parser.NewOpenAPIParser(cfg.OpenAPI.SpecPath, logger).ParseSpec()
+			if err != nil {
+				return err
+			}
+			tools, err := generator.NewMCPToolGenerator(spec, cfg, logger).GenerateTools()
+			if err != nil {
+				return err
+			}
+
+			source, err := codegen.GenerateGo(tools, spec.Endpoints, codegen.GoOptions{
+				PackageName: packageName,
+				BaseURL:     cfg.OpenAPI.BaseURL,
+			})
+			if err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(output, 0755); err != nil {
+				return fmt.Errorf("failed to create output directory: %w", err)
+			}
+			path := filepath.Join(output, "tools.go")
+			if err := os.WriteFile(path, source, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", path, err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Generated %d tools in %s\n", len(tools), path)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "./mcptools", "Output directory for the generated package")
+	cmd.Flags().StringVar(&packageName, "package", "", "Go package name (default: output directory name)")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Tenant namespace (default: top-level configuration)")
+
+	return cmd
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/parser"
+
+	"github.com/spf13/cobra"
+)
+
+// newInitCommand creates the init subcommand which scaffolds a starter configuration
+func newInitCommand() *cobra.Command {
+	var specPath string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Write a commented starter configuration file",
+		Long: "Write a commented starter configuration to the --config path. When --spec is given,\n" +
+			"the base URL and example path filters are pre-filled from the specification.",
+		Example: `  api-to-mcp init
+  api-to-mcp init --spec ./openapi.yaml --config ./config.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stat(configPath); err == nil && !force {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", configPath)
+			}
+
+			starter := config.DefaultStarterConfig()
+			if specPath != "" {
+				var err error
+				starter, err = starterFromSpec(specPath)
+				if err != nil {
+					return err
+				}
+			}
+
+			if err := config.WriteStarterConfig(configPath, starter); err != nil {
+				return fmt.Errorf("failed to write configuration: %w", err)
+			}
+
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", configPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&specPath, "spec", "s", "", "OpenAPI specification used to pre-fill the configuration")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing configuration file")
+
+	return cmd
+}
+
+// starterFromSpec derives starter configuration values from an OpenAPI specification or Postman collection
+func starterFromSpec(specPath string) (config.StarterConfig, error) {
+	spec, err := parser.NewOpenAPIParser(specPath, newCLILogger()).ParseSpec()
+	if err != nil {
+		return config.StarterConfig{}, err
+	}
+
+	starter := config.DefaultStarterConfig()
+	starter.SpecPath = specPath
+	starter.BaseURL = "https://api.example.com"
+	for _, server := range spec.Servers {
+		if strings.HasPrefix(server.URL, "http://") || strings.HasPrefix(server.URL, "https://") {
+			starter.BaseURL = server.URL
+			break
+		}
+	}
+
+	// Pre-select authentication the spec declares, when the client supports it
+	if spec.Auth != nil && (spec.Auth.Type == "bearer" || spec.Auth.Type == "apikey") {
+		starter.AuthType = spec.Auth.Type
+	}
+
+	// Offer the first path segments as commented filter examples
+	prefixes := make(map[string]bool)
+	for _, endpoint := range spec.Endpoints {
+		segment := strings.SplitN(strings.TrimPrefix(endpoint.Path, "/"), "/", 2)[0]
+		if segment != "" && !strings.HasPrefix(segment, "{") {
+			prefixes["/"+segment] = true
+		}
+	}
+	for prefix := range prefixes {
+		starter.ExamplePaths = append(starter.ExamplePaths, prefix)
+	}
+	sort.Strings(starter.ExamplePaths)
+
+	return starter, nil
+}
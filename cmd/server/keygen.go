@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"api-to-mcp/internal/manifest"
+
+	"github.com/spf13/cobra"
+)
+
+// newKeygenCommand creates the keygen subcommand which generates a manifest signing key pair
+func newKeygenCommand() *cobra.Command {
+	var output string
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Generate a key pair for signing tool manifests",
+		Long: "Generate an Ed25519 key pair. The private key signs manifests with\n" +
+			"'api-to-mcp export --sign-key'; the public key (<output>.pub) is configured as\n" +
+			"openapi.manifest.public_key on servers that must only serve signed toolsets.",
+		Example: `  api-to-mcp keygen --output manifest.key`,
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := os.Stat(output); err == nil && !force {
+				return fmt.Errorf("%s already exists (use --force to overwrite)", output)
+			}
+
+			keyID, err := manifest.WriteKeyPair(output)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote private key %s and public key %s.pub (key ID %s)\n", output, output, keyID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "manifest.key", "Private key path; the public key is written next to it with a .pub suffix")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing key")
+
+	return cmd
+}
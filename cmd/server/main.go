@@ -1,58 +1,83 @@
 package main
 
 import (
-	"context"
-	"flag"
 	"fmt"
-	"log"
 	"os"
-	"os/signal"
-	"syscall"
 
 	"api-to-mcp/internal/config"
-	"api-to-mcp/internal/server"
+	"api-to-mcp/internal/version"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
 )
 
-func main() {
-	// Parse command line flags
-	configPath := flag.String("config", "config.yaml", "Path to configuration file")
-	port := flag.Int("port", 8080, "Server port")
-	flag.Parse()
+// configPath is the configuration file shared by all subcommands
+var configPath string
 
-	// Load configuration
-	cfg, err := config.Load(*configPath)
-	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
 	}
+}
 
-	// Override port if specified via flag
-	if *port != 8080 {
-		cfg.Server.Port = *port
-	}
+// newRootCommand creates the api-to-mcp root command and registers its subcommands
+func newRootCommand() *cobra.Command {
+	serveCmd := newServeCommand()
 
-	// Create MCP server
-	mcpServer, err := server.NewMCPServer(cfg)
-	if err != nil {
-		log.Fatalf("Failed to create MCP server: %v", err)
+	rootCmd := &cobra.Command{
+		Use:           "api-to-mcp",
+		Short:         "Expose OpenAPI-described REST APIs as MCP tools",
+		SilenceUsage:  true,
+		SilenceErrors: false,
+		Version:       version.Get().String(),
+		// Running without a subcommand starts the server
+		RunE: serveCmd.RunE,
 	}
 
-	// Start server
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", config.GetConfigPath(), "Path to configuration file")
+	rootCmd.Flags().AddFlagSet(serveCmd.Flags())
 
-	// Handle graceful shutdown
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(newValidateCommand())
+	rootCmd.AddCommand(newCallCommand())
+	rootCmd.AddCommand(newInitCommand())
+	rootCmd.AddCommand(newExportCommand())
+	rootCmd.AddCommand(newKeygenCommand())
+	rootCmd.AddCommand(newDoctorCommand())
+	rootCmd.AddCommand(newGenerateCommand())
+	rootCmd.AddCommand(newDiffCommand())
+	rootCmd.AddCommand(newBenchCommand())
+	rootCmd.AddCommand(newToolsCommand())
 
-	go func() {
-		<-sigChan
-		fmt.Println("\nShutting down server...")
-		cancel()
-	}()
+	return rootCmd
+}
 
-	// Start the server
-	fmt.Printf("Starting API-to-MCP server on port %d\n", cfg.Server.Port)
-	if err := mcpServer.Start(ctx); err != nil {
-		log.Fatalf("Server failed: %v", err)
+// loadConfig loads the configuration selected by the shared --config flag
+func loadConfig() (*config.Config, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// resolveNamespace returns the configuration for the default namespace or a named tenant
+func resolveNamespace(cfg *config.Config, namespace string) (*config.Config, error) {
+	if namespace == "" {
+		return cfg, nil
 	}
+	for _, tenant := range cfg.Tenants {
+		if tenant.Name == namespace {
+			return cfg.ForTenant(tenant), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown namespace: %s", namespace)
+}
+
+// newCLILogger creates a quiet logger for offline commands so their output stays readable
+func newCLILogger() *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(os.Stderr)
+	logger.SetLevel(logrus.WarnLevel)
+	return logger
 }
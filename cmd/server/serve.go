@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"api-to-mcp/internal/server"
+
+	"github.com/spf13/cobra"
+)
+
+// newServeCommand creates the serve subcommand which runs the MCP server
+func newServeCommand() *cobra.Command {
+	var port int
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start the MCP server",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Load configuration
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+
+			// Override port if specified via flag
+			if port != 0 {
+				cfg.Server.Port = port
+			}
+
+			// Create MCP server
+			mcpServer, err := server.NewMCPServer(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to create MCP server: %w", err)
+			}
+
+			// Single-shot startup check: parse, generate and bind, then exit
+			if check {
+				if err := mcpServer.Check(); err != nil {
+					return err
+				}
+				fmt.Printf("Startup check passed: %d tools, port %d available\n", len(mcpServer.GetTools()), cfg.Server.Port)
+				return nil
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// Handle graceful shutdown
+			sigChan := make(chan os.Signal, 1)
+			signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+
+			go func() {
+				<-sigChan
+				fmt.Println("\nShutting down server...")
+				cancel()
+			}()
+
+			// Start the server
+			fmt.Printf("Starting API-to-MCP server on port %d\n", cfg.Server.Port)
+			if err := mcpServer.Start(ctx); err != nil {
+				return fmt.Errorf("server failed: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&port, "port", "p", 0, "Server port (overrides server.port)")
+	cmd.Flags().BoolVar(&check, "check", false, "Perform startup (parse, generate, bind) and exit; for healthchecks and CI")
+
+	return cmd
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/server"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/spf13/cobra"
+)
+
+// newToolsCommand creates the tools command group
+func newToolsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tools",
+		Short: "Inspect the generated MCP tools",
+	}
+
+	cmd.AddCommand(newToolsListCommand())
+
+	return cmd
+}
+
+// newToolsListCommand creates the tools list subcommand which previews generated tools offline
+func newToolsListCommand() *cobra.Command {
+	var format string
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the tools generated from the configured specification",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tools, err := loadTools(namespace)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case "table":
+				return printToolsTable(cmd.OutOrStdout(), tools)
+			case "json":
+				return printToolsJSON(cmd.OutOrStdout(), tools)
+			default:
+				return fmt.Errorf("unsupported format: %s (expected table or json)", format)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&format, "format", "f", "table", "Output format: table or json")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Tenant namespace (default: top-level configuration)")
+
+	return cmd
+}
+
+// loadTools loads the configuration and generates the tools for a namespace, sorted by name
+func loadTools(namespace string) ([]mcp.Tool, error) {
+	cfg, err := loadNamespace(namespace)
+	if err != nil {
+		return nil, err
+	}
+	return generateTools(cfg)
+}
+
+// loadNamespace loads the configuration scoped to a namespace
+func loadNamespace(namespace string) (*config.Config, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return resolveNamespace(cfg, namespace)
+}
+
+// generateTools generates the tools for a configuration, sorted by name
+func generateTools(cfg *config.Config) ([]mcp.Tool, error) {
+	tools, err := server.BuildTools(cfg, newCLILogger())
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(tools, func(i, j int) bool {
+		return tools[i].Name < tools[j].Name
+	})
+	return tools, nil
+}
+
+// printToolsTable prints one row per tool with its arguments, required ones marked with '*'
+func printToolsTable(out io.Writer, tools []mcp.Tool) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tDESCRIPTION\tARGUMENTS")
+	for _, tool := range tools {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", tool.Name, tool.Description, formatArguments(tool.InputSchema))
+	}
+	return w.Flush()
+}
+
+// printToolsJSON prints the tools with their full input schemas
+func printToolsJSON(out io.Writer, tools []mcp.Tool) error {
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(tools)
+}
+
+// formatArguments renders an input schema as a compact "name:type" list
+func formatArguments(schema *mcp.InputSchema) string {
+	if schema == nil || len(schema.Properties) == 0 {
+		return "-"
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	names := make([]string, 0, len(schema.Properties))
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	args := make([]string, 0, len(names))
+	for _, name := range names {
+		arg := fmt.Sprintf("%s:%s", name, schema.Properties[name].Type)
+		if required[name] {
+			arg += "*"
+		}
+		args = append(args, arg)
+	}
+	return strings.Join(args, ", ")
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"api-to-mcp/internal/asyncapi"
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/generator"
+	"api-to-mcp/internal/graphql"
+	"api-to-mcp/internal/grpcapi"
+	"api-to-mcp/internal/parser"
+	"api-to-mcp/internal/server"
+	"api-to-mcp/internal/soap"
+	"api-to-mcp/pkg/mcp"
+	"api-to-mcp/pkg/openapi"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+)
+
+// newValidateCommand creates the validate subcommand which checks config and specs without serving
+func newValidateCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "validate",
+		Short: "Validate the configuration and OpenAPI specifications",
+		Long: "Parse and validate the configured OpenAPI specifications, report errors and warnings,\n" +
+			"and list which endpoints would generate tools. Exits non-zero on failure.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+			logger := newCLILogger()
+
+			cfg, err := loadConfig()
+			if err != nil {
+				fmt.Fprintf(out, "✗ Configuration %s: %v\n", configPath, err)
+				return fmt.Errorf("validation failed")
+			}
+			fmt.Fprintf(out, "✓ Configuration %s\n", configPath)
+
+			var failed bool
+			if len(cfg.Aggregate) > 0 {
+				failed = !validateAggregate(out, cfg, logger)
+			} else {
+				failed = !validateSpec(out, "default", cfg, logger)
+			}
+			for _, tenant := range cfg.Tenants {
+				if !validateSpec(out, tenant.Name, cfg.ForTenant(tenant), logger) {
+					failed = true
+				}
+			}
+
+			if failed {
+				return fmt.Errorf("validation failed")
+			}
+			return nil
+		},
+	}
+}
+
+// validateSpec prints the validation report for one namespace and reports whether it passed
+func validateSpec(out io.Writer, namespace string, cfg *config.Config, logger *logrus.Logger) bool {
+	if cfg.OpenAPI.GraphQL.Enabled {
+		return validateGraphQL(out, namespace, cfg, logger)
+	}
+	if cfg.OpenAPI.GRPC.Enabled {
+		return validateGRPC(out, namespace, cfg, logger)
+	}
+	if asyncapi.IsDocument(cfg.OpenAPI.SpecPath) {
+		return validateAsyncAPI(out, namespace, cfg, logger)
+	}
+	if soap.IsWSDL(cfg.OpenAPI.SpecPath) {
+		return validateSOAP(out, namespace, cfg, logger)
+	}
+	fmt.Fprintf(out, "\n[%s] %s\n", namespace, cfg.OpenAPI.SpecPath)
+
+	spec, err := parser.NewOpenAPIParser(cfg.OpenAPI.SpecPath, logger).ParseSpec()
+	if err != nil {
+		fmt.Fprintf(out, "✗ %v\n", err)
+		return false
+	}
+	fmt.Fprintf(out, "✓ %s %s\n", spec.Info.Title, spec.Info.Version)
+
+	gen := generator.NewMCPToolGenerator(spec, cfg, logger)
+	endpoints := sortedEndpoints(spec.Endpoints)
+
+	included := 0
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, endpoint := range endpoints {
+		if gen.IsEndpointIncluded(endpoint) {
+			included++
+			fmt.Fprintf(w, "  +\t%s\t%s\t→ %s\n", endpoint.Method, endpoint.Path, gen.ToolName(endpoint))
+		} else {
+			fmt.Fprintf(w, "  -\t%s\t%s\t(filtered)\n", endpoint.Method, endpoint.Path)
+		}
+	}
+	w.Flush()
+
+	warnings := parser.NewValidator(logger).CollectWarnings(spec)
+	if len(warnings) > 0 {
+		fmt.Fprintf(out, "Warnings (%d):\n", len(warnings))
+		for _, warning := range warnings {
+			fmt.Fprintf(out, "  ! %s\n", warning)
+		}
+	}
+
+	if _, err := gen.GenerateTools(); err != nil {
+		fmt.Fprintf(out, "✗ %v\n", err)
+		return false
+	}
+
+	fmt.Fprintf(out, "✓ %d of %d endpoints generate tools\n", included, len(endpoints))
+	return true
+}
+
+// validateAggregate validates each aggregated upstream, then prints how their tools merge into
+// the default namespace and reports whether all passed; conflicts are warnings
+func validateAggregate(out io.Writer, cfg *config.Config, logger *logrus.Logger) bool {
+	passed := true
+	for _, upstream := range cfg.Aggregate {
+		if !validateSpec(out, "default/"+upstream.Name, cfg.ForUpstream(upstream), logger) {
+			passed = false
+		}
+	}
+	if !passed {
+		return false
+	}
+
+	fmt.Fprintf(out, "\n[default] aggregate of %d upstreams\n", len(cfg.Aggregate))
+	report, err := server.BuildAggregateReport(cfg, logger)
+	if err != nil {
+		fmt.Fprintf(out, "✗ %v\n", err)
+		return false
+	}
+
+	total := 0
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, upstream := range report.Upstreams {
+		total += upstream.Tools
+		fmt.Fprintf(w, "  +\t%s*\t%s\t%d tools, %d resources\n", upstream.Prefix, upstream.Name, upstream.Tools, upstream.Resources)
+	}
+	w.Flush()
+
+	if len(report.Conflicts) > 0 {
+		fmt.Fprintf(out, "Conflicts (%d):\n", len(report.Conflicts))
+		for _, conflict := range report.Conflicts {
+			fmt.Fprintf(out, "  ! %s\n", conflict)
+		}
+	}
+
+	fmt.Fprintf(out, "✓ %d tools generated\n", total)
+	return true
+}
+
+// validateGraphQL prints the tools a GraphQL namespace generates and reports whether it passed
+func validateGraphQL(out io.Writer, namespace string, cfg *config.Config, logger *logrus.Logger) bool {
+	source := cfg.OpenAPI.GraphQL.SchemaPath
+	if source == "" {
+		source = cfg.OpenAPI.BaseURL + cfg.OpenAPI.GraphQL.Endpoint + " (introspection)"
+	}
+	fmt.Fprintf(out, "\n[%s] GraphQL %s\n", namespace, source)
+
+	tools, err := graphql.BuildTools(context.Background(), cfg, logger)
+	return reportTools(out, tools, err)
+}
+
+// validateGRPC prints the tools a gRPC namespace generates and reports whether it passed
+func validateGRPC(out io.Writer, namespace string, cfg *config.Config, logger *logrus.Logger) bool {
+	source := cfg.OpenAPI.GRPC.DescriptorSet
+	if source == "" {
+		source = cfg.OpenAPI.GRPC.Target + " (reflection)"
+	}
+	fmt.Fprintf(out, "\n[%s] gRPC %s\n", namespace, source)
+
+	tools, err := grpcapi.BuildTools(context.Background(), cfg, logger)
+	return reportTools(out, tools, err)
+}
+
+// validateAsyncAPI prints the tools and resources an AsyncAPI namespace generates and reports
+// whether it passed
+func validateAsyncAPI(out io.Writer, namespace string, cfg *config.Config, logger *logrus.Logger) bool {
+	fmt.Fprintf(out, "\n[%s] AsyncAPI %s\n", namespace, cfg.OpenAPI.SpecPath)
+
+	bridge, err := asyncapi.NewBridge(cfg, logger)
+	if err != nil {
+		fmt.Fprintf(out, "✗ %v\n", err)
+		return false
+	}
+	defer bridge.Close()
+	if !reportTools(out, bridge.Tools(), nil) {
+		return false
+	}
+
+	resources := bridge.Resources()
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, resource := range resources {
+		fmt.Fprintf(w, "  +	%s	→ %s\n", resource.URI, resource.Name)
+	}
+	w.Flush()
+	fmt.Fprintf(out, "✓ %d resources generated\n", len(resources))
+	return true
+}
+
+// validateSOAP prints the tools a WSDL namespace generates and reports whether it passed
+func validateSOAP(out io.Writer, namespace string, cfg *config.Config, logger *logrus.Logger) bool {
+	fmt.Fprintf(out, "\n[%s] SOAP %s\n", namespace, cfg.OpenAPI.SpecPath)
+
+	tools, err := soap.BuildTools(cfg, logger)
+	return reportTools(out, tools, err)
+}
+
+// reportTools lists generated tools with the method and path their safety rules match
+func reportTools(out io.Writer, tools []mcp.Tool, err error) bool {
+	if err != nil {
+		fmt.Fprintf(out, "✗ %v\n", err)
+		return false
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	for _, tool := range tools {
+		fmt.Fprintf(w, "  +\t%s\t%s\t→ %s\n", tool.Operation.Method, tool.Operation.Path, tool.Name)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "✓ %d tools generated\n", len(tools))
+	return true
+}
+
+// sortedEndpoints returns the endpoints ordered by path and method for stable output
+func sortedEndpoints(endpoints []openapi.Endpoint) []openapi.Endpoint {
+	sorted := make([]openapi.Endpoint, len(endpoints))
+	copy(sorted, endpoints)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+	return sorted
+}
@@ -0,0 +1,165 @@
+package aggregate
+
+import (
+	"context"
+	"fmt"
+
+	"api-to-mcp/pkg/mcp"
+)
+
+// Kinds of conflicting entries
+const (
+	KindTool     = "tool"
+	KindResource = "resource"
+)
+
+// Upstream is the toolset generated from one aggregated specification
+type Upstream struct {
+	Name string
+	// Prefix is prepended to every tool and resource name, e.g. github_
+	Prefix    string
+	Tools     []mcp.Tool
+	Resources mcp.ResourceProvider
+}
+
+// Conflict is a tool or resource left out because an earlier entry already uses its name, or
+// for resources its URI
+type Conflict struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+	// Upstream is the upstream whose entry was left out
+	Upstream string `json:"upstream"`
+	// KeptFrom is the upstream whose entry is served
+	KeptFrom string `json:"kept_from"`
+}
+
+// String describes the conflict for logs and reports
+func (c Conflict) String() string {
+	if c.Upstream == c.KeptFrom {
+		return fmt.Sprintf("%s %s is generated more than once by %s; the first is served", c.Kind, c.Name, c.Upstream)
+	}
+	return fmt.Sprintf("%s %s from %s conflicts with %s; the one from %s is served", c.Kind, c.Name, c.Upstream, c.KeptFrom, c.KeptFrom)
+}
+
+// UpstreamSummary counts what one upstream contributes to the merged toolset
+type UpstreamSummary struct {
+	Name      string `json:"name"`
+	Prefix    string `json:"prefix"`
+	Tools     int    `json:"tools"`
+	Resources int    `json:"resources"`
+}
+
+// Report describes a merge: what each upstream contributed and what was left out
+type Report struct {
+	Upstreams []UpstreamSummary `json:"upstreams"`
+	Conflicts []Conflict        `json:"conflicts"`
+}
+
+// Toolset is the merged result of several upstreams
+type Toolset struct {
+	Tools []mcp.Tool
+	// Resources is nil when no upstream serves resources
+	Resources mcp.ResourceProvider
+	Report    Report
+}
+
+// Merge prefixes the tools and resources of each upstream and merges them in order; on a name
+// or URI conflict the earlier upstream's entry is kept and the conflict reported
+func Merge(upstreams []Upstream) *Toolset {
+	merged := &Toolset{
+		Tools: make([]mcp.Tool, 0),
+		Report: Report{
+			Upstreams: make([]UpstreamSummary, 0, len(upstreams)),
+			Conflicts: make([]Conflict, 0),
+		},
+	}
+	toolOwners := make(map[string]string)
+	resourceOwners := make(map[string]string)
+	resources := &resourceSet{providers: make(map[string]mcp.ResourceProvider)}
+
+	for _, upstream := range upstreams {
+		summary := UpstreamSummary{Name: upstream.Name, Prefix: upstream.Prefix}
+
+		for _, tool := range upstream.Tools {
+			tool.Name = upstream.Prefix + tool.Name
+			if owner, exists := toolOwners[tool.Name]; exists {
+				merged.Report.Conflicts = append(merged.Report.Conflicts, Conflict{
+					Kind: KindTool, Name: tool.Name, Upstream: upstream.Name, KeptFrom: owner,
+				})
+				continue
+			}
+			toolOwners[tool.Name] = upstream.Name
+			merged.Tools = append(merged.Tools, tool)
+			summary.Tools++
+		}
+
+		if upstream.Resources != nil {
+			for _, resource := range upstream.Resources.Resources() {
+				resource.Name = upstream.Prefix + resource.Name
+				if owner, exists := resourceOwners[resource.URI]; exists {
+					merged.Report.Conflicts = append(merged.Report.Conflicts, Conflict{
+						Kind: KindResource, Name: resource.URI, Upstream: upstream.Name, KeptFrom: owner,
+					})
+					continue
+				}
+				resourceOwners[resource.URI] = upstream.Name
+				resources.resources = append(resources.resources, resource)
+				resources.providers[resource.URI] = upstream.Resources
+				summary.Resources++
+			}
+		}
+
+		merged.Report.Upstreams = append(merged.Report.Upstreams, summary)
+	}
+
+	if len(resources.resources) > 0 {
+		merged.Resources = resources
+	}
+	return merged
+}
+
+// resourceSet serves the resources of several upstreams, routing each URI to its provider
+type resourceSet struct {
+	resources []mcp.Resource
+	providers map[string]mcp.ResourceProvider
+}
+
+// Resources returns the merged resources with prefixed names
+func (s *resourceSet) Resources() []mcp.Resource {
+	return s.resources
+}
+
+// ReadResource reads a resource from the upstream serving it
+func (s *resourceSet) ReadResource(ctx context.Context, uri string) (*mcp.ResourceContents, error) {
+	provider, err := s.provider(uri)
+	if err != nil {
+		return nil, err
+	}
+	return provider.ReadResource(ctx, uri)
+}
+
+// Subscribe subscribes to a resource of the upstream serving it
+func (s *resourceSet) Subscribe(ctx context.Context, uri string) error {
+	provider, err := s.provider(uri)
+	if err != nil {
+		return err
+	}
+	return provider.Subscribe(ctx, uri)
+}
+
+// Unsubscribe unsubscribes from a resource of the upstream serving it
+func (s *resourceSet) Unsubscribe(uri string) error {
+	provider, err := s.provider(uri)
+	if err != nil {
+		return err
+	}
+	return provider.Unsubscribe(uri)
+}
+
+func (s *resourceSet) provider(uri string) (mcp.ResourceProvider, error) {
+	provider, exists := s.providers[uri]
+	if !exists {
+		return nil, fmt.Errorf("resource not found: %s", uri)
+	}
+	return provider, nil
+}
@@ -0,0 +1,129 @@
+package aggregate
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tool(name string) mcp.Tool {
+	return mcp.Tool{
+		Name: name,
+		Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return name, nil
+		},
+	}
+}
+
+// channels serves a fixed set of resources, recording subscriptions
+type channels struct {
+	resources  []mcp.Resource
+	subscribed []string
+}
+
+func (c *channels) Resources() []mcp.Resource {
+	return c.resources
+}
+
+func (c *channels) ReadResource(ctx context.Context, uri string) (*mcp.ResourceContents, error) {
+	for _, resource := range c.resources {
+		if resource.URI == uri {
+			return &mcp.ResourceContents{URI: uri, Text: resource.Name}, nil
+		}
+	}
+	return nil, fmt.Errorf("resource not found: %s", uri)
+}
+
+func (c *channels) Subscribe(ctx context.Context, uri string) error {
+	c.subscribed = append(c.subscribed, uri)
+	return nil
+}
+
+func (c *channels) Unsubscribe(uri string) error {
+	return nil
+}
+
+func TestMerge_PrefixesTools(t *testing.T) {
+	merged := Merge([]Upstream{
+		{Name: "github", Prefix: "github_", Tools: []mcp.Tool{tool("list_issues"), tool("get_repo")}},
+		{Name: "jira", Prefix: "jira_", Tools: []mcp.Tool{tool("list_issues")}},
+	})
+
+	names := make([]string, len(merged.Tools))
+	for i, tool := range merged.Tools {
+		names[i] = tool.Name
+	}
+	assert.Equal(t, []string{"github_list_issues", "github_get_repo", "jira_list_issues"}, names)
+	assert.Nil(t, merged.Resources)
+	assert.Empty(t, merged.Report.Conflicts)
+	assert.Equal(t, []UpstreamSummary{
+		{Name: "github", Prefix: "github_", Tools: 2},
+		{Name: "jira", Prefix: "jira_", Tools: 1},
+	}, merged.Report.Upstreams)
+
+	// Handlers still belong to their upstream's tool
+	result, err := merged.Tools[2].Handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "list_issues", result)
+}
+
+func TestMerge_Conflicts(t *testing.T) {
+	merged := Merge([]Upstream{
+		{Name: "a", Prefix: "x_", Tools: []mcp.Tool{tool("b_list"), tool("get"), tool("get")}},
+		{Name: "b", Prefix: "x_b_", Tools: []mcp.Tool{tool("list"), tool("create")}},
+	})
+
+	assert.Len(t, merged.Tools, 3)
+	assert.Equal(t, []Conflict{
+		{Kind: KindTool, Name: "x_get", Upstream: "a", KeptFrom: "a"},
+		{Kind: KindTool, Name: "x_b_list", Upstream: "b", KeptFrom: "a"},
+	}, merged.Report.Conflicts)
+	assert.Equal(t, 2, merged.Report.Upstreams[0].Tools)
+	assert.Equal(t, 1, merged.Report.Upstreams[1].Tools)
+
+	assert.Equal(t, "tool x_get is generated more than once by a; the first is served", merged.Report.Conflicts[0].String())
+	assert.Equal(t, "tool x_b_list from b conflicts with a; the one from a is served", merged.Report.Conflicts[1].String())
+}
+
+func TestMerge_Resources(t *testing.T) {
+	orders := &channels{resources: []mcp.Resource{
+		{URI: "asyncapi://orders/created", Name: "created"},
+		{URI: "asyncapi://shared/status", Name: "status"},
+	}}
+	billing := &channels{resources: []mcp.Resource{
+		{URI: "asyncapi://billing/paid", Name: "paid"},
+		{URI: "asyncapi://shared/status", Name: "status"},
+	}}
+	merged := Merge([]Upstream{
+		{Name: "orders", Prefix: "orders_", Resources: orders},
+		{Name: "crm", Prefix: "crm_", Tools: []mcp.Tool{tool("find")}},
+		{Name: "billing", Prefix: "billing_", Resources: billing},
+	})
+
+	require.NotNil(t, merged.Resources)
+	names := make([]string, 0)
+	for _, resource := range merged.Resources.Resources() {
+		names = append(names, resource.Name)
+	}
+	assert.Equal(t, []string{"orders_created", "orders_status", "billing_paid"}, names)
+	assert.Equal(t, []Conflict{
+		{Kind: KindResource, Name: "asyncapi://shared/status", Upstream: "billing", KeptFrom: "orders"},
+	}, merged.Report.Conflicts)
+
+	// Reads and subscriptions go to the upstream serving the URI
+	contents, err := merged.Resources.ReadResource(context.Background(), "asyncapi://billing/paid")
+	require.NoError(t, err)
+	assert.Equal(t, "paid", contents.Text)
+	require.NoError(t, merged.Resources.Subscribe(context.Background(), "asyncapi://shared/status"))
+	assert.Equal(t, []string{"asyncapi://shared/status"}, orders.subscribed)
+	assert.Empty(t, billing.subscribed)
+
+	_, err = merged.Resources.ReadResource(context.Background(), "asyncapi://missing")
+	assert.EqualError(t, err, "resource not found: asyncapi://missing")
+	assert.Error(t, merged.Resources.Unsubscribe("asyncapi://missing"))
+}
@@ -0,0 +1,471 @@
+package asyncapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/generator"
+	"api-to-mcp/internal/redact"
+	"api-to-mcp/internal/secrets"
+	"api-to-mcp/pkg/mcp"
+	"api-to-mcp/pkg/openapi"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ResourcePrefix prefixes the URIs of channel resources, followed by the channel address
+const ResourcePrefix = "asyncapi://channels/"
+
+// defaultBufferSize applies when buffer_size is unset, as for tenants
+const defaultBufferSize = 100
+
+// Publish tools and channel resources are classified like REST operations so filters, read-only
+// mode and the other safety layers apply: publishing is a POST and reading a channel is a GET
+const (
+	publishMethod = "POST"
+	readMethod    = "GET"
+)
+
+// identifierPattern matches runs of characters not allowed in generated operation IDs
+var identifierPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Bridge exposes an AsyncAPI document's publish operations as tools and its subscribe channels
+// as resources backed by a broker
+type Bridge struct {
+	doc      *Document
+	server   Server
+	config   *config.Config
+	logger   *logrus.Logger
+	broker   Broker
+	redactor *redact.Redactor
+
+	tools     []mcp.Tool
+	resources []mcp.Resource
+	channels  map[string]*channel
+}
+
+// channel buffers the recent messages of a subscribed channel
+type channel struct {
+	pattern string
+	size    int
+
+	// subscribeMu serializes subscription changes; mu guards the buffer, which brokers fill
+	// from their own goroutines while a subscription may be in progress
+	subscribeMu sync.Mutex
+	subscribed  bool
+	mu          sync.Mutex
+	messages    []bufferedMessage
+}
+
+// bufferedMessage is a received message as returned by resource reads
+type bufferedMessage struct {
+	Channel    string      `json:"channel"`
+	ReceivedAt time.Time   `json:"receivedAt"`
+	Payload    interface{} `json:"payload"`
+}
+
+// NewBridge loads the AsyncAPI document at spec_path, selects its server and generates tools
+// and resources. The broker is not contacted until a tool is called or a channel is read
+func NewBridge(cfg *config.Config, logger *logrus.Logger) (*Bridge, error) {
+	doc, err := Load(cfg.OpenAPI.SpecPath)
+	if err != nil {
+		return nil, err
+	}
+	server, err := selectServer(doc, cfg.OpenAPI.AsyncAPI)
+	if err != nil {
+		return nil, err
+	}
+	broker, err := NewBroker(server, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	redactor, err := generator.NewResponseRedactor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bridge{
+		doc:      doc,
+		server:   server,
+		config:   cfg,
+		logger:   logger,
+		broker:   broker,
+		redactor: redactor,
+		channels: make(map[string]*channel),
+	}
+	if b.tools, err = b.generateTools(); err != nil {
+		return nil, err
+	}
+	b.generateResources()
+
+	logger.WithFields(logrus.Fields{
+		"server":         server.Name,
+		"protocol":       server.Protocol,
+		"tool_count":     len(b.tools),
+		"resource_count": len(b.resources),
+	}).Info("Generated MCP tools and resources from AsyncAPI document")
+	if len(b.tools) == 0 && len(b.resources) == 0 {
+		return nil, fmt.Errorf("no tools or resources could be generated: no operations passed the filters")
+	}
+	return b, nil
+}
+
+// selectServer picks the configured server, or the first one, applying the URL override
+func selectServer(doc *Document, settings config.AsyncAPIConfig) (Server, error) {
+	var server Server
+	switch {
+	case settings.Server != "":
+		names := make([]string, 0, len(doc.Servers))
+		for _, candidate := range doc.Servers {
+			if candidate.Name == settings.Server {
+				server = candidate
+			}
+			names = append(names, candidate.Name)
+		}
+		if server.Name == "" {
+			return server, fmt.Errorf("AsyncAPI server %s not found (available: %s)", settings.Server, strings.Join(names, ", "))
+		}
+	case len(doc.Servers) > 0:
+		server = doc.Servers[0]
+	case settings.URL == "":
+		return server, fmt.Errorf("the AsyncAPI document declares no servers; set openapi.asyncapi.url")
+	default:
+		server.Name = "default"
+	}
+
+	if settings.URL != "" {
+		server.URL = settings.URL
+		if i := strings.Index(settings.URL, "://"); i > 0 {
+			server.Protocol = settings.URL[:i]
+		}
+	}
+	return server, nil
+}
+
+// Tools returns the publish tools
+func (b *Bridge) Tools() []mcp.Tool {
+	return b.tools
+}
+
+// generateTools generates a tool per publish operation and message through the OpenAPI tool
+// generator, then binds each tool to the broker
+func (b *Bridge) generateTools() ([]mcp.Tool, error) {
+	// The generator requires a base URL; tools record the broker's instead
+	scoped := *b.config
+	scoped.OpenAPI.BaseURL = b.server.URL
+	spec := &openapi.ParsedSpec{
+		Info: openapi.Info{Title: b.doc.Title, Version: b.doc.Version, Description: b.doc.Description},
+	}
+	gen := generator.NewMCPToolGenerator(spec, &scoped, b.logger)
+
+	type binding struct {
+		operation Operation
+		message   Message
+	}
+	bindings := make(map[string]binding)
+	for _, operation := range b.doc.Operations {
+		if operation.Action != ActionPublish {
+			continue
+		}
+		messages := operation.Messages
+		if len(messages) == 0 {
+			messages = []Message{{}}
+		}
+		for _, message := range messages {
+			endpoint := publishEndpoint(operation, message, len(messages) > 1)
+			if !gen.IsEndpointIncluded(endpoint) {
+				b.logger.WithField("channel", operation.Channel).Debug("Skipping filtered publish operation")
+				continue
+			}
+			spec.Endpoints = append(spec.Endpoints, endpoint)
+			bindings[endpoint.OperationID] = binding{operation: operation, message: message}
+		}
+	}
+	if len(spec.Endpoints) == 0 {
+		return nil, nil
+	}
+
+	tools, err := gen.GenerateTools()
+	if err != nil {
+		return nil, err
+	}
+	for i := range tools {
+		bound := bindings[tools[i].Operation.OperationID]
+		tools[i].Handler = b.publisher(bound.operation, bound.message)
+		tools[i].Operation.BaseURL = secrets.ScrubURL(b.server.URL)
+	}
+	return tools, nil
+}
+
+// publishEndpoint describes a publish operation as an endpoint: channel parameters become path
+// parameters and the message payload becomes the request body
+func publishEndpoint(operation Operation, message Message, perMessage bool) openapi.Endpoint {
+	id := operation.ID
+	if id == "" {
+		id = "publish_" + identifier(operation.Channel)
+	}
+	if perMessage && message.Name != "" {
+		id += "_" + identifier(message.Name)
+	}
+
+	endpoint := openapi.Endpoint{
+		Path:        "/" + strings.TrimPrefix(operation.Channel, "/"),
+		Method:      publishMethod,
+		OperationID: id,
+		Summary:     firstNonEmpty(operation.Summary, message.Summary),
+		Description: firstNonEmpty(operation.Description, fmt.Sprintf("Publish a message to %s", operation.Channel)),
+	}
+	for _, parameter := range operation.Parameters {
+		endpoint.Parameters = append(endpoint.Parameters, openapi.Parameter{
+			Name:        parameter.Name,
+			In:          "path",
+			Description: parameter.Description,
+			Required:    true,
+			Schema:      parameter.Schema,
+		})
+	}
+
+	payload := openapi.Schema{Type: "string", Description: "Raw message payload"}
+	if message.Payload != nil {
+		payload = *message.Payload
+	}
+	endpoint.RequestBody = &openapi.RequestBody{
+		Required: true,
+		Content:  map[string]openapi.MediaType{"application/json": {Schema: payload}},
+	}
+	return endpoint
+}
+
+// identifier converts a channel address or message name to a snake_case identifier
+func identifier(name string) string {
+	return strings.Trim(identifierPattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
+
+// publisher creates the handler publishing a message built from tool arguments. Object payloads
+// are the arguments other than channel parameters; other payloads are the value argument
+func (b *Bridge) publisher(operation Operation, message Message) mcp.ToolHandler {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		address, err := expandChannel(operation, params)
+		if err != nil {
+			return nil, err
+		}
+
+		fields := make(map[string]interface{}, len(params))
+		for key, value := range params {
+			fields[key] = value
+		}
+		for _, parameter := range operation.Parameters {
+			delete(fields, parameter.Name)
+		}
+
+		var data []byte
+		switch {
+		case message.Payload == nil:
+			if raw, ok := fields["value"].(string); ok {
+				data = []byte(raw)
+			} else {
+				data, err = json.Marshal(fields["value"])
+			}
+		case message.Payload.Type == "object":
+			data, err = json.Marshal(fields)
+		default:
+			data, err = json.Marshal(fields["value"])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode message: %w", err)
+		}
+
+		if err := b.broker.Publish(ctx, address, data); err != nil {
+			return nil, fmt.Errorf("publish to %s failed: %w", address, err)
+		}
+		b.logger.WithFields(logrus.Fields{"channel": address, "size": len(data)}).Debug("Published message")
+		return map[string]interface{}{"published": true, "channel": address}, nil
+	}
+}
+
+// expandChannel fills a channel's parameters from tool arguments; values may not contain
+// separators or wildcards, so a call cannot reach another channel
+func expandChannel(operation Operation, params map[string]interface{}) (string, error) {
+	var expandErr error
+	address := channelParamPattern.ReplaceAllStringFunc(operation.Channel, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		value, exists := params[name]
+		if !exists {
+			expandErr = fmt.Errorf("missing channel parameter %s", name)
+			return placeholder
+		}
+		s := fmt.Sprintf("%v", value)
+		if s == "" || strings.ContainsAny(s, "/+#") {
+			expandErr = fmt.Errorf("invalid channel parameter %s: must be non-empty without '/', '+' or '#'", name)
+			return placeholder
+		}
+		return s
+	})
+	if expandErr != nil {
+		return "", expandErr
+	}
+	return address, nil
+}
+
+// generateResources creates a resource per subscribe channel that passes the filters
+func (b *Bridge) generateResources() {
+	gen := generator.NewMCPToolGenerator(nil, b.config, b.logger)
+	size := b.config.OpenAPI.AsyncAPI.BufferSize
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+
+	for _, operation := range b.doc.Operations {
+		if operation.Action != ActionSubscribe {
+			continue
+		}
+		endpoint := openapi.Endpoint{Method: readMethod, Path: "/" + strings.TrimPrefix(operation.Channel, "/")}
+		if !gen.IsEndpointIncluded(endpoint) {
+			b.logger.WithField("channel", operation.Channel).Debug("Skipping filtered subscribe operation")
+			continue
+		}
+		uri := ResourcePrefix + strings.TrimPrefix(operation.Channel, "/")
+		if _, exists := b.channels[uri]; exists {
+			continue
+		}
+
+		description := firstNonEmpty(operation.Summary, operation.Description, fmt.Sprintf("Messages received on %s", operation.Channel))
+		names := make([]string, 0, len(operation.Messages))
+		for _, message := range operation.Messages {
+			if message.Name != "" {
+				names = append(names, message.Name)
+			}
+		}
+		if len(names) > 0 {
+			description = fmt.Sprintf("%s (messages: %s)", description, strings.Join(names, ", "))
+		}
+
+		b.channels[uri] = &channel{pattern: strings.TrimPrefix(operation.Channel, "/"), size: size}
+		b.resources = append(b.resources, mcp.Resource{
+			URI:         uri,
+			Name:        firstNonEmpty(operation.ID, operation.Channel),
+			Description: description,
+			MimeType:    "application/json",
+		})
+	}
+}
+
+// Resources returns the channel resources
+func (b *Bridge) Resources() []mcp.Resource {
+	return b.resources
+}
+
+// ReadResource returns the buffered messages of a channel, subscribing on first read so clients
+// that only poll still receive messages
+func (b *Bridge) ReadResource(ctx context.Context, uri string) (*mcp.ResourceContents, error) {
+	ch, exists := b.channels[uri]
+	if !exists {
+		return nil, fmt.Errorf("resource not found: %s", uri)
+	}
+	if err := b.Subscribe(ctx, uri); err != nil {
+		return nil, err
+	}
+
+	ch.mu.Lock()
+	messages := make([]interface{}, len(ch.messages))
+	for i, message := range ch.messages {
+		messages[i] = message
+	}
+	ch.mu.Unlock()
+
+	var value interface{} = map[string]interface{}{"channel": ch.pattern, "messages": messages}
+	if b.redactor != nil {
+		// Round-trip through JSON so the redactor sees plain maps
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		value = b.redactor.Value(value)
+	}
+	text, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode messages: %w", err)
+	}
+	return &mcp.ResourceContents{URI: uri, MimeType: "application/json", Text: string(text)}, nil
+}
+
+// Subscribe starts buffering a channel's messages
+func (b *Bridge) Subscribe(ctx context.Context, uri string) error {
+	ch, exists := b.channels[uri]
+	if !exists {
+		return fmt.Errorf("resource not found: %s", uri)
+	}
+	ch.subscribeMu.Lock()
+	defer ch.subscribeMu.Unlock()
+	if ch.subscribed {
+		return nil
+	}
+	if err := b.broker.Subscribe(ctx, ch.pattern, ch.add); err != nil {
+		return fmt.Errorf("subscribe to %s failed: %w", ch.pattern, err)
+	}
+	ch.subscribed = true
+	b.logger.WithField("channel", ch.pattern).Info("Subscribed to AsyncAPI channel")
+	return nil
+}
+
+// Unsubscribe stops buffering a channel's messages; messages already buffered are kept
+func (b *Bridge) Unsubscribe(uri string) error {
+	ch, exists := b.channels[uri]
+	if !exists {
+		return fmt.Errorf("resource not found: %s", uri)
+	}
+	ch.subscribeMu.Lock()
+	defer ch.subscribeMu.Unlock()
+	if !ch.subscribed {
+		return nil
+	}
+	if err := b.broker.Unsubscribe(ch.pattern); err != nil {
+		return fmt.Errorf("unsubscribe from %s failed: %w", ch.pattern, err)
+	}
+	ch.subscribed = false
+	return nil
+}
+
+// add buffers a delivery, dropping the oldest message when full
+func (ch *channel) add(delivery Delivery) {
+	var payload interface{}
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		payload = string(delivery.Payload)
+	}
+
+	ch.mu.Lock()
+	defer ch.mu.Unlock()
+	ch.messages = append(ch.messages, bufferedMessage{
+		Channel:    delivery.Channel,
+		ReceivedAt: delivery.ReceivedAt,
+		Payload:    payload,
+	})
+	if len(ch.messages) > ch.size {
+		ch.messages = ch.messages[len(ch.messages)-ch.size:]
+	}
+}
+
+// ServeHTTP passes inbound webhook deliveries to brokers that receive them
+func (b *Bridge) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	receiver, ok := b.broker.(http.Handler)
+	if !ok {
+		http.Error(w, "this namespace does not receive webhooks", http.StatusNotFound)
+		return
+	}
+	receiver.ServeHTTP(w, r)
+}
+
+// Close disconnects from the broker
+func (b *Bridge) Close() error {
+	return b.broker.Close()
+}
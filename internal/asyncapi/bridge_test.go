@@ -0,0 +1,261 @@
+package asyncapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/secrets"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exampleDocument = "../../examples/notifications.asyncapi.yaml"
+
+// received records requests made to a webhook upstream
+type received struct {
+	mu       sync.Mutex
+	requests map[string]string
+}
+
+func webhookUpstream(t *testing.T) (*httptest.Server, *received) {
+	t.Helper()
+	rec := &received{requests: make(map[string]string)}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		rec.requests[r.Method+" "+r.URL.Path] = string(body)
+		rec.mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream, rec
+}
+
+func testConfig(url string) *config.Config {
+	cfg := &config.Config{}
+	cfg.OpenAPI.SpecPath = exampleDocument
+	cfg.OpenAPI.AsyncAPI = config.AsyncAPIConfig{Server: "webhooks", URL: url}
+	cfg.Safety.Outbound.AllowPrivate = true
+	return cfg
+}
+
+func findTool(tools []mcp.Tool, name string) *mcp.Tool {
+	for i := range tools {
+		if tools[i].Name == name {
+			return &tools[i]
+		}
+	}
+	return nil
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestNewBridge_Webhook(t *testing.T) {
+	upstream, rec := webhookUpstream(t)
+	bridge, err := NewBridge(testConfig(upstream.URL+"/events"), logrus.New())
+	require.NoError(t, err)
+	defer bridge.Close()
+
+	tools := bridge.Tools()
+	require.Len(t, tools, 2)
+
+	command := findTool(tools, "sendcommand")
+	require.NotNil(t, command)
+	assert.Equal(t, "POST", command.Operation.Method)
+	assert.Equal(t, "/devices/{deviceId}/commands", command.Operation.Path)
+	assert.ElementsMatch(t, []string{"deviceId", "action"}, command.InputSchema.Required)
+
+	result, err := command.Handler(context.Background(), map[string]interface{}{
+		"deviceId":     "d1",
+		"action":       "reboot",
+		"delaySeconds": 5,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"published": true, "channel": "devices/d1/commands"}, result)
+	assert.JSONEq(t, `{"action":"reboot","delaySeconds":5}`, rec.requests["POST /events/devices/d1/commands"])
+
+	announce := findTool(tools, "publish_announcements")
+	require.NotNil(t, announce)
+	_, err = announce.Handler(context.Background(), map[string]interface{}{"value": "Maintenance at noon"})
+	require.NoError(t, err)
+	assert.Equal(t, `"Maintenance at noon"`, rec.requests["POST /events/announcements"])
+}
+
+func TestPublish_RejectsChannelInjection(t *testing.T) {
+	upstream, rec := webhookUpstream(t)
+	bridge, err := NewBridge(testConfig(upstream.URL), logrus.New())
+	require.NoError(t, err)
+
+	command := findTool(bridge.Tools(), "sendcommand")
+	require.NotNil(t, command)
+	for _, deviceID := range []string{"d1/../admin", "+", "#", ""} {
+		_, err := command.Handler(context.Background(), map[string]interface{}{"deviceId": deviceID, "action": "reboot"})
+		assert.ErrorContains(t, err, "invalid channel parameter deviceId")
+	}
+	assert.Empty(t, rec.requests)
+}
+
+func TestResources_WebhookDelivery(t *testing.T) {
+	upstream, _ := webhookUpstream(t)
+	cfg := testConfig(upstream.URL)
+	cfg.OpenAPI.AsyncAPI.BufferSize = 2
+	cfg.OpenAPI.AsyncAPI.WebhookSecret = secrets.New("s3cret")
+	bridge, err := NewBridge(cfg, logrus.New())
+	require.NoError(t, err)
+
+	resources := bridge.Resources()
+	require.Len(t, resources, 1)
+	uri := ResourcePrefix + "devices/{deviceId}/telemetry"
+	assert.Equal(t, uri, resources[0].URI)
+	assert.Equal(t, "deviceTelemetry", resources[0].Name)
+	assert.Contains(t, resources[0].Description, "messages: Reading, Alert")
+
+	deliver := func(path, body, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if signature != "" {
+			req.Header.Set(SignatureHeader, signature)
+		}
+		w := httptest.NewRecorder()
+		bridge.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Deliveries are refused until the channel is subscribed
+	body := `{"temperature":21.5}`
+	assert.Equal(t, http.StatusNotFound, deliver("/devices/d1/telemetry", body, sign("s3cret", body)))
+	require.NoError(t, bridge.Subscribe(context.Background(), uri))
+
+	assert.Equal(t, http.StatusUnauthorized, deliver("/devices/d1/telemetry", body, ""))
+	assert.Equal(t, http.StatusUnauthorized, deliver("/devices/d1/telemetry", body, sign("wrong", body)))
+	assert.Equal(t, http.StatusNotFound, deliver("/devices/d1/commands", body, sign("s3cret", body)))
+	for _, temperature := range []string{"20", "21", "22"} {
+		body := `{"temperature":` + temperature + `}`
+		assert.Equal(t, http.StatusAccepted, deliver("/devices/d1/telemetry", body, sign("s3cret", body)))
+	}
+
+	contents, err := bridge.ReadResource(context.Background(), uri)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json", contents.MimeType)
+	var read struct {
+		Channel  string `json:"channel"`
+		Messages []struct {
+			Channel string                 `json:"channel"`
+			Payload map[string]interface{} `json:"payload"`
+		} `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(contents.Text), &read))
+	assert.Equal(t, "devices/{deviceId}/telemetry", read.Channel)
+	require.Len(t, read.Messages, 2, "the buffer keeps the newest messages")
+	assert.Equal(t, "devices/d1/telemetry", read.Messages[0].Channel)
+	assert.Equal(t, 21.0, read.Messages[0].Payload["temperature"])
+	assert.Equal(t, 22.0, read.Messages[1].Payload["temperature"])
+
+	require.NoError(t, bridge.Unsubscribe(uri))
+	assert.Equal(t, http.StatusNotFound, deliver("/devices/d1/telemetry", body, sign("s3cret", body)))
+
+	_, err = bridge.ReadResource(context.Background(), ResourcePrefix+"unknown")
+	assert.ErrorContains(t, err, "resource not found")
+}
+
+func TestNewBridge_Filters(t *testing.T) {
+	upstream, _ := webhookUpstream(t)
+	cfg := testConfig(upstream.URL)
+	cfg.Filters.IncludeMethods = []string{"GET"}
+	bridge, err := NewBridge(cfg, logrus.New())
+	require.NoError(t, err)
+	assert.Empty(t, bridge.Tools())
+	assert.Len(t, bridge.Resources(), 1)
+
+	cfg.Filters.IncludeMethods = nil
+	cfg.Filters.ExcludePaths = []string{"/devices", "/announcements"}
+	_, err = NewBridge(cfg, logrus.New())
+	assert.ErrorContains(t, err, "no tools or resources")
+}
+
+func TestNewBridge_Servers(t *testing.T) {
+	cfg := testConfig("")
+	cfg.OpenAPI.AsyncAPI.Server = "staging"
+	_, err := NewBridge(cfg, logrus.New())
+	assert.ErrorContains(t, err, "AsyncAPI server staging not found (available: production, webhooks)")
+
+	cfg.OpenAPI.AsyncAPI.Server = ""
+	cfg.OpenAPI.AsyncAPI.URL = "amqp://broker.example.com"
+	_, err = NewBridge(cfg, logrus.New())
+	assert.ErrorContains(t, err, `unsupported AsyncAPI protocol "amqp"`)
+}
+
+// fakeBroker records publishes and hands out subscriptions
+type fakeBroker struct {
+	published map[string][]byte
+	delivers  map[string]func(Delivery)
+}
+
+func (b *fakeBroker) Publish(_ context.Context, channel string, payload []byte) error {
+	b.published[channel] = payload
+	return nil
+}
+
+func (b *fakeBroker) Subscribe(_ context.Context, pattern string, deliver func(Delivery)) error {
+	b.delivers[pattern] = deliver
+	return nil
+}
+
+func (b *fakeBroker) Unsubscribe(pattern string) error {
+	delete(b.delivers, pattern)
+	return nil
+}
+
+func (b *fakeBroker) Close() error {
+	return nil
+}
+
+func TestRegisterBroker(t *testing.T) {
+	broker := &fakeBroker{published: make(map[string][]byte), delivers: make(map[string]func(Delivery))}
+	RegisterBroker("FAKE", func(server Server, cfg *config.Config, logger *logrus.Logger) (Broker, error) {
+		assert.Equal(t, "fake://broker", server.URL)
+		return broker, nil
+	})
+
+	cfg := testConfig("fake://broker")
+	bridge, err := NewBridge(cfg, logrus.New())
+	require.NoError(t, err)
+
+	command := findTool(bridge.Tools(), "sendcommand")
+	require.NotNil(t, command)
+	assert.Equal(t, "fake://broker", command.Operation.BaseURL)
+	_, err = command.Handler(context.Background(), map[string]interface{}{"deviceId": "d7", "action": "locate"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"action":"locate"}`, string(broker.published["devices/d7/commands"]))
+
+	uri := ResourcePrefix + "devices/{deviceId}/telemetry"
+	_, err = bridge.ReadResource(context.Background(), uri)
+	require.NoError(t, err)
+	require.Contains(t, broker.delivers, "devices/{deviceId}/telemetry")
+	broker.delivers["devices/{deviceId}/telemetry"](Delivery{Channel: "devices/d7/telemetry", Payload: []byte("not json")})
+
+	contents, err := bridge.ReadResource(context.Background(), uri)
+	require.NoError(t, err)
+	assert.Contains(t, contents.Text, `"payload":"not json"`)
+
+	req := httptest.NewRequest(http.MethodPost, "/devices/d7/telemetry", nil)
+	w := httptest.NewRecorder()
+	bridge.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code, "brokers without a webhook receiver refuse deliveries")
+}
@@ -0,0 +1,101 @@
+package asyncapi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"api-to-mcp/internal/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Delivery is a message received from a channel
+type Delivery struct {
+	// Channel is the concrete channel the message arrived on, with parameters filled in
+	Channel    string
+	Payload    []byte
+	ReceivedAt time.Time
+}
+
+// Broker sends and receives messages on the channels of an AsyncAPI server. Channel patterns
+// passed to Subscribe keep their {name} placeholders, which match any single segment
+type Broker interface {
+	Publish(ctx context.Context, channel string, payload []byte) error
+	Subscribe(ctx context.Context, pattern string, deliver func(Delivery)) error
+	Unsubscribe(pattern string) error
+	Close() error
+}
+
+// BrokerFactory creates the broker for a server. Factories should not connect until the broker
+// is first used, so listing tools never touches the broker
+type BrokerFactory func(server Server, cfg *config.Config, logger *logrus.Logger) (Broker, error)
+
+var (
+	brokersMu sync.RWMutex
+	brokers   = map[string]BrokerFactory{
+		"http":  newWebhookBroker,
+		"https": newWebhookBroker,
+		"mqtt":  newMQTTBroker,
+		"mqtts": newMQTTBroker,
+		// AsyncAPI 2.x names MQTT over TLS secure-mqtt
+		"secure-mqtt": newMQTTBroker,
+	}
+)
+
+// RegisterBroker makes a broker available for a server protocol, replacing any existing one
+func RegisterBroker(protocol string, factory BrokerFactory) {
+	brokersMu.Lock()
+	defer brokersMu.Unlock()
+	brokers[strings.ToLower(protocol)] = factory
+}
+
+// NewBroker creates the broker registered for a server's protocol
+func NewBroker(server Server, cfg *config.Config, logger *logrus.Logger) (Broker, error) {
+	protocol := strings.ToLower(server.Protocol)
+	if protocol == "" {
+		if i := strings.Index(server.URL, "://"); i > 0 {
+			protocol = strings.ToLower(server.URL[:i])
+		}
+	}
+
+	brokersMu.RLock()
+	factory, exists := brokers[protocol]
+	supported := make([]string, 0, len(brokers))
+	for name := range brokers {
+		supported = append(supported, name)
+	}
+	brokersMu.RUnlock()
+
+	if !exists {
+		sort.Strings(supported)
+		return nil, fmt.Errorf("unsupported AsyncAPI protocol %q for server %s (supported: %s)",
+			protocol, server.Name, strings.Join(supported, ", "))
+	}
+	return factory(server, cfg, logger)
+}
+
+// matchChannel reports whether a concrete channel matches a pattern whose {name} placeholders
+// each match one /-separated segment
+func matchChannel(pattern, channel string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	channelSegments := strings.Split(strings.Trim(channel, "/"), "/")
+	if len(patternSegments) != len(channelSegments) {
+		return false
+	}
+	for i, segment := range patternSegments {
+		if channelParamPattern.MatchString(segment) {
+			if channelSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != channelSegments[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,534 @@
+package asyncapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"api-to-mcp/pkg/openapi"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Actions describe what the bridge does on a channel
+const (
+	// ActionPublish operations send messages to the channel and become tools
+	ActionPublish = "publish"
+	// ActionSubscribe operations receive messages from the channel and become resources
+	ActionSubscribe = "subscribe"
+)
+
+// channelParamPattern matches {name} placeholders in channel addresses
+var channelParamPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// Document is an AsyncAPI 2.x or 3.x document normalized to the operations the bridge performs
+type Document struct {
+	Version     string
+	Title       string
+	Description string
+	// Servers are in declaration order
+	Servers    []Server
+	Operations []Operation
+}
+
+// Server is a broker or endpoint the document's channels live on
+type Server struct {
+	Name        string
+	URL         string
+	Protocol    string
+	Description string
+}
+
+// Operation is a publish or subscribe operation on a channel
+type Operation struct {
+	ID          string
+	Action      string
+	Channel     string
+	Summary     string
+	Description string
+	Parameters  []Parameter
+	Messages    []Message
+}
+
+// Parameter is a channel address parameter
+type Parameter struct {
+	Name        string
+	Description string
+	Schema      openapi.Schema
+}
+
+// Message is a message an operation carries
+type Message struct {
+	Name        string
+	Summary     string
+	ContentType string
+	// Payload is nil when the payload is not described by a JSON schema
+	Payload *openapi.Schema
+}
+
+// IsDocument reports whether a file is an AsyncAPI document, identified by its asyncapi field
+func IsDocument(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	var header struct {
+		AsyncAPI string `yaml:"asyncapi"`
+	}
+	return yaml.Unmarshal(data, &header) == nil && header.AsyncAPI != ""
+}
+
+// Load reads and normalizes an AsyncAPI document in YAML or JSON
+func Load(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read AsyncAPI document: %w", err)
+	}
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AsyncAPI document %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// Parse normalizes an AsyncAPI document in YAML or JSON
+func Parse(data []byte) (*Document, error) {
+	var root map[string]interface{}
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	version, _ := root["asyncapi"].(string)
+	if version == "" {
+		return nil, fmt.Errorf("missing asyncapi version field")
+	}
+
+	r := &resolver{root: root, serverOrder: declaredKeys(data, "servers")}
+	doc := &Document{Version: version}
+	info := asMap(root["info"])
+	doc.Title = asString(info["title"])
+	doc.Description = asString(info["description"])
+
+	var err error
+	switch {
+	case strings.HasPrefix(version, "2."):
+		err = r.parseV2(doc)
+	case strings.HasPrefix(version, "3."):
+		err = r.parseV3(doc)
+	default:
+		err = fmt.Errorf("unsupported AsyncAPI version %s", version)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// parseV2 reads servers and channel operations from a 2.x document, where publish means the
+// application receives messages the bridge sends
+func (r *resolver) parseV2(doc *Document) error {
+	for _, name := range r.serverOrder {
+		server := r.mapAt(asMap(r.root["servers"])[name])
+		url := asString(server["url"])
+		protocol := asString(server["protocol"])
+		if protocol != "" && !strings.Contains(url, "://") {
+			url = protocol + "://" + url
+		}
+		doc.Servers = append(doc.Servers, Server{
+			Name:        name,
+			URL:         url,
+			Protocol:    protocol,
+			Description: asString(server["description"]),
+		})
+	}
+
+	channels := asMap(r.root["channels"])
+	for _, address := range orderedKeys(channels) {
+		channel := r.mapAt(channels[address])
+		parameters, err := r.parameters(address, channel)
+		if err != nil {
+			return err
+		}
+		for _, action := range []string{ActionPublish, ActionSubscribe} {
+			raw, exists := channel[action]
+			if !exists {
+				continue
+			}
+			operation := r.mapAt(raw)
+			messages, err := r.messagesV2(operation["message"])
+			if err != nil {
+				return fmt.Errorf("channel %s: %w", address, err)
+			}
+			doc.Operations = append(doc.Operations, Operation{
+				ID:          asString(operation["operationId"]),
+				Action:      action,
+				Channel:     address,
+				Summary:     asString(operation["summary"]),
+				Description: firstNonEmpty(asString(operation["description"]), asString(channel["description"])),
+				Parameters:  parameters,
+				Messages:    messages,
+			})
+		}
+	}
+	return nil
+}
+
+// messagesV2 reads an operation message, which is a message or a oneOf list of messages
+func (r *resolver) messagesV2(raw interface{}) ([]Message, error) {
+	message := r.mapAt(raw)
+	if message == nil {
+		return nil, nil
+	}
+	if oneOf, ok := message["oneOf"].([]interface{}); ok {
+		messages := make([]Message, 0, len(oneOf))
+		for _, item := range oneOf {
+			m, err := r.message(refName(item), item)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, m)
+		}
+		return messages, nil
+	}
+	m, err := r.message(refName(raw), raw)
+	if err != nil {
+		return nil, err
+	}
+	return []Message{m}, nil
+}
+
+// parseV3 reads servers and operations from a 3.x document, where receive means the application
+// receives messages the bridge sends
+func (r *resolver) parseV3(doc *Document) error {
+	for _, name := range r.serverOrder {
+		server := r.mapAt(asMap(r.root["servers"])[name])
+		protocol := asString(server["protocol"])
+		url := asString(server["host"]) + asString(server["pathname"])
+		if protocol != "" {
+			url = protocol + "://" + url
+		}
+		doc.Servers = append(doc.Servers, Server{
+			Name:        name,
+			URL:         url,
+			Protocol:    protocol,
+			Description: asString(server["description"]),
+		})
+	}
+
+	operations := asMap(r.root["operations"])
+	for _, id := range orderedKeys(operations) {
+		operation := r.mapAt(operations[id])
+		action := ""
+		switch asString(operation["action"]) {
+		case "receive":
+			action = ActionPublish
+		case "send":
+			action = ActionSubscribe
+		default:
+			return fmt.Errorf("operation %s: action must be send or receive", id)
+		}
+
+		channelRef := asMap(operation["channel"])
+		channel := r.mapAt(channelRef)
+		if channel == nil {
+			return fmt.Errorf("operation %s: channel not found", id)
+		}
+		address, exists := channel["address"]
+		if !exists || address == nil {
+			// A null address means the channel key itself is the address
+			address = strings.TrimPrefix(asString(channelRef["$ref"]), "#/channels/")
+		}
+		parameters, err := r.parameters(asString(address), channel)
+		if err != nil {
+			return err
+		}
+
+		// The operation's messages default to every message of its channel
+		messages := make([]Message, 0)
+		if list, ok := operation["messages"].([]interface{}); ok && len(list) > 0 {
+			for _, raw := range list {
+				m, err := r.message(refName(raw), raw)
+				if err != nil {
+					return fmt.Errorf("operation %s: %w", id, err)
+				}
+				messages = append(messages, m)
+			}
+		} else {
+			channelMessages := asMap(channel["messages"])
+			for _, name := range orderedKeys(channelMessages) {
+				m, err := r.message(name, channelMessages[name])
+				if err != nil {
+					return fmt.Errorf("operation %s: %w", id, err)
+				}
+				messages = append(messages, m)
+			}
+		}
+
+		doc.Operations = append(doc.Operations, Operation{
+			ID:          id,
+			Action:      action,
+			Channel:     asString(address),
+			Summary:     asString(operation["summary"]),
+			Description: firstNonEmpty(asString(operation["description"]), asString(channel["description"])),
+			Parameters:  parameters,
+			Messages:    messages,
+		})
+	}
+	return nil
+}
+
+// parameters reads the parameters of a channel address, defaulting undeclared placeholders to strings
+func (r *resolver) parameters(address string, channel map[string]interface{}) ([]Parameter, error) {
+	declared := asMap(channel["parameters"])
+	parameters := make([]Parameter, 0)
+	for _, match := range channelParamPattern.FindAllStringSubmatch(address, -1) {
+		name := match[1]
+		parameter := Parameter{Name: name, Schema: openapi.Schema{Type: "string"}}
+		if raw, exists := declared[name]; exists {
+			definition := r.mapAt(raw)
+			parameter.Description = asString(definition["description"])
+			if schema, exists := definition["schema"]; exists {
+				converted, err := r.schema(schema)
+				if err != nil {
+					return nil, fmt.Errorf("channel %s parameter %s: %w", address, name, err)
+				}
+				parameter.Schema = *converted
+			} else if enum, ok := definition["enum"].([]interface{}); ok {
+				// 3.x parameters are always strings with an optional enum and default
+				parameter.Schema.Enum = enum
+				parameter.Schema.Default = definition["default"]
+			}
+		}
+		parameters = append(parameters, parameter)
+	}
+	return parameters, nil
+}
+
+// message reads a message, converting JSON schema payloads
+func (r *resolver) message(name string, raw interface{}) (Message, error) {
+	message := r.mapAt(raw)
+	m := Message{
+		Name:        firstNonEmpty(asString(message["name"]), name, asString(message["title"])),
+		Summary:     firstNonEmpty(asString(message["summary"]), asString(message["description"])),
+		ContentType: asString(message["contentType"]),
+	}
+
+	payload, exists := message["payload"]
+	if !exists {
+		return m, nil
+	}
+	format := asString(message["schemaFormat"])
+	if multi := r.mapAt(payload); multi != nil && multi["schemaFormat"] != nil && multi["schema"] != nil {
+		// 3.x multi-format schema objects carry their format alongside the schema
+		format = asString(multi["schemaFormat"])
+		payload = multi["schema"]
+	}
+	if format != "" && !strings.Contains(format, "schema+json") && !strings.Contains(format, "aai") {
+		return m, nil
+	}
+
+	schema, err := r.schema(payload)
+	if err != nil {
+		return m, fmt.Errorf("message %s payload: %w", m.Name, err)
+	}
+	m.Payload = schema
+	return m, nil
+}
+
+// schema resolves a JSON schema and converts it to the OpenAPI schema model
+func (r *resolver) schema(raw interface{}) (*openapi.Schema, error) {
+	resolved, err := r.resolve(raw, 0)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(normalizeSchema(resolved))
+	if err != nil {
+		return nil, err
+	}
+	var schema openapi.Schema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
+
+// normalizeSchema adapts JSON schema keywords the OpenAPI schema model cannot hold: type lists
+// keep their first non-null type, and untyped schemas with properties are objects
+func normalizeSchema(value interface{}) interface{} {
+	node, ok := value.(map[string]interface{})
+	if !ok {
+		return value
+	}
+	if types, ok := node["type"].([]interface{}); ok {
+		node["type"] = ""
+		for _, t := range types {
+			if s, _ := t.(string); s != "null" {
+				node["type"] = s
+				break
+			}
+		}
+	}
+	if _, typed := node["type"]; !typed {
+		if _, hasProperties := node["properties"]; hasProperties {
+			node["type"] = "object"
+		}
+	}
+	for name, property := range asMap(node["properties"]) {
+		asMap(node["properties"])[name] = normalizeSchema(property)
+	}
+	if items, exists := node["items"]; exists {
+		if _, isList := items.([]interface{}); isList {
+			delete(node, "items")
+		} else {
+			node["items"] = normalizeSchema(items)
+		}
+	}
+	if _, draft3 := node["required"].(bool); draft3 {
+		delete(node, "required")
+	}
+	return node
+}
+
+// maxRefDepth bounds nested references so recursive schemas cannot expand forever
+const maxRefDepth = 8
+
+// resolver resolves local $ref pointers within a document
+type resolver struct {
+	root map[string]interface{}
+	// serverOrder lists server names as declared, since maps lose their order
+	serverOrder []string
+}
+
+// mapAt follows a value's reference chain to an object; it returns nil otherwise. Nested
+// references are left in place
+func (r *resolver) mapAt(value interface{}) map[string]interface{} {
+	for i := 0; i < maxRefDepth; i++ {
+		ref, ok := asMap(value)["$ref"].(string)
+		if !ok {
+			return asMap(value)
+		}
+		target, err := r.lookup(ref)
+		if err != nil {
+			return nil
+		}
+		value = target
+	}
+	return nil
+}
+
+// resolve returns a copy of value with every local reference replaced by its target
+func (r *resolver) resolve(value interface{}, depth int) (interface{}, error) {
+	if depth >= maxRefDepth {
+		return map[string]interface{}{"type": "object", "description": "(recursive)"}, nil
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			target, err := r.lookup(ref)
+			if err != nil {
+				return nil, err
+			}
+			return r.resolve(target, depth+1)
+		}
+		copied := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			resolved, err := r.resolve(item, depth)
+			if err != nil {
+				return nil, err
+			}
+			copied[key] = resolved
+		}
+		return copied, nil
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			resolved, err := r.resolve(item, depth)
+			if err != nil {
+				return nil, err
+			}
+			copied[i] = resolved
+		}
+		return copied, nil
+	default:
+		return value, nil
+	}
+}
+
+// lookup follows a local JSON pointer such as #/components/schemas/User
+func (r *resolver) lookup(ref string) (interface{}, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported reference %s: only local references are supported", ref)
+	}
+	var current interface{} = r.root
+	for _, token := range strings.Split(ref[2:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		node, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unresolved reference %s", ref)
+		}
+		if current, ok = node[token]; !ok {
+			return nil, fmt.Errorf("unresolved reference %s", ref)
+		}
+	}
+	return current, nil
+}
+
+// declaredKeys returns the keys of a top-level object in document order
+func declaredKeys(data []byte, field string) []string {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 {
+		return nil
+	}
+	document := root.Content[0]
+	for i := 0; i+1 < len(document.Content); i += 2 {
+		if document.Content[i].Value != field {
+			continue
+		}
+		object := document.Content[i+1]
+		keys := make([]string, 0, len(object.Content)/2)
+		for j := 0; j+1 < len(object.Content); j += 2 {
+			keys = append(keys, object.Content[j].Value)
+		}
+		return keys
+	}
+	return nil
+}
+
+// refName returns the last segment of a reference, such as UserSignedUp for
+// #/components/messages/UserSignedUp
+func refName(value interface{}) string {
+	ref := asString(asMap(value)["$ref"])
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// orderedKeys returns an object's keys in sorted order for stable output
+func orderedKeys(value interface{}) []string {
+	node := asMap(value)
+	keys := make([]string, 0, len(node))
+	for key := range node {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func asMap(value interface{}) map[string]interface{} {
+	node, _ := value.(map[string]interface{})
+	return node
+}
+
+func asString(value interface{}) string {
+	s, _ := value.(string)
+	return s
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
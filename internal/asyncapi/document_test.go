@@ -0,0 +1,174 @@
+package asyncapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_ExampleV2(t *testing.T) {
+	doc, err := Load("../../examples/notifications.asyncapi.yaml")
+	require.NoError(t, err)
+
+	assert.Equal(t, "2.6.0", doc.Version)
+	assert.Equal(t, "Notifications", doc.Title)
+	require.Len(t, doc.Servers, 2)
+	assert.Equal(t, Server{Name: "production", URL: "mqtt://broker.example.com:1883", Protocol: "mqtt", Description: "Production broker"}, doc.Servers[0])
+	assert.Equal(t, "https://hooks.example.com/events", doc.Servers[1].URL)
+
+	require.Len(t, doc.Operations, 3)
+	command := doc.Operations[1]
+	assert.Equal(t, "sendCommand", command.ID)
+	assert.Equal(t, ActionPublish, command.Action)
+	assert.Equal(t, "devices/{deviceId}/commands", command.Channel)
+	assert.Equal(t, "Commands sent to a device", command.Description)
+	require.Len(t, command.Parameters, 1)
+	assert.Equal(t, "deviceId", command.Parameters[0].Name)
+	require.Len(t, command.Messages, 1)
+	assert.Equal(t, "Command", command.Messages[0].Name)
+	require.NotNil(t, command.Messages[0].Payload)
+	assert.Equal(t, "object", command.Messages[0].Payload.Type)
+	assert.Equal(t, []string{"action"}, command.Messages[0].Payload.Required)
+
+	telemetry := doc.Operations[2]
+	assert.Equal(t, ActionSubscribe, telemetry.Action)
+	assert.Equal(t, "Device identifier", telemetry.Parameters[0].Description)
+	require.Len(t, telemetry.Messages, 2)
+	assert.Equal(t, "Reading", telemetry.Messages[0].Name)
+	assert.Equal(t, "number", telemetry.Messages[0].Payload.Properties["temperature"].Type)
+	assert.Equal(t, "Alert", telemetry.Messages[1].Name)
+
+	announcements := doc.Operations[0]
+	assert.Equal(t, "announcements", announcements.Channel)
+	assert.Equal(t, "string", announcements.Messages[0].Payload.Type)
+}
+
+func TestParse_V3(t *testing.T) {
+	doc, err := Parse([]byte(`
+asyncapi: 3.0.0
+info:
+  title: Orders
+  version: 2.0.0
+servers:
+  events:
+    host: events.example.com
+    pathname: /hooks
+    protocol: https
+channels:
+  orderCreated:
+    address: orders/{region}/created
+    parameters:
+      region:
+        enum: [eu, us]
+        default: eu
+    messages:
+      created:
+        payload:
+          type: [object, "null"]
+          properties:
+            id: {type: string}
+  refunds:
+    address: null
+    messages:
+      refund:
+        $ref: '#/components/messages/Refund'
+operations:
+  createOrder:
+    action: receive
+    channel:
+      $ref: '#/channels/orderCreated'
+  watchRefunds:
+    action: send
+    summary: Refunds issued
+    channel:
+      $ref: '#/channels/refunds'
+components:
+  messages:
+    Refund:
+      payload:
+        schemaFormat: application/vnd.aai.asyncapi+json;version=3.0.0
+        schema:
+          properties:
+            amount: {type: number}
+`))
+	require.NoError(t, err)
+
+	require.Len(t, doc.Servers, 1)
+	assert.Equal(t, "https://events.example.com/hooks", doc.Servers[0].URL)
+
+	require.Len(t, doc.Operations, 2)
+	create := doc.Operations[0]
+	assert.Equal(t, "createOrder", create.ID)
+	assert.Equal(t, ActionPublish, create.Action)
+	assert.Equal(t, "orders/{region}/created", create.Channel)
+	require.Len(t, create.Parameters, 1)
+	assert.Equal(t, []interface{}{"eu", "us"}, create.Parameters[0].Schema.Enum)
+	assert.Equal(t, "eu", create.Parameters[0].Schema.Default)
+	require.Len(t, create.Messages, 1)
+	assert.Equal(t, "created", create.Messages[0].Name)
+	assert.Equal(t, "object", create.Messages[0].Payload.Type)
+
+	refunds := doc.Operations[1]
+	assert.Equal(t, ActionSubscribe, refunds.Action)
+	assert.Equal(t, "refunds", refunds.Channel)
+	require.Len(t, refunds.Messages, 1)
+	assert.Equal(t, "refund", refunds.Messages[0].Name)
+	assert.Equal(t, "object", refunds.Messages[0].Payload.Type)
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse([]byte("info: {title: x}"))
+	assert.ErrorContains(t, err, "missing asyncapi version")
+
+	_, err = Parse([]byte("asyncapi: 1.2.0"))
+	assert.ErrorContains(t, err, "unsupported AsyncAPI version 1.2.0")
+
+	_, err = Parse([]byte(`
+asyncapi: 3.0.0
+operations:
+  broken:
+    action: publish
+    channel: {$ref: '#/channels/missing'}
+`))
+	assert.ErrorContains(t, err, "action must be send or receive")
+}
+
+func TestParse_RecursiveSchema(t *testing.T) {
+	doc, err := Parse([]byte(`
+asyncapi: 2.6.0
+channels:
+  tree:
+    publish:
+      message:
+        payload:
+          $ref: '#/components/schemas/Node'
+components:
+  schemas:
+    Node:
+      type: object
+      properties:
+        children:
+          type: array
+          items:
+            $ref: '#/components/schemas/Node'
+`))
+	require.NoError(t, err)
+	require.NotNil(t, doc.Operations[0].Messages[0].Payload)
+	assert.Equal(t, "array", doc.Operations[0].Messages[0].Payload.Properties["children"].Type)
+}
+
+func TestIsDocument(t *testing.T) {
+	assert.True(t, IsDocument("../../examples/notifications.asyncapi.yaml"))
+	assert.False(t, IsDocument("../../examples/petstore.yaml"))
+	assert.False(t, IsDocument("does-not-exist.yaml"))
+}
+
+func TestMatchChannel(t *testing.T) {
+	assert.True(t, matchChannel("devices/{id}/telemetry", "devices/d1/telemetry"))
+	assert.True(t, matchChannel("/announcements", "announcements"))
+	assert.False(t, matchChannel("devices/{id}/telemetry", "devices//telemetry"))
+	assert.False(t, matchChannel("devices/{id}/telemetry", "devices/d1/commands"))
+	assert.False(t, matchChannel("devices/{id}", "devices/d1/telemetry"))
+	assert.Equal(t, "devices/+/telemetry", topicFilter("devices/{id}/telemetry"))
+}
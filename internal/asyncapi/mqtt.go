@@ -0,0 +1,214 @@
+package asyncapi
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/generator"
+	"api-to-mcp/internal/secrets"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/sirupsen/logrus"
+)
+
+// mqttTimeout bounds connecting and subscribing
+const mqttTimeout = 30 * time.Second
+
+// mqttBroker publishes and subscribes over MQTT, connecting on first use
+type mqttBroker struct {
+	options *mqtt.ClientOptions
+	qos     byte
+	logger  *logrus.Logger
+
+	mu            sync.Mutex
+	client        mqtt.Client
+	subscriptions map[string]func(Delivery)
+}
+
+// newMQTTBroker creates a broker for an mqtt or mqtts server
+func newMQTTBroker(server Server, cfg *config.Config, logger *logrus.Logger) (Broker, error) {
+	serverURL := server.URL
+	if !strings.Contains(serverURL, "://") {
+		serverURL = "mqtt://" + serverURL
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid MQTT server URL %s: %w", secrets.ScrubURL(server.URL), err)
+	}
+
+	outbound, err := generator.NewOutboundPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := outbound.CheckHost(u.Hostname()); err != nil {
+		return nil, err
+	}
+
+	scheme, port := "tcp", "1883"
+	secure := u.Scheme == "mqtts" || server.Protocol == "secure-mqtt"
+	if secure {
+		scheme, port = "ssl", "8883"
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+
+	settings := cfg.OpenAPI.AsyncAPI.MQTT
+	clientID := settings.ClientID
+	if clientID == "" {
+		clientID = randomClientID()
+	}
+
+	b := &mqttBroker{
+		qos:           byte(settings.QoS),
+		logger:        logger,
+		subscriptions: make(map[string]func(Delivery)),
+	}
+	b.options = mqtt.NewClientOptions().
+		AddBroker(fmt.Sprintf("%s://%s", scheme, net.JoinHostPort(u.Hostname(), port))).
+		SetClientID(clientID).
+		SetConnectTimeout(mqttTimeout).
+		SetAutoReconnect(true).
+		SetDialer(&net.Dialer{Timeout: mqttTimeout, KeepAlive: 30 * time.Second, Control: outbound.DialControl}).
+		SetOnConnectHandler(b.resubscribe).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.WithError(err).Warn("MQTT connection lost")
+		})
+	if secure {
+		b.options.SetTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12})
+	}
+	if settings.Username != "" || settings.Password.IsSet() {
+		// Credentials are resolved on every connect so rotated secrets take effect
+		b.options.SetCredentialsProvider(func() (string, string) {
+			password, err := settings.Password.Reveal()
+			if err != nil {
+				logger.WithError(err).Error("Failed to resolve MQTT password")
+				return settings.Username, ""
+			}
+			defer secrets.Wipe(password)
+			return settings.Username, string(password)
+		})
+	}
+	return b, nil
+}
+
+// randomClientID returns a client identifier unique to this process
+func randomClientID() string {
+	suffix := make([]byte, 6)
+	rand.Read(suffix)
+	return "api-to-mcp-" + hex.EncodeToString(suffix)
+}
+
+// connect connects on first use
+func (b *mqttBroker) connect() (mqtt.Client, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.client != nil {
+		return b.client, nil
+	}
+
+	client := mqtt.NewClient(b.options)
+	token := client.Connect()
+	if !token.WaitTimeout(mqttTimeout) {
+		return nil, fmt.Errorf("timed out connecting to MQTT broker")
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+	b.client = client
+	return client, nil
+}
+
+// Publish publishes the payload to the channel's topic
+func (b *mqttBroker) Publish(ctx context.Context, channel string, payload []byte) error {
+	client, err := b.connect()
+	if err != nil {
+		return err
+	}
+	return wait(ctx, client.Publish(channel, b.qos, false, payload))
+}
+
+// Subscribe subscribes to the pattern's topic filter, with placeholders as + wildcards
+func (b *mqttBroker) Subscribe(ctx context.Context, pattern string, deliver func(Delivery)) error {
+	b.mu.Lock()
+	b.subscriptions[pattern] = deliver
+	b.mu.Unlock()
+
+	client, err := b.connect()
+	if err != nil {
+		return err
+	}
+	return wait(ctx, client.Subscribe(topicFilter(pattern), b.qos, handler(deliver)))
+}
+
+// Unsubscribe removes the pattern's subscription
+func (b *mqttBroker) Unsubscribe(pattern string) error {
+	b.mu.Lock()
+	delete(b.subscriptions, pattern)
+	client := b.client
+	b.mu.Unlock()
+
+	if client == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), mqttTimeout)
+	defer cancel()
+	return wait(ctx, client.Unsubscribe(topicFilter(pattern)))
+}
+
+// Close disconnects from the broker
+func (b *mqttBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.client != nil {
+		b.client.Disconnect(250)
+		b.client = nil
+	}
+	return nil
+}
+
+// resubscribe restores subscriptions after a reconnect, since sessions are not persisted
+func (b *mqttBroker) resubscribe(client mqtt.Client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for pattern, deliver := range b.subscriptions {
+		client.Subscribe(topicFilter(pattern), b.qos, handler(deliver))
+	}
+}
+
+// handler adapts a delivery callback to an MQTT message handler
+func handler(deliver func(Delivery)) mqtt.MessageHandler {
+	return func(_ mqtt.Client, message mqtt.Message) {
+		deliver(Delivery{Channel: message.Topic(), Payload: message.Payload(), ReceivedAt: time.Now()})
+	}
+}
+
+// topicFilter converts a channel pattern to an MQTT topic filter
+func topicFilter(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if channelParamPattern.MatchString(segment) {
+			segments[i] = "+"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// wait waits for an MQTT operation to complete or the context to end
+func wait(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,152 @@
+package asyncapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/generator"
+	"api-to-mcp/internal/secrets"
+	"api-to-mcp/internal/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SignatureHeader carries the hex HMAC-SHA256 of an inbound webhook body, prefixed with sha256=
+const SignatureHeader = "X-Signature-256"
+
+// maxDeliveryBytes bounds the size of an inbound webhook delivery
+const maxDeliveryBytes = 1 << 20
+
+// webhookBroker publishes by POSTing to the server URL and receives webhook deliveries POSTed
+// to the bridge's events endpoint
+type webhookBroker struct {
+	client *utils.HTTPClient
+	secret secrets.Secret
+	logger *logrus.Logger
+
+	mu            sync.RWMutex
+	subscriptions map[string]func(Delivery)
+}
+
+// newWebhookBroker creates a broker for an http or https server
+func newWebhookBroker(server Server, cfg *config.Config, logger *logrus.Logger) (Broker, error) {
+	outbound, err := generator.NewOutboundPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	scoped := *cfg
+	scoped.OpenAPI.BaseURL = strings.TrimSuffix(server.URL, "/")
+	return &webhookBroker{
+		client:        generator.NewUpstreamClient(&scoped, logger, outbound),
+		secret:        cfg.OpenAPI.AsyncAPI.WebhookSecret,
+		logger:        logger,
+		subscriptions: make(map[string]func(Delivery)),
+	}, nil
+}
+
+// Publish POSTs the payload to the channel address relative to the server URL
+func (b *webhookBroker) Publish(ctx context.Context, channel string, payload []byte) error {
+	_, err := b.client.MakeRequest(ctx, http.MethodPost, "/"+strings.TrimPrefix(channel, "/"), map[string]interface{}{
+		"body": json.RawMessage(payload),
+	})
+	return err
+}
+
+// Subscribe accepts deliveries for channels matching the pattern
+func (b *webhookBroker) Subscribe(_ context.Context, pattern string, deliver func(Delivery)) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions[pattern] = deliver
+	return nil
+}
+
+// Unsubscribe stops accepting deliveries for the pattern
+func (b *webhookBroker) Unsubscribe(pattern string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscriptions, pattern)
+	return nil
+}
+
+// Close drops every subscription
+func (b *webhookBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscriptions = make(map[string]func(Delivery))
+	return nil
+}
+
+// ServeHTTP receives a webhook delivery; the request path, relative to the namespace's events
+// endpoint, is the channel
+func (b *webhookBroker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDeliveryBytes))
+	if err != nil {
+		http.Error(w, "delivery too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := b.verify(r.Header.Get(SignatureHeader), body); err != nil {
+		b.logger.WithError(err).WithField("channel", r.URL.Path).Warn("Rejected webhook delivery")
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	channel := strings.Trim(r.URL.Path, "/")
+	b.mu.RLock()
+	var deliver func(Delivery)
+	for pattern, fn := range b.subscriptions {
+		if matchChannel(pattern, channel) {
+			deliver = fn
+			break
+		}
+	}
+	b.mu.RUnlock()
+	if deliver == nil {
+		http.Error(w, "no subscription for channel "+channel, http.StatusNotFound)
+		return
+	}
+
+	deliver(Delivery{Channel: channel, Payload: body, ReceivedAt: time.Now()})
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// verify checks the delivery signature when a webhook secret is configured
+func (b *webhookBroker) verify(signature string, body []byte) error {
+	if !b.secret.IsSet() {
+		return nil
+	}
+	if !strings.HasPrefix(signature, "sha256=") {
+		return fmt.Errorf("missing %s header", SignatureHeader)
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "sha256="))
+	if err != nil {
+		return fmt.Errorf("malformed %s header", SignatureHeader)
+	}
+
+	key, err := b.secret.Reveal()
+	if err != nil {
+		return fmt.Errorf("failed to resolve webhook secret: %w", err)
+	}
+	defer secrets.Wipe(key)
+	mac := hmac.New(sha256.New, key)
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"api-to-mcp/internal/functions"
+	"api-to-mcp/pkg/mcp"
+)
+
+// Version is the catalog format version; fields are only added within a version
+const Version = "1"
+
+// schema is the JSON Schema of the catalog format
+//
+//go:embed catalog.schema.json
+var schema []byte
+
+// Catalog describes generated tools in the shape agent frameworks such as LangChain and
+// LlamaIndex load: a function name, description and JSON Schema of the arguments per tool
+type Catalog struct {
+	CatalogVersion string `json:"catalogVersion"`
+	Server         Server `json:"server"`
+	Tools          []Tool `json:"tools"`
+}
+
+// Server identifies the bridge serving the tools
+type Server struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	// URL is where runtimes call the tools, when known
+	URL       string `json:"url,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Tool is a catalog entry
+type Tool struct {
+	// Name is the function name model APIs accept
+	Name string `json:"name"`
+	// ToolName is the MCP tool name the bridge serves
+	ToolName    string           `json:"toolName"`
+	Description string           `json:"description"`
+	Parameters  functions.Schema `json:"parameters"`
+	Endpoint    *mcp.Operation   `json:"endpoint,omitempty"`
+}
+
+// Build creates a catalog of the tools, ordered by name; it fails when two tools map to the
+// same function name
+func Build(tools []mcp.Tool, server Server) (*Catalog, error) {
+	if err := functions.CheckNames(tools); err != nil {
+		return nil, err
+	}
+
+	entries := make([]Tool, 0, len(tools))
+	for _, tool := range tools {
+		entries = append(entries, Tool{
+			Name:        functions.Name(tool.Name),
+			ToolName:    tool.Name,
+			Description: tool.Description,
+			Parameters:  functions.Parameters(tool.InputSchema),
+			Endpoint:    tool.Operation,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return &Catalog{CatalogVersion: Version, Server: server, Tools: entries}, nil
+}
+
+// Marshal encodes the catalog as indented JSON
+func (c *Catalog) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode catalog: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Schema returns the JSON Schema of the catalog format
+func Schema() []byte {
+	return schema
+}
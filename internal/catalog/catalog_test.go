@@ -0,0 +1,98 @@
+package catalog
+
+import (
+	"encoding/json"
+	"testing"
+
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "list.pets",
+			Description: "List pets",
+			InputSchema: &mcp.InputSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"tags":  {Type: "array", Description: "Tags to match"},
+					"limit": {Type: "integer"},
+				},
+				Required: []string{"limit"},
+			},
+			Operation: &mcp.Operation{Method: "GET", Path: "/pets", BaseURL: "https://pets.example.com", OperationID: "listPets"},
+		},
+		{
+			Name:        "add_pet",
+			Description: "Add a pet",
+			InputSchema: &mcp.InputSchema{Type: "object", Properties: map[string]mcp.Property{}},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	c, err := Build(testTools(), Server{Name: "pets", Version: "1.2.0", URL: "https://tools.example.com"})
+	require.NoError(t, err)
+
+	assert.Equal(t, Version, c.CatalogVersion)
+	require.Len(t, c.Tools, 2)
+	assert.Equal(t, "add_pet", c.Tools[0].Name)
+	assert.Nil(t, c.Tools[0].Endpoint)
+
+	list := c.Tools[1]
+	assert.Equal(t, "list_pets", list.Name)
+	assert.Equal(t, "list.pets", list.ToolName)
+	assert.Equal(t, "GET", list.Endpoint.Method)
+	assert.Equal(t, []string{"limit"}, list.Parameters["required"])
+	properties := list.Parameters["properties"].(map[string]interface{})
+	// Arrays always carry items, as model APIs require
+	assert.Equal(t, map[string]interface{}{}, properties["tags"].(map[string]interface{})["items"])
+}
+
+func TestBuild_NameCollision(t *testing.T) {
+	tools := append(testTools(), mcp.Tool{Name: "list_pets", Description: "Duplicate", InputSchema: &mcp.InputSchema{Type: "object"}})
+	_, err := Build(tools, Server{})
+	assert.ErrorContains(t, err, "tools list.pets and list_pets both map to function name list_pets")
+}
+
+// TestSchema checks the published schema against what Build writes, so the two cannot drift
+func TestSchema(t *testing.T) {
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(Schema(), &document))
+
+	properties := document["properties"].(map[string]interface{})
+	assert.Equal(t, Version, properties["catalogVersion"].(map[string]interface{})["const"])
+
+	c, err := Build(testTools(), Server{Name: "pets", Version: "1.2.0"})
+	require.NoError(t, err)
+	data, err := c.Marshal()
+	require.NoError(t, err)
+	var encoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &encoded))
+
+	assertFields := func(object map[string]interface{}, schema map[string]interface{}) {
+		t.Helper()
+		declared := schema["properties"].(map[string]interface{})
+		for _, required := range schema["required"].([]interface{}) {
+			assert.Contains(t, object, required)
+		}
+		for field := range object {
+			assert.Contains(t, declared, field, "field %s is not in the schema", field)
+		}
+	}
+	assertFields(encoded, document)
+	assertFields(encoded["server"].(map[string]interface{}), properties["server"].(map[string]interface{}))
+
+	toolSchema := document["$defs"].(map[string]interface{})["tool"].(map[string]interface{})
+	endpointSchema := toolSchema["properties"].(map[string]interface{})["endpoint"].(map[string]interface{})
+	for _, item := range encoded["tools"].([]interface{}) {
+		tool := item.(map[string]interface{})
+		assertFields(tool, toolSchema)
+		if endpoint, ok := tool["endpoint"].(map[string]interface{}); ok {
+			assertFields(endpoint, endpointSchema)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+	"text/template"
+
+	"api-to-mcp/pkg/mcp"
+	"api-to-mcp/pkg/openapi"
+)
+
+// GoOptions configures standalone Go package generation
+type GoOptions struct {
+	PackageName string
+	BaseURL     string
+}
+
+// goTool is the template view of a single compiled-in tool
+type goTool struct {
+	Name        string
+	Description string
+	Schema      string
+	Method      string
+	Path        string
+	OperationID string
+	PathParams  []string
+	QueryParams []string
+	HeaderParam []string
+	HasBody     bool
+}
+
+// GenerateGo renders a self-contained Go package exposing the tools with handlers that call
+// the upstream API directly through net/http. Endpoints are looked up by "METHOD path" to
+// recover parameter locations.
+func GenerateGo(tools []mcp.Tool, endpoints []openapi.Endpoint, opts GoOptions) ([]byte, error) {
+	if opts.PackageName == "" {
+		return nil, fmt.Errorf("package name is required")
+	}
+
+	byOperation := make(map[string]openapi.Endpoint, len(endpoints))
+	for _, endpoint := range endpoints {
+		byOperation[endpoint.Method+" "+endpoint.Path] = endpoint
+	}
+
+	views := make([]goTool, 0, len(tools))
+	for _, tool := range tools {
+		if tool.Operation == nil {
+			return nil, fmt.Errorf("tool %s has no upstream operation", tool.Name)
+		}
+		endpoint, exists := byOperation[tool.Operation.Method+" "+tool.Operation.Path]
+		if !exists {
+			return nil, fmt.Errorf("endpoint not found for tool %s: %s %s", tool.Name, tool.Operation.Method, tool.Operation.Path)
+		}
+
+		schema, err := json.Marshal(tool.InputSchema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode input schema for tool %s: %w", tool.Name, err)
+		}
+
+		view := goTool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Schema:      string(schema),
+			Method:      endpoint.Method,
+			Path:        endpoint.Path,
+			OperationID: endpoint.OperationID,
+			HasBody:     endpoint.RequestBody != nil,
+		}
+		for _, param := range endpoint.Parameters {
+			switch param.In {
+			case "path":
+				view.PathParams = append(view.PathParams, param.Name)
+			case "query":
+				view.QueryParams = append(view.QueryParams, param.Name)
+			case "header":
+				view.HeaderParam = append(view.HeaderParam, param.Name)
+			}
+		}
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool {
+		return views[i].Name < views[j].Name
+	})
+
+	var buf bytes.Buffer
+	err := goTemplate.Execute(&buf, map[string]interface{}{
+		"Package": opts.PackageName,
+		"BaseURL": opts.BaseURL,
+		"Tools":   views,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render Go package: %w", err)
+	}
+
+	source, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go package: %w", err)
+	}
+	return source, nil
+}
+
+var goTemplate = template.Must(template.New("go").Funcs(template.FuncMap{
+	"quote": func(s string) string { return fmt.Sprintf("%q", s) },
+	"literal": func(s string) string {
+		if strings.Contains(s, "`") {
+			return fmt.Sprintf("%q", s)
+		}
+		return "`" + s + "`"
+	},
+}).Parse(`// Code generated by api-to-mcp generate go. DO NOT EDIT.
+
+// Package {{.Package}} exposes a frozen, compiled-in MCP toolset that calls the upstream API
+// directly, with no configuration file or OpenAPI specification needed at runtime.
+package {{.Package}}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"api-to-mcp/pkg/mcp"
+)
+
+// BaseURL is the upstream API base URL the tools call
+var BaseURL = {{quote .BaseURL}}
+
+// HTTPClient performs the upstream requests
+var HTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// Headers are added to every upstream request, e.g. for authentication
+var Headers = map[string]string{}
+
+// Tools returns the compiled-in toolset
+func Tools() []mcp.Tool {
+	return []mcp.Tool{
+{{- range .Tools}}
+		{
+			Name:        {{quote .Name}},
+			Description: {{quote .Description}},
+			InputSchema: mustSchema({{literal .Schema}}),
+			Handler: handler(operation{
+				method:       {{quote .Method}},
+				path:         {{quote .Path}},
+				pathParams:   {{printf "%#v" .PathParams}},
+				queryParams:  {{printf "%#v" .QueryParams}},
+				headerParams: {{printf "%#v" .HeaderParam}},
+				hasBody:      {{.HasBody}},
+			}),
+			Operation: &mcp.Operation{
+				Method:      {{quote .Method}},
+				Path:        {{quote .Path}},
+				OperationID: {{quote .OperationID}},
+			},
+		},
+{{- end}}
+	}
+}
+
+// operation describes how tool arguments map onto an upstream request
+type operation struct {
+	method       string
+	path         string
+	pathParams   []string
+	queryParams  []string
+	headerParams []string
+	hasBody      bool
+}
+
+// mustSchema decodes an embedded input schema
+func mustSchema(data string) *mcp.InputSchema {
+	var schema mcp.InputSchema
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		panic(err)
+	}
+	return &schema
+}
+
+// handler builds the tool handler for an operation
+func handler(op operation) mcp.ToolHandler {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		args := make(map[string]interface{}, len(params))
+		for key, value := range params {
+			args[key] = value
+		}
+
+		path := op.path
+		for _, name := range op.pathParams {
+			value, exists := args[name]
+			if !exists {
+				return nil, fmt.Errorf("missing path parameter: %s", name)
+			}
+			path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(fmt.Sprintf("%v", value)))
+			delete(args, name)
+		}
+
+		query := url.Values{}
+		for _, name := range op.queryParams {
+			if value, exists := args[name]; exists {
+				query.Set(name, fmt.Sprintf("%v", value))
+				delete(args, name)
+			}
+		}
+
+		headers := make(map[string]string)
+		for _, name := range op.headerParams {
+			if value, exists := args[name]; exists {
+				headers[name] = fmt.Sprintf("%v", value)
+				delete(args, name)
+			}
+		}
+
+		var body io.Reader
+		if op.hasBody && len(args) > 0 {
+			var payload interface{} = args
+			if value, exists := args["body"]; exists && len(args) == 1 {
+				payload = value
+			}
+			data, err := json.Marshal(payload)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode request body: %w", err)
+			}
+			body = bytes.NewReader(data)
+		}
+
+		target := strings.TrimSuffix(BaseURL, "/") + path
+		if len(query) > 0 {
+			target += "?" + query.Encode()
+		}
+
+		req, err := http.NewRequestWithContext(ctx, op.method, target, body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		for key, value := range Headers {
+			req.Header.Set(key, value)
+		}
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+
+		resp, err := HTTPClient.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("%s request failed: %w", op.method, err)
+		}
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read response: %w", err)
+		}
+		if resp.StatusCode >= 400 {
+			return nil, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, string(data))
+		}
+
+		var result interface{}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return string(data), nil
+		}
+		return result, nil
+	}
+}
+`))
@@ -0,0 +1,61 @@
+package codegen
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+
+	"api-to-mcp/pkg/mcp"
+	"api-to-mcp/pkg/openapi"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateGo(t *testing.T) {
+	endpoints := []openapi.Endpoint{
+		{
+			Path:        "/pet/{petId}",
+			Method:      "GET",
+			OperationID: "getPetById",
+			Parameters: []openapi.Parameter{
+				{Name: "petId", In: "path", Required: true, Schema: openapi.Schema{Type: "integer"}},
+				{Name: "fields", In: "query", Schema: openapi.Schema{Type: "string"}},
+			},
+		},
+	}
+	tools := []mcp.Tool{
+		{
+			Name:        "getpetbyid",
+			Description: "Find pet by `ID`",
+			InputSchema: &mcp.InputSchema{
+				Type:       "object",
+				Properties: map[string]mcp.Property{"petId": {Type: "integer", Description: "Use `id`"}},
+				Required:   []string{"petId"},
+			},
+			Operation: &mcp.Operation{Method: "GET", Path: "/pet/{petId}"},
+		},
+	}
+
+	source, err := GenerateGo(tools, endpoints, GoOptions{PackageName: "petstore", BaseURL: "https://api.example.com"})
+	require.NoError(t, err)
+
+	file, err := parser.ParseFile(token.NewFileSet(), "tools.go", source, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "petstore", file.Name.Name)
+
+	code := string(source)
+	assert.Contains(t, code, `Name:        "getpetbyid"`)
+	assert.Contains(t, code, `pathParams:   []string{"petId"}`)
+	assert.Contains(t, code, `queryParams:  []string{"fields"}`)
+	assert.Contains(t, code, `var BaseURL = "https://api.example.com"`)
+}
+
+func TestGenerateGo_MissingEndpoint(t *testing.T) {
+	tools := []mcp.Tool{
+		{Name: "orphan", InputSchema: &mcp.InputSchema{Type: "object"}, Operation: &mcp.Operation{Method: "GET", Path: "/x"}},
+	}
+
+	_, err := GenerateGo(tools, nil, GoOptions{PackageName: "x"})
+	assert.Error(t, err)
+}
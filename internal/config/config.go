@@ -1,20 +1,45 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
 
+	"api-to-mcp/internal/secrets"
+
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
+// tenantNamePattern restricts tenant names to URL path safe characters
+var tenantNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
 // Config represents the application configuration
 type Config struct {
-	Server  ServerConfig  `mapstructure:"server"`
-	OpenAPI OpenAPIConfig `mapstructure:"openapi"`
-	MCP     MCPConfig     `mapstructure:"mcp"`
-	Filters FilterConfig  `mapstructure:"filters"`
-	Logging LoggingConfig `mapstructure:"logging"`
+	Server    ServerConfig     `mapstructure:"server"`
+	OpenAPI   OpenAPIConfig    `mapstructure:"openapi"`
+	MCP       MCPConfig        `mapstructure:"mcp"`
+	Filters   FilterConfig     `mapstructure:"filters"`
+	Logging   LoggingConfig    `mapstructure:"logging"`
+	Auth      AuthConfig       `mapstructure:"auth"`
+	Tenants   []TenantConfig   `mapstructure:"tenants"`
+	Aggregate []UpstreamConfig `mapstructure:"aggregate"`
+	Metrics   MetricsConfig    `mapstructure:"metrics"`
+	Admin     AdminConfig      `mapstructure:"admin"`
+	Functions FunctionsConfig  `mapstructure:"functions"`
+	Docs      DocsConfig       `mapstructure:"docs"`
+	Health    HealthConfig     `mapstructure:"health"`
+	Safety    SafetyConfig     `mapstructure:"safety"`
+	Inbound   InboundConfig    `mapstructure:"inbound_auth"`
+	RBAC      RBACConfig       `mapstructure:"rbac"`
+
+	Observability ObservabilityConfig `mapstructure:"observability"`
 }
 
 // ServerConfig contains server-specific configuration
@@ -25,8 +50,113 @@ type ServerConfig struct {
 
 // OpenAPIConfig contains OpenAPI-specific configuration
 type OpenAPIConfig struct {
-	SpecPath string `mapstructure:"spec_path"`
-	BaseURL  string `mapstructure:"base_url"`
+	SpecPath  string `mapstructure:"spec_path"`
+	BaseURL   string `mapstructure:"base_url"`
+	ProbePath string `mapstructure:"probe_path"`
+	// Manifest pins the served toolset to a signed manifest
+	Manifest ManifestConfig `mapstructure:"manifest"`
+	// GraphQL generates tools from a GraphQL schema instead of spec_path
+	GraphQL GraphQLConfig `mapstructure:"graphql"`
+	// GRPC generates tools from the unary methods of a gRPC server instead of spec_path
+	GRPC GRPCConfig `mapstructure:"grpc"`
+	// AsyncAPI configures the broker used when spec_path is an AsyncAPI document
+	AsyncAPI AsyncAPIConfig `mapstructure:"asyncapi"`
+	// SOAP configures the service called when spec_path is a WSDL document
+	SOAP SOAPConfig `mapstructure:"soap"`
+	// OData applies OData conventions to endpoints declaring system query options
+	OData ODataConfig `mapstructure:"odata"`
+	// JSONAPI simplifies JSON:API documents and adds page parameters to JSON:API collections
+	JSONAPI JSONAPIConfig `mapstructure:"jsonapi"`
+}
+
+// JSONAPIConfig configures JSON:API response handling
+type JSONAPIConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// FlattenIncluded replaces relationship identifiers with the included resources they name
+	FlattenIncluded bool `mapstructure:"flatten_included"`
+}
+
+// ODataConfig configures OData query option handling and paging
+type ODataConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// MaxPages bounds how many pages a call fetches by following @odata.nextLink; 1 returns only
+	// the first page
+	MaxPages int `mapstructure:"max_pages"`
+}
+
+// SOAPConfig selects and calls the SOAP service described by a WSDL document
+type SOAPConfig struct {
+	// Service names the WSDL service to expose; the first service is used when empty
+	Service string `mapstructure:"service"`
+	// Port names the service port; the first SOAP 1.1 or 1.2 port is used when empty
+	Port string `mapstructure:"port"`
+	// Endpoint overrides the port's soap:address location
+	Endpoint string `mapstructure:"endpoint"`
+	// ReadOperations are operation name patterns treated as read-only, e.g. ["Get*", "Find*"]
+	ReadOperations []string `mapstructure:"read_operations"`
+}
+
+// AsyncAPIConfig configures event-driven upstreams described by an AsyncAPI document
+type AsyncAPIConfig struct {
+	// Server names the document's server to connect to; the first server is used when empty
+	Server string `mapstructure:"server"`
+	// URL overrides the selected server's URL
+	URL string `mapstructure:"url"`
+	// BufferSize is how many recent messages each subscribed channel keeps
+	BufferSize int `mapstructure:"buffer_size"`
+	// WebhookSecret, when set, requires inbound webhook deliveries to carry an HMAC-SHA256 signature
+	WebhookSecret secrets.Secret `mapstructure:"webhook_secret"`
+	MQTT          MQTTConfig     `mapstructure:"mqtt"`
+}
+
+// MQTTConfig contains MQTT broker connection settings
+type MQTTConfig struct {
+	// ClientID defaults to a random api-to-mcp-<hex> identifier
+	ClientID string         `mapstructure:"client_id"`
+	Username string         `mapstructure:"username"`
+	Password secrets.Secret `mapstructure:"password"`
+	// QoS is the quality of service used to publish and subscribe, from 0 to 2
+	QoS int `mapstructure:"qos"`
+}
+
+// GRPCConfig describes a gRPC upstream whose unary methods become tools
+type GRPCConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Target is the server address, such as localhost:50051
+	Target string `mapstructure:"target"`
+	// DescriptorSet is a FileDescriptorSet written by protoc --descriptor_set_out --include_imports;
+	// server reflection is used when empty
+	DescriptorSet string `mapstructure:"descriptor_set"`
+	// Services limits tools to these fully-qualified services
+	Services []string `mapstructure:"services"`
+	// Plaintext connects without TLS
+	Plaintext bool `mapstructure:"plaintext"`
+	// ReadMethods are method name patterns treated as read-only, in addition to methods declaring
+	// idempotency_level = NO_SIDE_EFFECTS
+	ReadMethods []string `mapstructure:"read_methods"`
+	// Timeout bounds each call
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// GraphQLConfig describes a GraphQL upstream whose queries and mutations become tools
+type GraphQLConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Endpoint is the GraphQL endpoint, relative to base_url
+	Endpoint string `mapstructure:"endpoint"`
+	// SchemaPath is an SDL file or saved introspection result; the endpoint is introspected when empty
+	SchemaPath string `mapstructure:"schema_path"`
+	// MaxDepth limits how deeply nested object fields are selected in tool results
+	MaxDepth int `mapstructure:"max_depth"`
+	// DisableQueryTool omits the graphql_query tool that runs arbitrary documents
+	DisableQueryTool bool `mapstructure:"disable_query_tool"`
+}
+
+// ManifestConfig names a signed tool manifest the generated toolset must match before it is served
+type ManifestConfig struct {
+	Path string `mapstructure:"path"`
+	// Signature defaults to the manifest path with a .sig suffix
+	Signature string `mapstructure:"signature"`
+	PublicKey string `mapstructure:"public_key"`
 }
 
 // MCPConfig contains MCP-specific configuration
@@ -45,8 +175,253 @@ type FilterConfig struct {
 
 // LoggingConfig contains logging configuration
 type LoggingConfig struct {
-	Level  string `mapstructure:"level"`
-	Format string `mapstructure:"format"`
+	Level     string               `mapstructure:"level"`
+	Format    string               `mapstructure:"format"`
+	Output    string               `mapstructure:"output"`
+	Rotation  RotationConfig       `mapstructure:"rotation"`
+	Access    LogDestinationConfig `mapstructure:"access"`
+	Audit     LogDestinationConfig `mapstructure:"audit"`
+	Redaction RedactionConfig      `mapstructure:"redaction"`
+}
+
+// RotationConfig contains size and age limits for log files
+type RotationConfig struct {
+	MaxSizeMB  int           `mapstructure:"max_size_mb"`
+	MaxAge     time.Duration `mapstructure:"max_age"`
+	MaxBackups int           `mapstructure:"max_backups"`
+}
+
+// LogDestinationConfig selects where a log stream is written: stdout, stderr or a file path
+type LogDestinationConfig struct {
+	Output string `mapstructure:"output"`
+}
+
+// RedactionConfig contains the rules masking sensitive values in logs
+type RedactionConfig struct {
+	Enabled  bool     `mapstructure:"enabled"`
+	Fields   []string `mapstructure:"fields"`
+	Paths    []string `mapstructure:"paths"`
+	Patterns []string `mapstructure:"patterns"`
+	Mask     string   `mapstructure:"mask"`
+}
+
+// DefaultRedactedFields are the key patterns masked when no fields are configured
+var DefaultRedactedFields = []string{
+	"^(password|passwd|secret|client_secret)$",
+	"^(token|access_token|refresh_token|id_token)$",
+	"^(authorization|cookie|set-cookie)$",
+	"^x?-?api[-_]?key$",
+}
+
+// MetricsConfig contains tool call metrics configuration
+type MetricsConfig struct {
+	Enabled  bool `mapstructure:"enabled"`
+	MaxTools int  `mapstructure:"max_tools"`
+}
+
+// ObservabilityConfig contains tracing and diagnostics configuration
+type ObservabilityConfig struct {
+	CorrelationHeader  string        `mapstructure:"correlation_header"`
+	SlowCallThreshold  time.Duration `mapstructure:"slow_call_threshold"`
+	LargeResponseBytes int           `mapstructure:"large_response_bytes"`
+	Sentry             SentryConfig  `mapstructure:"sentry"`
+}
+
+// SentryConfig contains Sentry-compatible error reporting configuration
+type SentryConfig struct {
+	DSN            string        `mapstructure:"dsn"`
+	Environment    string        `mapstructure:"environment"`
+	BurstThreshold int           `mapstructure:"burst_threshold"`
+	BurstWindow    time.Duration `mapstructure:"burst_window"`
+}
+
+// HealthConfig contains upstream availability probing configuration
+type HealthConfig struct {
+	Enabled              bool          `mapstructure:"enabled"`
+	Interval             time.Duration `mapstructure:"interval"`
+	Timeout              time.Duration `mapstructure:"timeout"`
+	DegradedDescriptions bool          `mapstructure:"degraded_descriptions"`
+}
+
+// SafetyConfig contains guardrails limiting what generated tools may do
+type SafetyConfig struct {
+	ReadOnly     bool               `mapstructure:"read_only"`
+	Outbound     OutboundConfig     `mapstructure:"outbound"`
+	Confirmation ConfirmationConfig `mapstructure:"confirmation"`
+	// ResponseRedaction masks sensitive fields in upstream responses before they reach the client
+	ResponseRedaction RedactionConfig `mapstructure:"response_redaction"`
+	// Policy rules are evaluated before every tool call and may deny or rewrite it
+	Policy PolicyConfig `mapstructure:"policy"`
+	// WriteBudget caps mutating calls per session, independently of rate limiting
+	WriteBudget WriteBudgetConfig `mapstructure:"write_budget"`
+}
+
+// WriteBudgetConfig limits mutating calls per session within a sliding window. Sessions are
+// identified by the authenticated client, then SessionHeader, then the remote address
+type WriteBudgetConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	MaxWrites     int           `mapstructure:"max_writes"`
+	Window        time.Duration `mapstructure:"window"`
+	Methods       []string      `mapstructure:"methods"`
+	SessionHeader string        `mapstructure:"session_header"`
+}
+
+// PolicyConfig contains argument policy rules evaluated in order before each upstream call
+type PolicyConfig struct {
+	Enabled bool               `mapstructure:"enabled"`
+	Rules   []PolicyRuleConfig `mapstructure:"rules"`
+}
+
+// PolicyRuleConfig denies or rewrites the calls of matching tools; expressions see tool,
+// namespace, method, path, client.id, client.roles and arguments
+type PolicyRuleConfig struct {
+	Name string `mapstructure:"name"`
+	// Tools are tool name globs the rule applies to; empty applies to every tool
+	Tools []string `mapstructure:"tools"`
+	// When is an optional expression that must hold for the rule to apply
+	When string `mapstructure:"when"`
+	// Deny is an expression rejecting the call when true
+	Deny    string `mapstructure:"deny"`
+	Message string `mapstructure:"message"`
+	// Set assigns arguments from expressions before the call
+	Set []PolicyAssignment `mapstructure:"set"`
+}
+
+// PolicyAssignment overwrites an argument with the value of an expression
+type PolicyAssignment struct {
+	Argument string `mapstructure:"argument"`
+	Value    string `mapstructure:"value"`
+}
+
+// DefaultResponseRedactedFields are the response key patterns masked when no fields are configured
+var DefaultResponseRedactedFields = []string{
+	"^(password|password_hash|passwd|secret|client_secret)$",
+	"^(ssn|social_security_number)$",
+	"^(api_key|apikey|private_key)$",
+}
+
+// ConfirmationConfig selects tools that only run after the client repeats the call with a token
+type ConfirmationConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Methods []string      `mapstructure:"methods"`
+	Paths   []string      `mapstructure:"paths"`
+	TTL     time.Duration `mapstructure:"ttl"`
+}
+
+// IsReadOnlyMethod reports whether an HTTP method is allowed in read-only mode
+func IsReadOnlyMethod(method string) bool {
+	return strings.EqualFold(method, "GET") || strings.EqualFold(method, "HEAD")
+}
+
+// OutboundConfig restricts the upstream hosts and addresses tools may reach
+type OutboundConfig struct {
+	AllowedHosts []string `mapstructure:"allowed_hosts"`
+	AllowedCIDRs []string `mapstructure:"allowed_cidrs"`
+	AllowPrivate bool     `mapstructure:"allow_private"`
+}
+
+// InboundConfig contains authentication of MCP clients calling this server
+type InboundConfig struct {
+	Enabled bool           `mapstructure:"enabled"`
+	Clients []ClientConfig `mapstructure:"clients"`
+}
+
+// ClientConfig identifies an MCP client by its bearer token and grants it roles
+type ClientConfig struct {
+	ID    string         `mapstructure:"id"`
+	Token secrets.Secret `mapstructure:"token"`
+	Roles []string       `mapstructure:"roles"`
+}
+
+// RBACConfig contains the roles restricting which tools authenticated clients may see and call
+type RBACConfig struct {
+	Roles []RoleConfig `mapstructure:"roles"`
+}
+
+// RoleConfig grants the tools matching every set constraint; empty constraints match anything
+type RoleConfig struct {
+	Name       string   `mapstructure:"name"`
+	Namespaces []string `mapstructure:"namespaces"`
+	Methods    []string `mapstructure:"methods"`
+	Tools      []string `mapstructure:"tools"`
+}
+
+// AdminConfig contains admin API configuration
+type AdminConfig struct {
+	Enabled bool           `mapstructure:"enabled"`
+	Token   secrets.Secret `mapstructure:"token"`
+}
+
+// FunctionsConfig contains configuration of the function-calling shim for non-MCP clients
+type FunctionsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// DocsConfig contains configuration of the generated tool documentation page
+type DocsConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+}
+
+// AuthConfig contains upstream API authentication configuration; the token is resolved on use
+type AuthConfig struct {
+	Type  string         `mapstructure:"type"`
+	Token secrets.Secret `mapstructure:"token"`
+}
+
+// RateLimitConfig contains inbound rate limiting configuration
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `mapstructure:"requests_per_second"`
+	Burst             int     `mapstructure:"burst"`
+}
+
+// TenantConfig describes an isolated API namespace served under /mcp/<name>
+type TenantConfig struct {
+	Name      string          `mapstructure:"name"`
+	OpenAPI   OpenAPIConfig   `mapstructure:"openapi"`
+	Filters   FilterConfig    `mapstructure:"filters"`
+	Auth      AuthConfig      `mapstructure:"auth"`
+	RateLimit RateLimitConfig `mapstructure:"rate_limit"`
+}
+
+// UpstreamConfig describes a specification merged into the default namespace, its tools and
+// resources named with a prefix
+type UpstreamConfig struct {
+	Name string `mapstructure:"name"`
+	// Prefix is prepended to tool and resource names; defaults to the name followed by _
+	Prefix  string        `mapstructure:"prefix"`
+	OpenAPI OpenAPIConfig `mapstructure:"openapi"`
+	Filters FilterConfig  `mapstructure:"filters"`
+	Auth    AuthConfig    `mapstructure:"auth"`
+}
+
+// ToolPrefix returns the prefix of the upstream's tool and resource names
+func (u UpstreamConfig) ToolPrefix() string {
+	if u.Prefix != "" {
+		return u.Prefix
+	}
+	return u.Name + "_"
+}
+
+// ForTenant returns a copy of the configuration scoped to the given tenant
+func (c *Config) ForTenant(tenant TenantConfig) *Config {
+	scoped := *c
+	scoped.OpenAPI = tenant.OpenAPI
+	scoped.Filters = tenant.Filters
+	scoped.Auth = tenant.Auth
+	scoped.Tenants = nil
+	scoped.Aggregate = nil
+	return &scoped
+}
+
+// ForUpstream returns a copy of the configuration scoped to the given aggregated upstream
+func (c *Config) ForUpstream(upstream UpstreamConfig) *Config {
+	scoped := *c
+	scoped.OpenAPI = upstream.OpenAPI
+	scoped.Filters = upstream.Filters
+	scoped.Auth = upstream.Auth
+	scoped.Tenants = nil
+	scoped.Aggregate = nil
+	return &scoped
 }
 
 // Load loads configuration from file and environment variables
@@ -69,7 +444,12 @@ func Load(configPath string) (*Config, error) {
 	viper.AutomaticEnv()
 
 	var config Config
-	if err := viper.Unmarshal(&config); err != nil {
+	decodeHooks := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		secrets.DecodeHook(),
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	))
+	if err := viper.Unmarshal(&config, decodeHooks); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
@@ -87,27 +467,322 @@ func setDefaults() {
 	viper.SetDefault("server.port", 8080)
 	viper.SetDefault("openapi.spec_path", "./examples/petstore.yaml")
 	viper.SetDefault("openapi.base_url", "https://petstore3.swagger.io/api/v3")
+	viper.SetDefault("openapi.graphql.endpoint", "/graphql")
+	viper.SetDefault("openapi.graphql.max_depth", 2)
+	viper.SetDefault("openapi.grpc.timeout", "30s")
+	viper.SetDefault("openapi.asyncapi.buffer_size", 100)
+	viper.SetDefault("openapi.odata.enabled", false)
+	viper.SetDefault("openapi.odata.max_pages", 5)
+	viper.SetDefault("openapi.jsonapi.enabled", false)
+	viper.SetDefault("openapi.jsonapi.flatten_included", false)
 	viper.SetDefault("mcp.server_name", "api-to-mcp")
 	viper.SetDefault("mcp.version", "1.0.0")
 	viper.SetDefault("logging.level", "info")
 	viper.SetDefault("logging.format", "json")
+	viper.SetDefault("logging.output", "stderr")
+	viper.SetDefault("logging.rotation.max_size_mb", 100)
+	viper.SetDefault("logging.rotation.max_age", "24h")
+	viper.SetDefault("logging.rotation.max_backups", 7)
+	viper.SetDefault("logging.access.output", "")
+	viper.SetDefault("logging.audit.output", "")
+	viper.SetDefault("logging.redaction.enabled", true)
+	viper.SetDefault("logging.redaction.fields", DefaultRedactedFields)
+	viper.SetDefault("logging.redaction.mask", "[REDACTED]")
+	viper.SetDefault("metrics.enabled", true)
+	viper.SetDefault("metrics.max_tools", 500)
+	viper.SetDefault("admin.enabled", false)
+	viper.SetDefault("functions.enabled", false)
+	viper.SetDefault("docs.enabled", false)
+	viper.SetDefault("inbound_auth.enabled", false)
+	viper.SetDefault("safety.read_only", false)
+	viper.SetDefault("safety.outbound.allowed_hosts", []string{})
+	viper.SetDefault("safety.outbound.allowed_cidrs", []string{})
+	viper.SetDefault("safety.outbound.allow_private", false)
+	viper.SetDefault("safety.response_redaction.enabled", false)
+	viper.SetDefault("safety.response_redaction.fields", DefaultResponseRedactedFields)
+	viper.SetDefault("safety.response_redaction.mask", "[REDACTED]")
+	viper.SetDefault("safety.policy.enabled", false)
+	viper.SetDefault("safety.write_budget.enabled", false)
+	viper.SetDefault("safety.write_budget.max_writes", 10)
+	viper.SetDefault("safety.write_budget.window", "1m")
+	viper.SetDefault("safety.write_budget.methods", []string{"POST", "PUT", "PATCH", "DELETE"})
+	viper.SetDefault("safety.write_budget.session_header", "Mcp-Session-Id")
+	viper.SetDefault("safety.confirmation.enabled", false)
+	viper.SetDefault("safety.confirmation.methods", []string{"DELETE"})
+	viper.SetDefault("safety.confirmation.paths", []string{})
+	viper.SetDefault("safety.confirmation.ttl", "5m")
+	viper.SetDefault("health.enabled", false)
+	viper.SetDefault("health.interval", "30s")
+	viper.SetDefault("health.timeout", "5s")
+	viper.SetDefault("health.degraded_descriptions", false)
+	viper.SetDefault("observability.correlation_header", "X-Request-ID")
+	viper.SetDefault("observability.slow_call_threshold", "10s")
+	viper.SetDefault("observability.large_response_bytes", 1048576)
+	viper.SetDefault("observability.sentry.dsn", "")
+	viper.SetDefault("observability.sentry.burst_threshold", 5)
+	viper.SetDefault("observability.sentry.burst_window", "1m")
 }
 
 // validateConfig validates the configuration
 func validateConfig(config *Config) error {
-	if config.OpenAPI.SpecPath == "" {
-		return fmt.Errorf("openapi.spec_path is required")
+	if config.OpenAPI.GraphQL.Enabled && config.OpenAPI.GRPC.Enabled {
+		return fmt.Errorf("openapi.graphql and openapi.grpc cannot both be enabled")
 	}
+	if len(config.Aggregate) > 0 {
+		// Aggregated upstreams replace the top-level specification
+	} else if config.OpenAPI.GraphQL.Enabled {
+		if err := validateGraphQL("openapi.graphql", config.OpenAPI.GraphQL); err != nil {
+			return err
+		}
+	} else if config.OpenAPI.GRPC.Enabled {
+		if err := validateGRPC("openapi.grpc", config.OpenAPI.GRPC); err != nil {
+			return err
+		}
+	} else {
+		if config.OpenAPI.SpecPath == "" {
+			return fmt.Errorf("openapi.spec_path is required")
+		}
 
-	// Check if spec file exists
-	if _, err := os.Stat(config.OpenAPI.SpecPath); os.IsNotExist(err) {
-		return fmt.Errorf("openapi spec file not found: %s", config.OpenAPI.SpecPath)
+		// Check if spec file exists
+		if _, err := os.Stat(config.OpenAPI.SpecPath); os.IsNotExist(err) {
+			return fmt.Errorf("openapi spec file not found: %s", config.OpenAPI.SpecPath)
+		}
 	}
 
 	if config.Server.Port <= 0 || config.Server.Port > 65535 {
 		return fmt.Errorf("invalid server port: %d", config.Server.Port)
 	}
 
+	if err := validateManifest("openapi.manifest", config.OpenAPI.Manifest); err != nil {
+		return err
+	}
+
+	if err := validateAsyncAPI("openapi.asyncapi", config.OpenAPI.AsyncAPI); err != nil {
+		return err
+	}
+
+	if err := validateSOAP("openapi.soap", config.OpenAPI.SOAP); err != nil {
+		return err
+	}
+
+	if config.OpenAPI.OData.MaxPages < 0 {
+		return fmt.Errorf("openapi.odata.max_pages must not be negative")
+	}
+
+	if budget := config.Safety.WriteBudget; budget.Enabled && (budget.MaxWrites < 1 || budget.Window <= 0) {
+		return fmt.Errorf("safety.write_budget requires a positive max_writes and window")
+	}
+
+	if err := validateTenants(config.Tenants); err != nil {
+		return err
+	}
+
+	if err := validateAggregate(config.Aggregate, config.Tenants); err != nil {
+		return err
+	}
+
+	if err := validateInbound(config.Inbound, config.RBAC); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateTenants validates the tenant namespaces
+func validateTenants(tenants []TenantConfig) error {
+	seen := make(map[string]bool)
+	for i, tenant := range tenants {
+		if tenant.Name == "" {
+			return fmt.Errorf("tenants[%d].name is required", i)
+		}
+		if !tenantNamePattern.MatchString(tenant.Name) {
+			return fmt.Errorf("tenants[%d].name must contain only letters, digits, '-' and '_': %s", i, tenant.Name)
+		}
+		if seen[tenant.Name] {
+			return fmt.Errorf("duplicate tenant name: %s", tenant.Name)
+		}
+		seen[tenant.Name] = true
+
+		if err := validateNamespaceOpenAPI(fmt.Sprintf("tenants[%d].openapi", i), tenant.OpenAPI); err != nil {
+			return err
+		}
+		if tenant.RateLimit.RequestsPerSecond < 0 || tenant.RateLimit.Burst < 0 {
+			return fmt.Errorf("tenants[%d].rate_limit values must not be negative", i)
+		}
+	}
+	return nil
+}
+
+// validateAggregate validates the upstreams merged into the default namespace; their names
+// also name webhook paths, so they must not clash with tenants
+func validateAggregate(upstreams []UpstreamConfig, tenants []TenantConfig) error {
+	seen := make(map[string]bool)
+	for _, tenant := range tenants {
+		seen[tenant.Name] = true
+	}
+	for i, upstream := range upstreams {
+		if upstream.Name == "" {
+			return fmt.Errorf("aggregate[%d].name is required", i)
+		}
+		if !tenantNamePattern.MatchString(upstream.Name) {
+			return fmt.Errorf("aggregate[%d].name must contain only letters, digits, '-' and '_': %s", i, upstream.Name)
+		}
+		if seen[upstream.Name] {
+			return fmt.Errorf("aggregate[%d].name %s is already used by a tenant or upstream", i, upstream.Name)
+		}
+		seen[upstream.Name] = true
+
+		if upstream.Prefix != "" && !tenantNamePattern.MatchString(upstream.Prefix) {
+			return fmt.Errorf("aggregate[%d].prefix must contain only letters, digits, '-' and '_': %s", i, upstream.Prefix)
+		}
+		if err := validateNamespaceOpenAPI(fmt.Sprintf("aggregate[%d].openapi", i), upstream.OpenAPI); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateNamespaceOpenAPI validates the openapi section of a tenant or aggregated upstream
+func validateNamespaceOpenAPI(path string, openAPI OpenAPIConfig) error {
+	if openAPI.GraphQL.Enabled && openAPI.GRPC.Enabled {
+		return fmt.Errorf("%s.graphql and %s.grpc cannot both be enabled", path, path)
+	}
+	if openAPI.GraphQL.Enabled {
+		if err := validateGraphQL(path+".graphql", openAPI.GraphQL); err != nil {
+			return err
+		}
+	} else if openAPI.GRPC.Enabled {
+		if err := validateGRPC(path+".grpc", openAPI.GRPC); err != nil {
+			return err
+		}
+	} else {
+		if openAPI.SpecPath == "" {
+			return fmt.Errorf("%s.spec_path is required", path)
+		}
+		if _, err := os.Stat(openAPI.SpecPath); os.IsNotExist(err) {
+			return fmt.Errorf("%s.spec_path not found: %s", path, openAPI.SpecPath)
+		}
+	}
+	if err := validateManifest(path+".manifest", openAPI.Manifest); err != nil {
+		return err
+	}
+	if err := validateAsyncAPI(path+".asyncapi", openAPI.AsyncAPI); err != nil {
+		return err
+	}
+	if err := validateSOAP(path+".soap", openAPI.SOAP); err != nil {
+		return err
+	}
+	if openAPI.OData.MaxPages < 0 {
+		return fmt.Errorf("%s.odata.max_pages must not be negative", path)
+	}
+	return nil
+}
+
+// validateGraphQL validates a GraphQL upstream
+func validateGraphQL(key string, graphql GraphQLConfig) error {
+	if graphql.SchemaPath != "" {
+		if _, err := os.Stat(graphql.SchemaPath); os.IsNotExist(err) {
+			return fmt.Errorf("%s.schema_path not found: %s", key, graphql.SchemaPath)
+		}
+	}
+	if graphql.MaxDepth < 0 {
+		return fmt.Errorf("%s.max_depth must not be negative", key)
+	}
+	return nil
+}
+
+// validateGRPC validates a gRPC upstream
+func validateGRPC(key string, grpc GRPCConfig) error {
+	if grpc.Target == "" {
+		return fmt.Errorf("%s.target is required", key)
+	}
+	if grpc.DescriptorSet != "" {
+		if _, err := os.Stat(grpc.DescriptorSet); os.IsNotExist(err) {
+			return fmt.Errorf("%s.descriptor_set not found: %s", key, grpc.DescriptorSet)
+		}
+	}
+	for _, pattern := range grpc.ReadMethods {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s.read_methods has invalid pattern %q: %w", key, pattern, err)
+		}
+	}
+	if grpc.Timeout < 0 {
+		return fmt.Errorf("%s.timeout must not be negative", key)
+	}
+	return nil
+}
+
+// validateAsyncAPI validates the event-driven upstream settings
+func validateAsyncAPI(key string, asyncapi AsyncAPIConfig) error {
+	if asyncapi.BufferSize < 0 {
+		return fmt.Errorf("%s.buffer_size must not be negative", key)
+	}
+	if asyncapi.MQTT.QoS < 0 || asyncapi.MQTT.QoS > 2 {
+		return fmt.Errorf("%s.mqtt.qos must be 0, 1 or 2", key)
+	}
+	return nil
+}
+
+// validateSOAP validates the SOAP upstream settings
+func validateSOAP(key string, soap SOAPConfig) error {
+	for _, pattern := range soap.ReadOperations {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("%s.read_operations has invalid pattern %q: %w", key, pattern, err)
+		}
+	}
+	return nil
+}
+
+// validateManifest validates a manifest pin
+func validateManifest(key string, manifest ManifestConfig) error {
+	if manifest.Path == "" {
+		return nil
+	}
+	if manifest.PublicKey == "" {
+		return fmt.Errorf("%s.public_key is required when %s.path is set", key, key)
+	}
+	return nil
+}
+
+// validateInbound validates inbound clients and the roles they reference
+func validateInbound(inbound InboundConfig, rbac RBACConfig) error {
+	roles := make(map[string]bool)
+	for i, role := range rbac.Roles {
+		if role.Name == "" {
+			return fmt.Errorf("rbac.roles[%d].name is required", i)
+		}
+		if roles[role.Name] {
+			return fmt.Errorf("duplicate rbac role: %s", role.Name)
+		}
+		roles[role.Name] = true
+	}
+
+	if !inbound.Enabled {
+		return nil
+	}
+	if len(inbound.Clients) == 0 {
+		return fmt.Errorf("inbound_auth.clients must not be empty when inbound_auth is enabled")
+	}
+
+	ids := make(map[string]bool)
+	for i, client := range inbound.Clients {
+		if client.ID == "" {
+			return fmt.Errorf("inbound_auth.clients[%d].id is required", i)
+		}
+		if ids[client.ID] {
+			return fmt.Errorf("duplicate inbound client id: %s", client.ID)
+		}
+		ids[client.ID] = true
+
+		if !client.Token.IsSet() {
+			return fmt.Errorf("inbound_auth.clients[%d].token is required", i)
+		}
+		for _, role := range client.Roles {
+			if !roles[role] {
+				return fmt.Errorf("inbound client %s references unknown role: %s", client.ID, role)
+			}
+		}
+	}
 	return nil
 }
 
@@ -120,35 +795,265 @@ func GetConfigPath() string {
 	return "config.yaml"
 }
 
-// CreateDefaultConfig creates a default configuration file
-func CreateDefaultConfig(path string) error {
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create config directory: %w", err)
+// StarterConfig holds the values used to render a starter configuration file
+type StarterConfig struct {
+	SpecPath     string
+	BaseURL      string
+	ServerName   string
+	ExamplePaths []string
+	// AuthType pre-selects upstream authentication declared by the source spec
+	AuthType string
+}
+
+// DefaultStarterConfig returns the starter values pointing at the bundled petstore example
+func DefaultStarterConfig() StarterConfig {
+	return StarterConfig{
+		SpecPath:   "./examples/petstore.yaml",
+		BaseURL:    "https://petstore3.swagger.io/api/v3",
+		ServerName: "api-to-mcp",
 	}
+}
 
-	config := `server:
+// starterTemplate is the commented configuration written by CreateDefaultConfig and WriteStarterConfig
+var starterTemplate = template.Must(template.New("config").Parse(`# API-to-MCP configuration
+# Every value can also be set through environment variables (e.g. SERVER_PORT).
+
+server:
+  # Address the JSON-RPC server listens on
   host: localhost
   port: 8080
 
 openapi:
-  spec_path: ./examples/petstore.yaml
-  base_url: https://petstore3.swagger.io/api/v3
+  # OpenAPI 3.x specification (YAML or JSON) used to generate tools
+  spec_path: {{.SpecPath}}
+  # Base URL of the upstream API that tools call
+  base_url: {{.BaseURL}}
+  # Authenticated endpoint used by 'api-to-mcp doctor' to check credentials
+  probe_path: ""
+  # Refuse to serve unless the generated tools match this manifest and its signature verifies
+  # (see 'api-to-mcp keygen' and 'api-to-mcp export --sign-key')
+  manifest:
+    path: ""
+    signature: ""
+    public_key: ""
+  # Generate tools from a GraphQL API instead of spec_path: one tool per query and mutation
+  graphql:
+    enabled: false
+    # Endpoint relative to base_url
+    endpoint: /graphql
+    # SDL file or saved introspection result; the endpoint is introspected when empty
+    schema_path: ""
+    # Depth of nested object fields selected in tool results
+    max_depth: 2
+    # Omit the graphql_query tool that runs arbitrary documents
+    disable_query_tool: false
+  # Generate tools from the unary methods of a gRPC server instead of spec_path
+  grpc:
+    enabled: false
+    target: ""
+    # FileDescriptorSet from protoc --descriptor_set_out --include_imports; reflection when empty
+    descriptor_set: ""
+    # Fully-qualified services to expose (default: all)
+    services: []
+    plaintext: false
+    # Method name patterns treated as read-only, e.g. ["Get*", "List*"]
+    read_methods: []
+    timeout: 30s
+  # Broker settings used when spec_path is an AsyncAPI document
+  asyncapi:
+    # Server from the document's servers (default: the first)
+    server: ""
+    url: ""
+    # Recent messages kept per subscribed channel
+    buffer_size: 100
+    # Require X-Signature-256 on inbound webhook deliveries
+    # webhook_secret: ${WEBHOOK_SECRET}
+    mqtt:
+      client_id: ""
+      username: ""
+      # password: ${MQTT_PASSWORD}
+      qos: 0
+  # Service settings used when spec_path is a WSDL document
+  soap:
+    # Service and port from the WSDL (default: the first)
+    service: ""
+    port: ""
+    # Overrides the port's soap:address location
+    endpoint: ""
+    # Operation name patterns treated as read-only, e.g. ["Get*", "Find*"]
+    read_operations: []
+  # OData conventions for endpoints declaring $filter, $select, $top, $skip, ...:
+  # described query options, $filter syntax checks and @odata.nextLink paging
+  odata:
+    enabled: false
+    # Pages fetched per call by following @odata.nextLink (1: first page only)
+    max_pages: 5
+  # JSON:API conventions: page[number]/page[size] on collections and plain resource objects
+  # in results
+  jsonapi:
+    enabled: false
+    # Replace relationship identifiers with the included resources they name
+    flatten_included: false
 
 mcp:
-  server_name: api-to-mcp
+  server_name: {{.ServerName}}
   version: 1.0.0
 
+# Endpoint filters: paths match by prefix, methods are case-insensitive
 filters:
   include_paths: []
+{{- range .ExamplePaths}}
+  #  - {{.}}
+{{- end}}
   exclude_paths: []
   include_methods: []
+  #  - GET
   exclude_methods: []
+  #  - DELETE
 
 logging:
+  # debug, info, warn or error
   level: info
+  # json or text
   format: json
-`
+  # Application logs: stdout, stderr or a file path
+  output: stderr
+  # Rotation of file outputs (0 disables a limit)
+  rotation:
+    max_size_mb: 100
+    max_age: 24h
+    max_backups: 7
+  # HTTP access log and tool call audit log; empty disables
+  access:
+    output: ""
+  audit:
+    output: ""
+  # Mask sensitive values in all log output
+  redaction:
+    enabled: true
+    # Case-insensitive regular expressions matched against keys at any depth
+    fields: ["^(password|passwd|secret|client_secret)$", "^(token|access_token|refresh_token|id_token)$", "^(authorization|cookie|set-cookie)$", "^x?-?api[-_]?key$"]
+    # JSONPath-style locations, e.g. $.arguments.body.card.number or $.arguments.items[*].ssn
+    paths: []
+    # Regular expressions masked inside string values and messages
+    patterns: []
+    mask: "[REDACTED]"
+
+# Upstream API authentication: bearer or apikey
+# Tokens here and in admin/inbound_auth may be env:NAME, ${NAME} or file:/path; they are
+# resolved when used and never printed
+auth:
+  type: "{{.AuthType}}"
+  token: "{{if .AuthType}}env:API_TOKEN{{end}}"
+
+# Additional isolated API namespaces served under /mcp/<name>
+tenants: []
+
+# Specifications merged into the default namespace, replacing openapi; each upstream's tools
+# are named with its prefix (default: <name>_)
+aggregate: []
+
+# Prometheus metrics served at /metrics; tools beyond max_tools share the "_other" label
+metrics:
+  enabled: true
+  max_tools: 500
+
+# Authenticate MCP clients with bearer tokens; roles limit the tools each client can see and call
+inbound_auth:
+  enabled: false
+  clients: []
+rbac:
+  roles: []
+
+# Admin API served under /admin/, protected by a bearer token when set
+admin:
+  enabled: false
+  token: ""
+
+# Serve tool definitions and execute tool calls in the OpenAI and Anthropic formats under /functions/
+functions:
+  enabled: false
+
+# Serve HTML documentation of the generated tools under /docs and /docs/<namespace>
+docs:
+  enabled: false
+
+safety:
+  # Only generate and execute GET/HEAD tools
+  read_only: false
+  # Outbound requests: any host unless allowed_hosts is set (exact or *.example.com);
+  # private, loopback and link-local addresses need allow_private or an allowed CIDR
+  outbound:
+    allowed_hosts: []
+    allowed_cidrs: []
+    allow_private: false
+  # Tools matching these methods or path patterns (* one segment, ** any) return a
+  # challenge and only run when repeated with the issued token
+  confirmation:
+    enabled: false
+    methods: [DELETE]
+    paths: []
+    ttl: 5m
+  # Mask sensitive fields in upstream responses before they are returned; paths are
+  # relative to the response body, e.g. $.user.ssn or $[*].card.number
+  response_redaction:
+    enabled: false
+    fields: ["^(password|password_hash|passwd|secret|client_secret)$", "^(ssn|social_security_number)$", "^(api_key|apikey|private_key)$"]
+    paths: []
+    patterns: []
+    mask: "[REDACTED]"
+  # Rules evaluated in order before each call; a true deny expression rejects the call and
+  # set rewrites arguments. Expressions see tool, namespace, method, path, client and arguments
+  policy:
+    enabled: false
+    rules: []
+  # At most max_writes mutating calls per session within window; sessions are the
+  # authenticated client, then the session_header value, then the remote address
+  write_budget:
+    enabled: false
+    max_writes: 10
+    window: 1m
+    methods: [POST, PUT, PATCH, DELETE]
+    session_header: Mcp-Session-Id
+
+# Periodic upstream probes reported at /readyz; uses openapi.probe_path or HEAD /
+health:
+  enabled: false
+  interval: 30s
+  timeout: 5s
+  # Prefix tool descriptions with a notice while their upstream is down
+  degraded_descriptions: false
+
+observability:
+  # Header carrying tool call correlation IDs, accepted inbound and propagated upstream
+  correlation_header: X-Request-ID
+  # Log a warning when a tool call takes longer or its response is larger (0 disables)
+  slow_call_threshold: 10s
+  large_response_bytes: 1048576
+  # Report panics, tool generation failures and upstream 5xx bursts to a Sentry-compatible DSN
+  sentry:
+    dsn: ""
+    environment: ""
+    burst_threshold: 5
+    burst_window: 1m
+`))
+
+// CreateDefaultConfig creates a default configuration file
+func CreateDefaultConfig(path string) error {
+	return WriteStarterConfig(path, DefaultStarterConfig())
+}
+
+// WriteStarterConfig renders a commented starter configuration file from the given values
+func WriteStarterConfig(path string, starter StarterConfig) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := starterTemplate.Execute(&buf, starter); err != nil {
+		return fmt.Errorf("failed to render config: %w", err)
+	}
 
-	return os.WriteFile(path, []byte(config), 0644)
+	return os.WriteFile(path, buf.Bytes(), 0644)
 }
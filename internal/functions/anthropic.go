@@ -0,0 +1,129 @@
+package functions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"api-to-mcp/pkg/mcp"
+)
+
+// AnthropicTool is a tool definition for the tools parameter of the Anthropic Messages API
+type AnthropicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	InputSchema Schema `json:"input_schema"`
+}
+
+// AnthropicContentBlock is a content block of a message; tool_use blocks carry tool calls
+type AnthropicContentBlock struct {
+	Type  string          `json:"type"`
+	ID    string          `json:"id,omitempty"`
+	Name  string          `json:"name,omitempty"`
+	Input json.RawMessage `json:"input,omitempty"`
+}
+
+// AnthropicToolResult is a tool_result block returning a tool call's result to the model
+type AnthropicToolResult struct {
+	Type      string `json:"type"`
+	ToolUseID string `json:"tool_use_id"`
+	Content   string `json:"content"`
+	IsError   bool   `json:"is_error,omitempty"`
+}
+
+// AnthropicMessage is a message carrying tool results
+type AnthropicMessage struct {
+	Role    string                `json:"role"`
+	Content []AnthropicToolResult `json:"content"`
+}
+
+// anthropicFormat is the Anthropic tool-use format
+var anthropicFormat = &Format{
+	Name: "anthropic",
+	Tools: func(tools []mcp.Tool) (interface{}, error) {
+		return AnthropicTools(tools)
+	},
+	DecodeCalls:   decodeAnthropicCalls,
+	EncodeResults: encodeAnthropicResults,
+}
+
+// AnthropicTools converts tools to Anthropic tool definitions
+func AnthropicTools(tools []mcp.Tool) ([]AnthropicTool, error) {
+	if err := CheckNames(tools); err != nil {
+		return nil, err
+	}
+	definitions := make([]AnthropicTool, 0, len(tools))
+	for _, tool := range tools {
+		definitions = append(definitions, AnthropicTool{
+			Name:        Name(tool.Name),
+			Description: tool.Description,
+			InputSchema: Parameters(tool.InputSchema),
+		})
+	}
+	return definitions, nil
+}
+
+// decodeAnthropicCalls reads the tool_use blocks of a content block, a list of content blocks,
+// or a message or Messages API response with content; other blocks such as text are skipped
+func decodeAnthropicCalls(data []byte) ([]Call, error) {
+	var blocks []AnthropicContentBlock
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) > 0 && data[0] == '[':
+		if err := json.Unmarshal(data, &blocks); err != nil {
+			return nil, fmt.Errorf("invalid content blocks: %w", err)
+		}
+	default:
+		var message struct {
+			Content json.RawMessage `json:"content"`
+		}
+		if err := json.Unmarshal(data, &message); err != nil {
+			return nil, fmt.Errorf("invalid tool use: %w", err)
+		}
+		if bytes.HasPrefix(bytes.TrimSpace(message.Content), []byte("[")) {
+			if err := json.Unmarshal(message.Content, &blocks); err != nil {
+				return nil, fmt.Errorf("invalid message content: %w", err)
+			}
+			break
+		}
+		var block AnthropicContentBlock
+		if err := json.Unmarshal(data, &block); err != nil {
+			return nil, fmt.Errorf("invalid tool use: %w", err)
+		}
+		blocks = []AnthropicContentBlock{block}
+	}
+
+	calls := make([]Call, 0, len(blocks))
+	for _, block := range blocks {
+		if block.Type != "tool_use" {
+			continue
+		}
+		if block.Name == "" {
+			return nil, fmt.Errorf("tool use %s has no name", block.ID)
+		}
+		arguments, err := decodeArguments(block.Input)
+		if err != nil {
+			return nil, fmt.Errorf("tool use %s: %w", block.ID, err)
+		}
+		calls = append(calls, Call{ID: block.ID, Name: block.Name, Arguments: arguments})
+	}
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("no tool_use blocks found")
+	}
+	return calls, nil
+}
+
+// encodeAnthropicResults builds the user message answering every tool use, since the API expects
+// all results of a turn in one message
+func encodeAnthropicResults(results []Result) interface{} {
+	message := AnthropicMessage{Role: "user", Content: make([]AnthropicToolResult, 0, len(results))}
+	for _, result := range results {
+		message.Content = append(message.Content, AnthropicToolResult{
+			Type:      "tool_result",
+			ToolUseID: result.Call.ID,
+			Content:   result.Content,
+			IsError:   result.IsError,
+		})
+	}
+	return message
+}
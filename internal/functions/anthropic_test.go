@@ -0,0 +1,92 @@
+package functions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnthropicTools(t *testing.T) {
+	definitions, err := AnthropicTools(testTools())
+	require.NoError(t, err)
+
+	data, err := json.Marshal(definitions)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{
+			"name": "getpetbyid",
+			"description": "Find pet by ID",
+			"input_schema": {
+				"type": "object",
+				"properties": {
+					"petId": {"type": "integer", "format": "int64"},
+					"tags": {"type": "array", "description": "Tags to match", "items": {}}
+				},
+				"required": ["petId"]
+			}
+		},
+		{
+			"name": "pets_list-all",
+			"description": "List pets",
+			"input_schema": {"type": "object", "properties": {}}
+		}
+	]`, string(data))
+
+	_, err = AnthropicTools([]mcp.Tool{{Name: "a.b"}, {Name: "a_b"}})
+	assert.ErrorContains(t, err, "both map to function name a_b")
+}
+
+func TestDecodeAnthropicCalls(t *testing.T) {
+	block := `{"type": "tool_use", "id": "toolu_1", "name": "getpetbyid", "input": {"petId": 7}}`
+	calls, err := decodeAnthropicCalls([]byte(block))
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	assert.Equal(t, Call{ID: "toolu_1", Name: "getpetbyid", Arguments: map[string]interface{}{"petId": 7.0}}, calls[0])
+
+	// A Messages API response mixes text and tool_use blocks
+	response := `{
+		"id": "msg_1", "type": "message", "role": "assistant", "stop_reason": "tool_use",
+		"content": [
+			{"type": "text", "text": "Looking that up"},
+			` + block + `,
+			{"type": "tool_use", "id": "toolu_2", "name": "pets_list-all", "input": {}}
+		]
+	}`
+	calls, err = decodeAnthropicCalls([]byte(response))
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	assert.Equal(t, "toolu_2", calls[1].ID)
+	assert.Empty(t, calls[1].Arguments)
+
+	calls, err = decodeAnthropicCalls([]byte(`[` + block + `]`))
+	require.NoError(t, err)
+	assert.Len(t, calls, 1)
+
+	_, err = decodeAnthropicCalls([]byte(`{"role": "assistant", "content": "just text"}`))
+	assert.ErrorContains(t, err, "no tool_use blocks found")
+	_, err = decodeAnthropicCalls([]byte(`{"type": "tool_use", "id": "toolu_3", "name": "x", "input": [1]}`))
+	assert.ErrorContains(t, err, "tool use toolu_3: arguments must be a JSON object")
+	_, err = decodeAnthropicCalls([]byte(`{"type": "tool_use", "id": "toolu_4"}`))
+	assert.ErrorContains(t, err, "tool use toolu_4 has no name")
+}
+
+func TestEncodeAnthropicResults(t *testing.T) {
+	results := []Result{
+		NewResult(Call{ID: "toolu_1"}, map[string]interface{}{"id": 7}),
+		NewResult(Call{ID: "toolu_2"}, mcp.NewError(mcp.MethodNotFound, "Tool not found: x", nil)),
+	}
+
+	data, err := json.Marshal(encodeAnthropicResults(results))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"role": "user",
+		"content": [
+			{"type": "tool_result", "tool_use_id": "toolu_1", "content": "{\"id\":7}"},
+			{"type": "tool_result", "tool_use_id": "toolu_2", "content": "{\"error\":{\"code\":-32601,\"message\":\"Tool not found: x\"}}", "is_error": true}
+		]
+	}`, string(data))
+}
@@ -0,0 +1,175 @@
+package functions
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"api-to-mcp/pkg/mcp"
+)
+
+// maxNameLength is the longest function name model APIs accept
+const maxNameLength = 64
+
+// invalidNameChars matches characters function names may not contain
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]`)
+
+// Format converts tools and tool calls to and from a model API's function-calling format
+type Format struct {
+	Name string
+	// Tools converts tools to the API's tool definitions
+	Tools func(tools []mcp.Tool) (interface{}, error)
+	// DecodeCalls reads the tool calls a model made
+	DecodeCalls func(data []byte) ([]Call, error)
+	// EncodeResults builds the reply returning each call's result to the model
+	EncodeResults func(results []Result) interface{}
+}
+
+// Call is a tool call made by a model, addressed by function name
+type Call struct {
+	ID        string
+	Name      string
+	Arguments map[string]interface{}
+}
+
+// Result is the outcome of a call as text for the model
+type Result struct {
+	Call    Call
+	Content string
+	IsError bool
+}
+
+// formats are the supported function-calling formats by name
+var formats = map[string]*Format{
+	"anthropic": anthropicFormat,
+	"openai":    openAIFormat,
+}
+
+// Lookup returns the named format
+func Lookup(name string) (*Format, error) {
+	format, exists := formats[strings.ToLower(name)]
+	if !exists {
+		return nil, fmt.Errorf("unknown function format %q (supported: %s)", name, strings.Join(Names(), ", "))
+	}
+	return format, nil
+}
+
+// Names returns the supported format names in order
+func Names() []string {
+	names := make([]string, 0, len(formats))
+	for name := range formats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Name converts a tool name to a function name model APIs accept: characters other than letters,
+// digits, _ and - become _ and the name is cut to 64 characters
+func Name(tool string) string {
+	name := invalidNameChars.ReplaceAllString(tool, "_")
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+	return name
+}
+
+// Resolve finds the tool a function name refers to
+func Resolve(tools []mcp.Tool, name string) (*mcp.Tool, bool) {
+	for i := range tools {
+		if Name(tools[i].Name) == name {
+			return &tools[i], true
+		}
+	}
+	return nil, false
+}
+
+// CheckNames fails when tools convert to the same function name, since calls could not be told apart
+func CheckNames(tools []mcp.Tool) error {
+	seen := make(map[string]string, len(tools))
+	for _, tool := range tools {
+		name := Name(tool.Name)
+		if other, exists := seen[name]; exists {
+			return fmt.Errorf("tools %s and %s both map to function name %s", other, tool.Name, name)
+		}
+		seen[name] = tool.Name
+	}
+	return nil
+}
+
+// Schema is a JSON schema describing a function's parameters
+type Schema map[string]interface{}
+
+// Parameters converts a tool's input schema to an object schema as model APIs require it: with
+// properties even when there are none, and with items on every array
+func Parameters(schema *mcp.InputSchema) Schema {
+	properties := make(map[string]interface{})
+	params := Schema{"type": "object", "properties": properties}
+	if schema == nil {
+		return params
+	}
+	for name, property := range schema.Properties {
+		properties[name] = propertySchema(property)
+	}
+	if len(schema.Required) > 0 {
+		params["required"] = schema.Required
+	}
+	return params
+}
+
+// propertySchema converts a property to a JSON schema
+func propertySchema(property mcp.Property) map[string]interface{} {
+	converted := make(map[string]interface{})
+	data, err := json.Marshal(property)
+	if err == nil {
+		json.Unmarshal(data, &converted)
+	}
+	if property.Type == "array" {
+		converted["items"] = map[string]interface{}{}
+	}
+	return converted
+}
+
+// NewResult converts a tool call's reply to text: strings are passed through, JSON-RPC errors
+// are marked as errors and anything else is encoded as JSON
+func NewResult(call Call, reply interface{}) Result {
+	result := Result{Call: call}
+	switch value := reply.(type) {
+	case string:
+		result.Content = value
+		return result
+	case *mcp.Error:
+		result.IsError = true
+		reply = map[string]interface{}{"error": value}
+	}
+
+	data, err := json.Marshal(reply)
+	if err != nil {
+		result.IsError = true
+		result.Content = fmt.Sprintf("failed to encode tool result: %v", err)
+		return result
+	}
+	result.Content = string(data)
+	return result
+}
+
+// decodeArguments decodes arguments sent as a JSON object or a string holding one
+func decodeArguments(raw json.RawMessage) (map[string]interface{}, error) {
+	arguments := make(map[string]interface{})
+	if len(raw) == 0 || string(raw) == "null" {
+		return arguments, nil
+	}
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err == nil {
+		if strings.TrimSpace(encoded) == "" {
+			return arguments, nil
+		}
+		raw = json.RawMessage(encoded)
+	}
+	if err := json.Unmarshal(raw, &arguments); err != nil {
+		return nil, fmt.Errorf("arguments must be a JSON object: %w", err)
+	}
+	return arguments, nil
+}
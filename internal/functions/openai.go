@@ -0,0 +1,121 @@
+package functions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"api-to-mcp/pkg/mcp"
+)
+
+// OpenAITool is a tool definition for the tools parameter of the OpenAI Chat Completions API
+type OpenAITool struct {
+	Type     string         `json:"type"`
+	Function OpenAIFunction `json:"function"`
+}
+
+// OpenAIFunction describes a function a model may call
+type OpenAIFunction struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Parameters  Schema `json:"parameters"`
+}
+
+// OpenAIToolCall is a tool call from an assistant message; arguments are a JSON-encoded object
+type OpenAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// OpenAIToolMessage returns a tool call's result to the model
+type OpenAIToolMessage struct {
+	Role       string `json:"role"`
+	ToolCallID string `json:"tool_call_id"`
+	Content    string `json:"content"`
+}
+
+// openAIFormat is the OpenAI function-calling format
+var openAIFormat = &Format{
+	Name: "openai",
+	Tools: func(tools []mcp.Tool) (interface{}, error) {
+		return OpenAITools(tools)
+	},
+	DecodeCalls:   decodeOpenAICalls,
+	EncodeResults: encodeOpenAIResults,
+}
+
+// OpenAITools converts tools to OpenAI function definitions
+func OpenAITools(tools []mcp.Tool) ([]OpenAITool, error) {
+	if err := CheckNames(tools); err != nil {
+		return nil, err
+	}
+	definitions := make([]OpenAITool, 0, len(tools))
+	for _, tool := range tools {
+		definitions = append(definitions, OpenAITool{
+			Type: "function",
+			Function: OpenAIFunction{
+				Name:        Name(tool.Name),
+				Description: tool.Description,
+				Parameters:  Parameters(tool.InputSchema),
+			},
+		})
+	}
+	return definitions, nil
+}
+
+// decodeOpenAICalls reads a tool call, a list of tool calls or an assistant message with tool_calls
+func decodeOpenAICalls(data []byte) ([]Call, error) {
+	var toolCalls []OpenAIToolCall
+	data = bytes.TrimSpace(data)
+	switch {
+	case len(data) > 0 && data[0] == '[':
+		if err := json.Unmarshal(data, &toolCalls); err != nil {
+			return nil, fmt.Errorf("invalid tool calls: %w", err)
+		}
+	default:
+		var message struct {
+			ToolCalls []OpenAIToolCall `json:"tool_calls"`
+		}
+		if err := json.Unmarshal(data, &message); err != nil {
+			return nil, fmt.Errorf("invalid tool call: %w", err)
+		}
+		toolCalls = message.ToolCalls
+		if message.ToolCalls == nil {
+			var toolCall OpenAIToolCall
+			if err := json.Unmarshal(data, &toolCall); err != nil {
+				return nil, fmt.Errorf("invalid tool call: %w", err)
+			}
+			toolCalls = []OpenAIToolCall{toolCall}
+		}
+	}
+
+	calls := make([]Call, 0, len(toolCalls))
+	for _, toolCall := range toolCalls {
+		if toolCall.Function.Name == "" {
+			return nil, fmt.Errorf("tool call %s has no function name", toolCall.ID)
+		}
+		arguments, err := decodeArguments(toolCall.Function.Arguments)
+		if err != nil {
+			return nil, fmt.Errorf("tool call %s: %w", toolCall.ID, err)
+		}
+		calls = append(calls, Call{ID: toolCall.ID, Name: toolCall.Function.Name, Arguments: arguments})
+	}
+	return calls, nil
+}
+
+// encodeOpenAIResults builds one tool message per call, ready to append to the conversation
+func encodeOpenAIResults(results []Result) interface{} {
+	messages := make([]OpenAIToolMessage, 0, len(results))
+	for _, result := range results {
+		messages = append(messages, OpenAIToolMessage{
+			Role:       "tool",
+			ToolCallID: result.Call.ID,
+			Content:    result.Content,
+		})
+	}
+	return messages
+}
@@ -0,0 +1,131 @@
+package functions
+
+import (
+	"encoding/json"
+	"testing"
+
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "getpetbyid",
+			Description: "Find pet by ID",
+			InputSchema: &mcp.InputSchema{
+				Type: "object",
+				Properties: map[string]mcp.Property{
+					"petId": {Type: "integer", Format: "int64"},
+					"tags":  {Type: "array", Description: "Tags to match"},
+				},
+				Required: []string{"petId"},
+			},
+		},
+		{Name: "pets.list-all", Description: "List pets"},
+	}
+}
+
+func TestOpenAITools(t *testing.T) {
+	definitions, err := OpenAITools(testTools())
+	require.NoError(t, err)
+
+	data, err := json.Marshal(definitions)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"type": "function", "function": {
+			"name": "getpetbyid",
+			"description": "Find pet by ID",
+			"parameters": {
+				"type": "object",
+				"properties": {
+					"petId": {"type": "integer", "format": "int64"},
+					"tags": {"type": "array", "description": "Tags to match", "items": {}}
+				},
+				"required": ["petId"]
+			}
+		}},
+		{"type": "function", "function": {
+			"name": "pets_list-all",
+			"description": "List pets",
+			"parameters": {"type": "object", "properties": {}}
+		}}
+	]`, string(data))
+}
+
+func TestOpenAITools_NameCollision(t *testing.T) {
+	_, err := OpenAITools([]mcp.Tool{{Name: "pets.list"}, {Name: "pets_list"}})
+	assert.ErrorContains(t, err, "tools pets.list and pets_list both map to function name pets_list")
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "get_pets__petId_", Name("get pets/{petId}"))
+	long := Name("a_very_long_operation_identifier_that_keeps_going_well_past_the_limit")
+	assert.Len(t, long, maxNameLength)
+
+	tool, found := Resolve(testTools(), "pets_list-all")
+	require.True(t, found)
+	assert.Equal(t, "pets.list-all", tool.Name)
+	_, found = Resolve(testTools(), "missing")
+	assert.False(t, found)
+}
+
+func TestDecodeOpenAICalls(t *testing.T) {
+	single := `{"id": "call_1", "type": "function", "function": {"name": "getpetbyid", "arguments": "{\"petId\": 7}"}}`
+	calls, err := decodeOpenAICalls([]byte(single))
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	assert.Equal(t, Call{ID: "call_1", Name: "getpetbyid", Arguments: map[string]interface{}{"petId": 7.0}}, calls[0])
+
+	calls, err = decodeOpenAICalls([]byte(`[` + single + `, {"id": "call_2", "function": {"name": "pets_list-all", "arguments": ""}}]`))
+	require.NoError(t, err)
+	require.Len(t, calls, 2)
+	assert.Empty(t, calls[1].Arguments)
+
+	calls, err = decodeOpenAICalls([]byte(`{"role": "assistant", "content": null, "tool_calls": [` + single + `]}`))
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	assert.Equal(t, "call_1", calls[0].ID)
+
+	// Arguments may also arrive already decoded
+	calls, err = decodeOpenAICalls([]byte(`{"id": "call_3", "function": {"name": "getpetbyid", "arguments": {"petId": 8}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, 8.0, calls[0].Arguments["petId"])
+
+	_, err = decodeOpenAICalls([]byte(`{"id": "call_4", "function": {"name": "getpetbyid", "arguments": "[1]"}}`))
+	assert.ErrorContains(t, err, "tool call call_4: arguments must be a JSON object")
+	_, err = decodeOpenAICalls([]byte(`{"id": "call_5"}`))
+	assert.ErrorContains(t, err, "tool call call_5 has no function name")
+	_, err = decodeOpenAICalls([]byte(`not json`))
+	assert.ErrorContains(t, err, "invalid tool call")
+}
+
+func TestEncodeOpenAIResults(t *testing.T) {
+	call := Call{ID: "call_1", Name: "getpetbyid"}
+	results := []Result{
+		NewResult(call, map[string]interface{}{"id": 7, "name": "Rex"}),
+		NewResult(Call{ID: "call_2"}, "plain text"),
+		NewResult(Call{ID: "call_3"}, mcp.NewError(mcp.PolicyDenied, "Tool x is not allowed in read-only mode", nil)),
+	}
+	assert.False(t, results[0].IsError)
+	assert.True(t, results[2].IsError)
+
+	data, err := json.Marshal(encodeOpenAIResults(results))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"role": "tool", "tool_call_id": "call_1", "content": "{\"id\":7,\"name\":\"Rex\"}"},
+		{"role": "tool", "tool_call_id": "call_2", "content": "plain text"},
+		{"role": "tool", "tool_call_id": "call_3", "content": "{\"error\":{\"code\":-32001,\"message\":\"Tool x is not allowed in read-only mode\"}}"}
+	]`, string(data))
+}
+
+func TestLookup(t *testing.T) {
+	format, err := Lookup("OpenAI")
+	require.NoError(t, err)
+	assert.Equal(t, "openai", format.Name)
+
+	_, err = Lookup("cohere")
+	assert.ErrorContains(t, err, `unknown function format "cohere" (supported: anthropic, openai)`)
+}
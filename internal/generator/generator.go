@@ -1,10 +1,19 @@
 package generator
 
 import (
+	"context"
 	"fmt"
+	neturl "net/url"
+	"regexp"
+	"sort"
 	"strings"
 
 	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/jsonapi"
+	"api-to-mcp/internal/netguard"
+	"api-to-mcp/internal/odata"
+	"api-to-mcp/internal/redact"
+	"api-to-mcp/internal/safety"
 	"api-to-mcp/internal/utils"
 	"api-to-mcp/pkg/mcp"
 	"api-to-mcp/pkg/openapi"
@@ -14,9 +23,20 @@ import (
 
 // MCPToolGenerator generates MCP tools from OpenAPI specifications
 type MCPToolGenerator struct {
-	spec   *openapi.ParsedSpec
-	config *config.Config
-	logger *logrus.Logger
+	spec     *openapi.ParsedSpec
+	config   *config.Config
+	logger   *logrus.Logger
+	outbound *netguard.Policy
+	redactor *redact.Redactor
+	failures []GenerationFailure
+}
+
+// GenerationFailure records an endpoint whose tool could not be generated
+type GenerationFailure struct {
+	Method string
+	Path   string
+	Tool   string
+	Err    error
 }
 
 // NewMCPToolGenerator creates a new MCP tool generator
@@ -37,8 +57,21 @@ func (g *MCPToolGenerator) GenerateTools() ([]mcp.Tool, error) {
 		return nil, fmt.Errorf("input validation failed: %w", err)
 	}
 
+	outbound, err := NewOutboundPolicy(g.config)
+	if err != nil {
+		return nil, err
+	}
+	g.outbound = outbound
+
+	redactor, err := NewResponseRedactor(g.config)
+	if err != nil {
+		return nil, err
+	}
+	g.redactor = redactor
+
 	tools := make([]mcp.Tool, 0)
 	errors := make([]error, 0)
+	g.failures = nil
 
 	for _, endpoint := range g.spec.Endpoints {
 		// Apply filters
@@ -55,6 +88,7 @@ func (g *MCPToolGenerator) GenerateTools() ([]mcp.Tool, error) {
 		if err != nil {
 			errorMsg := fmt.Errorf("failed to generate tool for endpoint %s %s: %w", endpoint.Method, endpoint.Path, err)
 			errors = append(errors, errorMsg)
+			g.recordFailure(endpoint, g.generateToolName(endpoint), errorMsg)
 			g.logger.WithError(err).WithFields(logrus.Fields{
 				"path":   endpoint.Path,
 				"method": endpoint.Method,
@@ -66,6 +100,7 @@ func (g *MCPToolGenerator) GenerateTools() ([]mcp.Tool, error) {
 		if err := g.validateTool(tool); err != nil {
 			errorMsg := fmt.Errorf("generated tool validation failed for %s %s: %w", endpoint.Method, endpoint.Path, err)
 			errors = append(errors, errorMsg)
+			g.recordFailure(endpoint, tool.Name, errorMsg)
 			g.logger.WithError(err).WithFields(logrus.Fields{
 				"path":   endpoint.Path,
 				"method": endpoint.Method,
@@ -103,6 +138,63 @@ func (g *MCPToolGenerator) GenerateTools() ([]mcp.Tool, error) {
 	return tools, nil
 }
 
+// NewOutboundPolicy builds the outbound request policy from the safety configuration
+func NewOutboundPolicy(cfg *config.Config) (*netguard.Policy, error) {
+	outbound := cfg.Safety.Outbound
+	policy, err := netguard.NewPolicy(outbound.AllowedHosts, outbound.AllowedCIDRs, outbound.AllowPrivate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid safety.outbound configuration: %w", err)
+	}
+	return policy, nil
+}
+
+// NewUpstreamClient creates an HTTP client for the configured upstream API with its auth,
+// correlation header, warning thresholds and outbound policy applied
+func NewUpstreamClient(cfg *config.Config, logger *logrus.Logger, outbound *netguard.Policy) *utils.HTTPClient {
+	httpClient := utils.NewHTTPClient(cfg.OpenAPI.BaseURL, logger)
+	if cfg.Auth.Type != "" {
+		httpClient.SetAuth(cfg.Auth.Type, cfg.Auth.Token)
+	}
+	httpClient.SetRequestIDHeader(cfg.Observability.CorrelationHeader)
+	httpClient.SetWarningThresholds(cfg.Observability.SlowCallThreshold, cfg.Observability.LargeResponseBytes)
+	httpClient.SetOutboundPolicy(outbound)
+	return httpClient
+}
+
+// NewResponseRedactor builds the redactor applied to upstream responses, or nil when disabled
+func NewResponseRedactor(cfg *config.Config) (*redact.Redactor, error) {
+	rules := cfg.Safety.ResponseRedaction
+	if !rules.Enabled {
+		return nil, nil
+	}
+
+	redactor, err := redact.New(redact.Rules{
+		Fields:   rules.Fields,
+		Paths:    rules.Paths,
+		Patterns: rules.Patterns,
+		Mask:     rules.Mask,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid safety.response_redaction rules: %w", err)
+	}
+	return redactor, nil
+}
+
+// Failures returns the endpoints that failed during the last GenerateTools call
+func (g *MCPToolGenerator) Failures() []GenerationFailure {
+	return g.failures
+}
+
+// recordFailure remembers an endpoint whose tool could not be generated
+func (g *MCPToolGenerator) recordFailure(endpoint openapi.Endpoint, tool string, err error) {
+	g.failures = append(g.failures, GenerationFailure{
+		Method: endpoint.Method,
+		Path:   endpoint.Path,
+		Tool:   tool,
+		Err:    err,
+	})
+}
+
 // generateToolForEndpoint generates a single MCP tool for an endpoint
 func (g *MCPToolGenerator) generateToolForEndpoint(endpoint openapi.Endpoint) (*mcp.Tool, error) {
 	// Generate tool name
@@ -117,8 +209,19 @@ func (g *MCPToolGenerator) generateToolForEndpoint(endpoint openapi.Endpoint) (*
 		return nil, fmt.Errorf("failed to generate input schema: %w", err)
 	}
 
+	// Gated tools accept the confirmation token issued by their first call
+	if safety.RequiresConfirmation(g.config.Safety.Confirmation, endpoint.Method, endpoint.Path) {
+		if inputSchema.Properties == nil {
+			inputSchema.Properties = make(map[string]mcp.Property)
+		}
+		inputSchema.Properties[safety.ConfirmArgument] = mcp.Property{
+			Type:        "string",
+			Description: "Confirmation token returned by a previous call with the same arguments; omit on the first call",
+		}
+	}
+
 	// Create HTTP client for this tool
-	httpClient := utils.NewHTTPClient(g.config.OpenAPI.BaseURL, g.logger)
+	httpClient := NewUpstreamClient(g.config, g.logger, g.outbound)
 
 	// Create tool handler
 	handler := g.createToolHandler(endpoint, httpClient)
@@ -128,6 +231,12 @@ func (g *MCPToolGenerator) generateToolForEndpoint(endpoint openapi.Endpoint) (*
 		Description: description,
 		InputSchema: inputSchema,
 		Handler:     handler,
+		Operation: &mcp.Operation{
+			Method:      endpoint.Method,
+			Path:        endpoint.Path,
+			BaseURL:     g.config.OpenAPI.BaseURL,
+			OperationID: endpoint.OperationID,
+		},
 	}
 
 	g.logger.WithFields(logrus.Fields{
@@ -139,6 +248,16 @@ func (g *MCPToolGenerator) generateToolForEndpoint(endpoint openapi.Endpoint) (*
 	return tool, nil
 }
 
+// ToolName returns the name of the tool generated for an endpoint
+func (g *MCPToolGenerator) ToolName(endpoint openapi.Endpoint) string {
+	return g.generateToolName(endpoint)
+}
+
+// IsEndpointIncluded reports whether an endpoint passes the configured filters
+func (g *MCPToolGenerator) IsEndpointIncluded(endpoint openapi.Endpoint) bool {
+	return g.shouldIncludeEndpoint(endpoint)
+}
+
 // generateToolName generates a tool name from an endpoint
 func (g *MCPToolGenerator) generateToolName(endpoint openapi.Endpoint) string {
 	// Use operation ID if available
@@ -190,15 +309,24 @@ func (g *MCPToolGenerator) generateInputSchema(endpoint openapi.Endpoint) (*mcp.
 	}
 
 	// Add query parameters
+	isOData := g.isODataEndpoint(endpoint)
 	for _, param := range endpoint.Parameters {
 		if param.In == "query" {
 			property := g.convertParameterToProperty(param)
+			if isOData {
+				property = odata.Describe(param.Name, property)
+			}
 			schema.Properties[param.Name] = property
 			if param.Required {
 				schema.Required = append(schema.Required, param.Name)
 			}
 		}
 	}
+	if g.isJSONAPICollection(endpoint) {
+		for name, property := range jsonapi.PageParameters() {
+			schema.Properties[name] = property
+		}
+	}
 
 	// Add request body parameters
 	if endpoint.RequestBody != nil {
@@ -293,38 +421,201 @@ func (g *MCPToolGenerator) mapOpenAPITypeToMCPType(openAPIType string) string {
 }
 
 // createToolHandler creates a handler function for a tool
-func (g *MCPToolGenerator) createToolHandler(endpoint openapi.Endpoint, httpClient *utils.HTTPClient) func(map[string]interface{}) (interface{}, error) {
-	return func(params map[string]interface{}) (interface{}, error) {
+func (g *MCPToolGenerator) createToolHandler(endpoint openapi.Endpoint, httpClient *utils.HTTPClient) mcp.ToolHandler {
+	isOData := g.isODataEndpoint(endpoint)
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		// Reject malformed filters before they reach the service
+		if filter, ok := params[odata.Filter].(string); ok && isOData {
+			if err := odata.ValidateFilter(filter); err != nil {
+				return nil, err
+			}
+		}
+
 		// Build URL with path parameters
-		url := g.buildURL(endpoint.Path, params)
+		url, err := g.buildURL(endpoint, params)
+		if err != nil {
+			return nil, err
+		}
 
 		// Make HTTP request
-		response, err := httpClient.MakeRequest(endpoint.Method, url, params)
+		response, err := httpClient.MakeRequest(ctx, endpoint.Method, url, params)
 		if err != nil {
 			return nil, fmt.Errorf("HTTP request failed: %w", err)
 		}
 
+		if isOData {
+			response, err = g.followNextLinks(ctx, httpClient, url, response)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if jsonAPI := g.config.OpenAPI.JSONAPI; jsonAPI.Enabled {
+			response = jsonapi.Simplify(response, jsonAPI.FlattenIncluded)
+		}
+
+		if g.redactor != nil {
+			return g.redactor.Value(response), nil
+		}
 		return response, nil
 	}
 }
 
-// buildURL builds the URL for an endpoint with path parameters
-func (g *MCPToolGenerator) buildURL(path string, params map[string]interface{}) string {
-	url := path
+// isJSONAPICollection reports whether page parameters apply to an endpoint: a GET with a
+// JSON:API response whose primary data is an array, declaring no page parameters of its own,
+// with openapi.jsonapi enabled. Without a data schema, paths not ending in a parameter are
+// taken as collections
+func (g *MCPToolGenerator) isJSONAPICollection(endpoint openapi.Endpoint) bool {
+	if !g.config.OpenAPI.JSONAPI.Enabled || endpoint.Method != "GET" {
+		return false
+	}
+	for _, param := range endpoint.Parameters {
+		if param.In == "query" && strings.HasPrefix(param.Name, "page[") {
+			return false
+		}
+	}
 
-	// Replace path parameters
-	for key, value := range params {
-		placeholder := fmt.Sprintf("{%s}", key)
-		if strings.Contains(url, placeholder) {
-			url = strings.ReplaceAll(url, placeholder, fmt.Sprintf("%v", value))
+	declared := false
+	for status, response := range endpoint.Responses {
+		if !strings.HasPrefix(status, "2") {
+			continue
+		}
+		for contentType, media := range response.Content {
+			if !jsonapi.IsMediaType(contentType) {
+				continue
+			}
+			declared = true
+			if data, ok := media.Schema.Properties["data"]; ok && data.Type != "" {
+				return data.Type == "array"
+			}
 		}
 	}
+	return declared && !strings.HasSuffix(endpoint.Path, "}")
+}
+
+// defaultODataMaxPages bounds OData paging when max_pages is unset, as for tenants
+const defaultODataMaxPages = 5
 
-	return url
+// isODataEndpoint reports whether OData conventions apply to an endpoint: a GET declaring at
+// least one system query option, with openapi.odata enabled
+func (g *MCPToolGenerator) isODataEndpoint(endpoint openapi.Endpoint) bool {
+	if !g.config.OpenAPI.OData.Enabled || endpoint.Method != "GET" {
+		return false
+	}
+	for _, param := range endpoint.Parameters {
+		if param.In == "query" && odata.IsSystemOption(param.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// followNextLinks fetches further pages of an OData collection and merges their items, up to
+// openapi.odata.max_pages; the last page's next link is kept when pages remain
+func (g *MCPToolGenerator) followNextLinks(ctx context.Context, httpClient *utils.HTTPClient, path string, response interface{}) (interface{}, error) {
+	maxPages := g.config.OpenAPI.OData.MaxPages
+	if maxPages <= 0 {
+		maxPages = defaultODataMaxPages
+	}
+	for page := 1; page < maxPages; page++ {
+		link, ok := odata.NextLink(response)
+		if !ok {
+			break
+		}
+		next, err := odata.ResolveNextLink(g.config.OpenAPI.BaseURL, path, link)
+		if err != nil {
+			g.logger.WithError(err).Warn("Not following OData next link")
+			break
+		}
+
+		g.logger.WithFields(logrus.Fields{
+			"path": path,
+			"page": page + 1,
+		}).Debug("Following OData next link")
+		pageResponse, err := httpClient.MakeRequest(ctx, "GET", next, nil)
+		if err != nil {
+			return nil, fmt.Errorf("HTTP request for page %d failed: %w", page+1, err)
+		}
+		merged, err := odata.MergePage(response, pageResponse)
+		if err != nil {
+			g.logger.WithError(err).Warn("Not merging OData page")
+			break
+		}
+		response = merged
+	}
+	return response, nil
+}
+
+// pathParamPattern matches {name} placeholders in endpoint paths
+var pathParamPattern = regexp.MustCompile(`\{([^{}]+)\}`)
+
+// buildURL builds the URL for an endpoint, URL-escaping path parameter values
+func (g *MCPToolGenerator) buildURL(endpoint openapi.Endpoint, params map[string]interface{}) (string, error) {
+	allowReserved := make(map[string]bool)
+	for _, param := range endpoint.Parameters {
+		if param.In == "path" && param.AllowReserved {
+			allowReserved[param.Name] = true
+		}
+	}
+
+	var buildErr error
+	url := pathParamPattern.ReplaceAllStringFunc(endpoint.Path, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		value, exists := params[name]
+		if !exists {
+			buildErr = fmt.Errorf("missing path parameter %s", name)
+			return placeholder
+		}
+
+		escaped, err := escapePathValue(fmt.Sprintf("%v", value), allowReserved[name])
+		if err != nil {
+			buildErr = fmt.Errorf("invalid path parameter %s: %w", name, err)
+			return placeholder
+		}
+		return escaped
+	})
+	if buildErr != nil {
+		return "", buildErr
+	}
+
+	return url, nil
+}
+
+// escapePathValue escapes a path parameter value so it cannot change the URL structure; values
+// containing /, ? or # are rejected unless the parameter allows reserved characters, in which
+// case / is kept as a separator and every segment is escaped
+func escapePathValue(value string, allowReserved bool) (string, error) {
+	if value == "" {
+		return "", fmt.Errorf("value must not be empty")
+	}
+
+	if !allowReserved {
+		if strings.ContainsAny(value, "/?#") {
+			return "", fmt.Errorf("value %q must not contain '/', '?' or '#'", value)
+		}
+		if value == "." || value == ".." {
+			return "", fmt.Errorf("value %q is not a valid path segment", value)
+		}
+		return neturl.PathEscape(value), nil
+	}
+
+	segments := strings.Split(value, "/")
+	for i, segment := range segments {
+		if segment == "." || segment == ".." {
+			return "", fmt.Errorf("value %q must not contain dot segments", value)
+		}
+		segments[i] = neturl.PathEscape(segment)
+	}
+	return strings.Join(segments, "/"), nil
 }
 
 // shouldIncludeEndpoint checks if an endpoint should be included based on filters
 func (g *MCPToolGenerator) shouldIncludeEndpoint(endpoint openapi.Endpoint) bool {
+	// Read-only mode never exposes mutating endpoints
+	if g.config.Safety.ReadOnly && !config.IsReadOnlyMethod(endpoint.Method) {
+		return false
+	}
+
 	// Check path filters
 	if len(g.config.Filters.IncludePaths) > 0 {
 		include := false
@@ -494,6 +785,7 @@ func (g *MCPToolGenerator) convertSchemaToProperty(schema openapi.Schema) (mcp.P
 		for name := range schema.Properties {
 			propertyNames = append(propertyNames, name)
 		}
+		sort.Strings(propertyNames)
 		if len(propertyNames) > 0 {
 			property.Description = fmt.Sprintf("%s - properties: %s", property.Description, strings.Join(propertyNames, ", "))
 		}
@@ -592,6 +884,7 @@ func (g *MCPToolGenerator) convertSchemaToPropertyWithReferences(schema openapi.
 		for name := range schema.Properties {
 			propertyNames = append(propertyNames, name)
 		}
+		sort.Strings(propertyNames)
 		if len(propertyNames) > 0 {
 			property.Description = fmt.Sprintf("%s - properties: %s", property.Description, strings.Join(propertyNames, ", "))
 		}
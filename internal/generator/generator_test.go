@@ -1,7 +1,13 @@
 package generator
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"api-to-mcp/internal/config"
@@ -629,6 +635,21 @@ func TestConvertSchemaToProperty(t *testing.T) {
 
 	assert.Equal(t, "array", arrayProperty.Type)
 	assert.Contains(t, arrayProperty.Description, "array of string")
+
+	// Nested object property names are listed in a stable order
+	objectSchema := openapi.Schema{
+		Type:        "object",
+		Description: "Test object",
+		Properties: map[string]openapi.Schema{
+			"zeta":  {Type: "string"},
+			"alpha": {Type: "string"},
+			"mid":   {Type: "integer"},
+		},
+	}
+
+	objectProperty, err := generator.convertSchemaToProperty(objectSchema)
+	require.NoError(t, err)
+	assert.Equal(t, "Test object (object with 3 properties) - properties: alpha, mid, zeta", objectProperty.Description)
 }
 
 func TestGenerateTools_WithNestedObjectSchema(t *testing.T) {
@@ -892,7 +913,7 @@ func TestValidateTool(t *testing.T) {
 			},
 			Required: []string{"test"},
 		},
-		Handler: func(params map[string]interface{}) (interface{}, error) {
+		Handler: func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
 			return "test", nil
 		},
 	}
@@ -1262,16 +1283,59 @@ func TestBuildURL(t *testing.T) {
 			params:   map[string]interface{}{"id": "test", "other": "ignored"},
 			expected: "/users/test",
 		},
+		{
+			path:     "/files/{name}",
+			params:   map[string]interface{}{"name": "a b%2F..;x"},
+			expected: "/files/a%20b%252F..%3Bx",
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.path, func(t *testing.T) {
-			result := generator.buildURL(tc.path, tc.params)
+			result, err := generator.buildURL(openapi.Endpoint{Path: tc.path}, tc.params)
+			require.NoError(t, err)
 			assert.Equal(t, tc.expected, result)
 		})
 	}
 }
 
+func TestBuildURL_RejectsTraversal(t *testing.T) {
+	logger := logrus.New()
+	generator := NewMCPToolGenerator(&openapi.ParsedSpec{}, &config.Config{}, logger)
+	endpoint := openapi.Endpoint{
+		Path:       "/users/{id}",
+		Parameters: []openapi.Parameter{{Name: "id", In: "path", Required: true}},
+	}
+
+	for _, value := range []string{"../admin", "..", ".", "a/b", "a/b?x=1", "1?admin=true", "1#frag", ""} {
+		t.Run(value, func(t *testing.T) {
+			_, err := generator.buildURL(endpoint, map[string]interface{}{"id": value})
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := generator.buildURL(endpoint, map[string]interface{}{})
+	assert.Error(t, err, "missing path parameters are rejected")
+}
+
+func TestBuildURL_AllowReserved(t *testing.T) {
+	logger := logrus.New()
+	generator := NewMCPToolGenerator(&openapi.ParsedSpec{}, &config.Config{}, logger)
+	endpoint := openapi.Endpoint{
+		Path:       "/repos/{path}",
+		Parameters: []openapi.Parameter{{Name: "path", In: "path", Required: true, AllowReserved: true}},
+	}
+
+	result, err := generator.buildURL(endpoint, map[string]interface{}{"path": "docs/read me?.md"})
+	require.NoError(t, err)
+	assert.Equal(t, "/repos/docs/read%20me%3F.md", result)
+
+	for _, value := range []string{"../etc/passwd", "docs/../../admin", "docs/./x"} {
+		_, err := generator.buildURL(endpoint, map[string]interface{}{"path": value})
+		assert.Error(t, err, value)
+	}
+}
+
 func TestShouldIncludeEndpoint(t *testing.T) {
 	logger := logrus.New()
 	config := &config.Config{
@@ -1318,6 +1382,14 @@ func TestShouldIncludeEndpoint(t *testing.T) {
 
 	config.Filters.ExcludeMethods = []string{"POST"}
 	assert.True(t, generator.shouldIncludeEndpoint(endpoint))
+
+	// Test read-only mode
+	config.Filters.ExcludeMethods = []string{}
+	config.Safety.ReadOnly = true
+	assert.True(t, generator.shouldIncludeEndpoint(endpoint))
+	assert.True(t, generator.shouldIncludeEndpoint(openapi.Endpoint{Path: "/users", Method: "HEAD"}))
+	assert.False(t, generator.shouldIncludeEndpoint(openapi.Endpoint{Path: "/users", Method: "POST"}))
+	assert.False(t, generator.shouldIncludeEndpoint(openapi.Endpoint{Path: "/users/{id}", Method: "DELETE"}))
 }
 
 func TestGenerateTools_IntegrationWithRealSpec(t *testing.T) {
@@ -1368,3 +1440,179 @@ func TestGenerateTools_IntegrationWithRealSpec(t *testing.T) {
 	}
 	assert.Greater(t, foundExpected, 0, "Should have found some expected petstore tools")
 }
+
+func odataSpec() *openapi.ParsedSpec {
+	return &openapi.ParsedSpec{
+		Info: openapi.Info{Title: "People", Version: "1.0.0"},
+		Endpoints: []openapi.Endpoint{
+			{
+				Path:        "/People",
+				Method:      "GET",
+				OperationID: "listPeople",
+				Summary:     "List people",
+				Parameters: []openapi.Parameter{
+					{Name: "$filter", In: "query", Schema: openapi.Schema{Type: "string"}},
+					{Name: "$top", In: "query", Schema: openapi.Schema{Type: "number"}},
+					{Name: "$select", In: "query", Description: "Fields", Schema: openapi.Schema{Type: "string"}},
+				},
+			},
+		},
+	}
+}
+
+func TestGenerateTools_ODataOptions(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.OpenAPI.BaseURL = "https://odata.example.com/v4"
+	cfg.OpenAPI.OData.Enabled = true
+
+	tools, err := NewMCPToolGenerator(odataSpec(), cfg, logrus.New()).GenerateTools()
+	require.NoError(t, err)
+	properties := tools[0].InputSchema.Properties
+	assert.Contains(t, properties["$filter"].Description, "OData filter expression")
+	assert.Equal(t, "integer", properties["$top"].Type)
+	require.NotNil(t, properties["$top"].Minimum)
+	assert.Equal(t, 0.0, *properties["$top"].Minimum)
+	// Descriptions from the specification are kept
+	assert.Equal(t, "Fields", properties["$select"].Description)
+
+	_, err = tools[0].Handler(context.Background(), map[string]interface{}{"$filter": "Name eq 'unterminated"})
+	assert.ErrorContains(t, err, "invalid $filter at position 9: unterminated string")
+
+	// Without openapi.odata.enabled the options are left as the specification declares them
+	cfg.OpenAPI.OData.Enabled = false
+	tools, err = NewMCPToolGenerator(odataSpec(), cfg, logrus.New()).GenerateTools()
+	require.NoError(t, err)
+	assert.Empty(t, tools[0].InputSchema.Properties["$filter"].Description)
+	assert.Equal(t, "number", tools[0].InputSchema.Properties["$top"].Type)
+}
+
+func TestGenerateTools_ODataNextLink(t *testing.T) {
+	var server *httptest.Server
+	pages := map[string]string{
+		"":  `{"value":[{"Name":"a"}],"@odata.nextLink":"%s/v4/People?$skiptoken=1"}`,
+		"1": `{"value":[{"Name":"b"}],"@odata.nextLink":"People?$skiptoken=2"}`,
+		"2": `{"value":[{"Name":"c"}],"@odata.nextLink":"https://elsewhere.example.com/v4/People?$skiptoken=3"}`,
+	}
+	var requests atomic.Int32
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		body := pages[r.URL.Query().Get("$skiptoken")]
+		if strings.Contains(body, "%s") {
+			body = fmt.Sprintf(body, server.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.OpenAPI.BaseURL = server.URL + "/v4"
+	cfg.OpenAPI.OData.Enabled = true
+	cfg.Safety.Outbound.AllowPrivate = true
+
+	tools, err := NewMCPToolGenerator(odataSpec(), cfg, logrus.New()).GenerateTools()
+	require.NoError(t, err)
+	result, err := tools[0].Handler(context.Background(), map[string]interface{}{"$filter": "startswith(Name,'a') or Name in ('b','c')"})
+	require.NoError(t, err)
+
+	// Pages are merged until a next link leaves the base URL, which is returned unfollowed
+	body := result.(map[string]interface{})
+	assert.Len(t, body["value"], 3)
+	assert.Equal(t, "https://elsewhere.example.com/v4/People?$skiptoken=3", body["@odata.nextLink"])
+	assert.Equal(t, int32(3), requests.Load())
+
+	// max_pages bounds how many pages are fetched
+	requests.Store(0)
+	cfg.OpenAPI.OData.MaxPages = 2
+	tools, err = NewMCPToolGenerator(odataSpec(), cfg, logrus.New()).GenerateTools()
+	require.NoError(t, err)
+	result, err = tools[0].Handler(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	body = result.(map[string]interface{})
+	assert.Len(t, body["value"], 2)
+	assert.Equal(t, "People?$skiptoken=2", body["@odata.nextLink"])
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func jsonAPISpec() *openapi.ParsedSpec {
+	document := func(data openapi.Schema) map[string]openapi.Response {
+		return map[string]openapi.Response{"200": {Content: map[string]openapi.MediaType{
+			"application/vnd.api+json": {Schema: openapi.Schema{Type: "object", Properties: map[string]openapi.Schema{"data": data}}},
+		}}}
+	}
+	return &openapi.ParsedSpec{
+		Info: openapi.Info{Title: "Articles", Version: "1.0.0"},
+		Endpoints: []openapi.Endpoint{
+			{Path: "/articles", Method: "GET", OperationID: "listArticles", Responses: document(openapi.Schema{Type: "array"})},
+			{
+				Path: "/articles/{id}", Method: "GET", OperationID: "getArticle",
+				Parameters: []openapi.Parameter{{Name: "id", In: "path", Required: true, Schema: openapi.Schema{Type: "string"}}},
+				Responses:  document(openapi.Schema{Type: "object"}),
+			},
+			{
+				Path: "/people", Method: "GET", OperationID: "listPeople",
+				Parameters: []openapi.Parameter{{Name: "page[cursor]", In: "query", Schema: openapi.Schema{Type: "string"}}},
+				Responses:  document(openapi.Schema{Type: "array"}),
+			},
+		},
+	}
+}
+
+func TestGenerateTools_JSONAPIPageParameters(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.OpenAPI.BaseURL = "https://api.example.com"
+	cfg.OpenAPI.JSONAPI.Enabled = true
+
+	tools, err := NewMCPToolGenerator(jsonAPISpec(), cfg, logrus.New()).GenerateTools()
+	require.NoError(t, err)
+	require.Len(t, tools, 3)
+	assert.Equal(t, "integer", tools[0].InputSchema.Properties["page[number]"].Type)
+	assert.Contains(t, tools[0].InputSchema.Properties, "page[size]")
+	// Single resources and collections with their own pagination are left alone
+	assert.NotContains(t, tools[1].InputSchema.Properties, "page[number]")
+	assert.NotContains(t, tools[2].InputSchema.Properties, "page[number]")
+
+	cfg.OpenAPI.JSONAPI.Enabled = false
+	tools, err = NewMCPToolGenerator(jsonAPISpec(), cfg, logrus.New()).GenerateTools()
+	require.NoError(t, err)
+	assert.NotContains(t, tools[0].InputSchema.Properties, "page[number]")
+}
+
+func TestGenerateTools_JSONAPIResults(t *testing.T) {
+	var query atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query.Store(r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		_, _ = w.Write([]byte(`{
+  "links": {"next": "/articles?page[number]=3"},
+  "data": [{"type": "articles", "id": "1", "attributes": {"title": "Hello"},
+    "relationships": {"author": {"data": {"type": "people", "id": "9"}, "links": {"related": "/articles/1/author"}}}}],
+  "included": [{"type": "people", "id": "9", "attributes": {"name": "Dan"}}]
+}`))
+	}))
+	defer server.Close()
+
+	cfg := &config.Config{}
+	cfg.OpenAPI.BaseURL = server.URL
+	cfg.OpenAPI.JSONAPI.Enabled = true
+	cfg.OpenAPI.JSONAPI.FlattenIncluded = true
+	cfg.Safety.Outbound.AllowPrivate = true
+
+	tools, err := NewMCPToolGenerator(jsonAPISpec(), cfg, logrus.New()).GenerateTools()
+	require.NoError(t, err)
+	result, err := tools[0].Handler(context.Background(), map[string]interface{}{"page[number]": float64(2)})
+	require.NoError(t, err)
+	assert.Equal(t, "page%5Bnumber%5D=2", query.Load())
+
+	assert.Equal(t, map[string]interface{}{
+		"links":     map[string]interface{}{"next": "/articles?page[number]=3"},
+		"next_page": 3,
+		"data": []interface{}{map[string]interface{}{
+			"type":   "articles",
+			"id":     "1",
+			"title":  "Hello",
+			"author": map[string]interface{}{"type": "people", "id": "9", "name": "Dan"},
+			"links":  map[string]interface{}{"author": "/articles/1/author"},
+		}},
+	}, result)
+}
@@ -0,0 +1,486 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/generator"
+	"api-to-mcp/internal/redact"
+	"api-to-mcp/internal/safety"
+	"api-to-mcp/internal/utils"
+	"api-to-mcp/pkg/mcp"
+	"api-to-mcp/pkg/openapi"
+
+	"github.com/sirupsen/logrus"
+)
+
+// QueryToolName names the tool that runs arbitrary GraphQL documents
+const QueryToolName = "graphql_query"
+
+// Fallbacks for tenants, which do not receive configuration defaults
+const (
+	defaultEndpoint = "/graphql"
+	defaultMaxDepth = 2
+)
+
+// Queries are classified as GET and mutations as POST, so read-only mode, method filters,
+// confirmation, write budgets and role method rules treat them like REST reads and writes
+const (
+	queryMethod    = "GET"
+	mutationMethod = "POST"
+)
+
+// camelBoundary finds the lower-to-upper transitions of camelCase names
+var camelBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// responseError is an entry of a GraphQL response's errors list
+type responseError struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// joinErrors combines the messages of GraphQL errors
+func joinErrors(errs []responseError) string {
+	messages := make([]string, len(errs))
+	for i, e := range errs {
+		messages[i] = e.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Generator generates MCP tools from a GraphQL schema
+type Generator struct {
+	schema   *Schema
+	config   *config.Config
+	logger   *logrus.Logger
+	client   *utils.HTTPClient
+	filter   *generator.MCPToolGenerator
+	redactor *redact.Redactor
+	endpoint string
+	maxDepth int
+	names    map[string]bool
+}
+
+// BuildTools loads the configured GraphQL schema, introspecting the endpoint when no schema
+// file is configured, and generates its tools
+func BuildTools(ctx context.Context, cfg *config.Config, logger *logrus.Logger) ([]mcp.Tool, error) {
+	outbound, err := generator.NewOutboundPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := generator.NewUpstreamClient(cfg, logger, outbound)
+
+	var schema *Schema
+	if path := cfg.OpenAPI.GraphQL.SchemaPath; path != "" {
+		schema, err = LoadSchema(path)
+	} else {
+		logger.WithField("endpoint", endpointPath(cfg)).Info("Introspecting GraphQL endpoint")
+		schema, err = Introspect(ctx, client, endpointPath(cfg))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewGenerator(schema, cfg, logger, client).GenerateTools()
+}
+
+// NewGenerator creates a generator whose tools send requests with the given client
+func NewGenerator(schema *Schema, cfg *config.Config, logger *logrus.Logger, client *utils.HTTPClient) *Generator {
+	maxDepth := cfg.OpenAPI.GraphQL.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = defaultMaxDepth
+	}
+	return &Generator{
+		schema:   schema,
+		config:   cfg,
+		logger:   logger,
+		client:   client,
+		filter:   generator.NewMCPToolGenerator(nil, cfg, logger),
+		endpoint: endpointPath(cfg),
+		maxDepth: maxDepth,
+	}
+}
+
+// endpointPath returns the configured GraphQL endpoint
+func endpointPath(cfg *config.Config) string {
+	if cfg.OpenAPI.GraphQL.Endpoint == "" {
+		return defaultEndpoint
+	}
+	return cfg.OpenAPI.GraphQL.Endpoint
+}
+
+// GenerateTools generates a tool per query and mutation field, plus the graphql_query tool
+func (g *Generator) GenerateTools() ([]mcp.Tool, error) {
+	g.logger.Info("Generating MCP tools from GraphQL schema")
+
+	query := g.schema.Query()
+	if query == nil {
+		return nil, fmt.Errorf("GraphQL schema has no query type")
+	}
+
+	redactor, err := generator.NewResponseRedactor(g.config)
+	if err != nil {
+		return nil, err
+	}
+	g.redactor = redactor
+	g.names = make(map[string]bool)
+
+	tools := make([]mcp.Tool, 0)
+	roots := []struct {
+		operation string
+		method    string
+		root      *Type
+	}{
+		{"query", queryMethod, query},
+		{"mutation", mutationMethod, g.schema.Mutation()},
+	}
+	for _, r := range roots {
+		if r.root == nil {
+			continue
+		}
+		for _, field := range r.root.Fields {
+			if strings.HasPrefix(field.Name, "__") {
+				continue
+			}
+			endpoint := openapi.Endpoint{Method: r.method, Path: "/" + r.operation + "/" + field.Name}
+			if !g.filter.IsEndpointIncluded(endpoint) {
+				g.logger.WithFields(logrus.Fields{
+					"operation": r.operation,
+					"field":     field.Name,
+				}).Debug("Skipping filtered GraphQL field")
+				continue
+			}
+			tools = append(tools, g.fieldTool(r.operation, endpoint, field))
+		}
+	}
+
+	if !g.config.OpenAPI.GraphQL.DisableQueryTool &&
+		g.filter.IsEndpointIncluded(openapi.Endpoint{Method: mutationMethod, Path: g.endpoint}) {
+		tools = append(tools, g.queryTool())
+	}
+
+	g.logger.WithField("tool_count", len(tools)).Info("Generated MCP tools")
+	if len(tools) == 0 {
+		return nil, fmt.Errorf("no tools could be generated: all GraphQL fields were filtered out")
+	}
+	return tools, nil
+}
+
+// fieldTool generates the tool for a root query or mutation field
+func (g *Generator) fieldTool(operation string, endpoint openapi.Endpoint, field Field) mcp.Tool {
+	name := g.uniqueName(toolName(field.Name), operation)
+
+	description := strings.TrimSpace(field.Description)
+	if description == "" {
+		description = fmt.Sprintf("GraphQL %s %s", operation, field.Name)
+	}
+
+	inputSchema := &mcp.InputSchema{
+		Type:       "object",
+		Properties: make(map[string]mcp.Property, len(field.Args)),
+		Required:   make([]string, 0),
+	}
+	arguments := make([]string, 0, len(field.Args))
+	for _, arg := range field.Args {
+		inputSchema.Properties[arg.Name] = g.property(arg)
+		if arg.Type.NonNull() && arg.DefaultValue == nil {
+			inputSchema.Required = append(inputSchema.Required, arg.Name)
+		}
+		arguments = append(arguments, arg.Name)
+	}
+
+	// Gated tools accept the confirmation token issued by their first call
+	if safety.RequiresConfirmation(g.config.Safety.Confirmation, endpoint.Method, endpoint.Path) {
+		inputSchema.Properties[safety.ConfirmArgument] = mcp.Property{
+			Type:        "string",
+			Description: "Confirmation token returned by a previous call with the same arguments; omit on the first call",
+		}
+	}
+
+	document := g.document(operation, field)
+	g.logger.WithFields(logrus.Fields{
+		"tool_name": name,
+		"operation": operation,
+		"field":     field.Name,
+	}).Debug("Generated tool for GraphQL field")
+
+	return mcp.Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: inputSchema,
+		Handler:     g.fieldHandler(field.Name, document, arguments),
+		Operation: &mcp.Operation{
+			Method:      endpoint.Method,
+			Path:        endpoint.Path,
+			BaseURL:     g.config.OpenAPI.BaseURL,
+			OperationID: field.Name,
+		},
+	}
+}
+
+// queryTool generates the graphql_query escape hatch. It can run mutations, so it is
+// classified as mutating
+func (g *Generator) queryTool() mcp.Tool {
+	return mcp.Tool{
+		Name:        g.uniqueName(QueryToolName, "query"),
+		Description: "Execute an arbitrary GraphQL query or mutation against the API and return its data",
+		InputSchema: &mcp.InputSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"query":          {Type: "string", Description: "GraphQL document to execute"},
+				"variables":      {Type: "object", Description: "Values of the variables declared by the document"},
+				"operation_name": {Type: "string", Description: "Operation to run when the document defines several"},
+			},
+			Required: []string{"query"},
+		},
+		Handler: g.queryHandler(),
+		Operation: &mcp.Operation{
+			Method:  mutationMethod,
+			Path:    g.endpoint,
+			BaseURL: g.config.OpenAPI.BaseURL,
+		},
+	}
+}
+
+// uniqueName returns name, qualified by the operation when another tool already uses it
+func (g *Generator) uniqueName(name, operation string) string {
+	if g.names[name] {
+		name = name + "_" + operation
+	}
+	for candidate, i := name, 2; ; i++ {
+		if !g.names[candidate] {
+			g.names[candidate] = true
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+}
+
+// toolName converts a camelCase field name to a snake_case tool name
+func toolName(field string) string {
+	return strings.ToLower(camelBoundary.ReplaceAllString(field, "${1}_${2}"))
+}
+
+// document builds the operation for a field, declaring every argument as a variable
+func (g *Generator) document(operation string, field Field) string {
+	var b strings.Builder
+	b.WriteString(operation)
+	b.WriteString(" ")
+	b.WriteString(field.Name)
+	if len(field.Args) > 0 {
+		declarations := make([]string, len(field.Args))
+		uses := make([]string, len(field.Args))
+		for i, arg := range field.Args {
+			declarations[i] = fmt.Sprintf("$%s: %s", arg.Name, arg.Type.String())
+			uses[i] = fmt.Sprintf("%s: $%s", arg.Name, arg.Name)
+		}
+		fmt.Fprintf(&b, "(%s) { %s(%s)", strings.Join(declarations, ", "), field.Name, strings.Join(uses, ", "))
+	} else {
+		fmt.Fprintf(&b, " { %s", field.Name)
+	}
+	if selection := g.selection(field.Type.Named(), 1); selection != "" {
+		b.WriteString(" ")
+		b.WriteString(selection)
+	}
+	b.WriteString(" }")
+	return b.String()
+}
+
+// selection builds the selection set of a type: every scalar and enum field, and object fields
+// without required arguments down to the configured depth. Scalars need no selection
+func (g *Generator) selection(typeName string, depth int) string {
+	t := g.schema.Type(typeName)
+	if t == nil {
+		return ""
+	}
+
+	switch t.Kind {
+	case KindObject, KindInterface:
+		fields := make([]string, 0, len(t.Fields))
+		for _, field := range t.Fields {
+			if hasRequiredArgs(field) {
+				continue
+			}
+			fieldType := g.schema.Type(field.Type.Named())
+			if fieldType == nil || fieldType.Kind == KindScalar || fieldType.Kind == KindEnum {
+				fields = append(fields, field.Name)
+				continue
+			}
+			if depth >= g.maxDepth {
+				continue
+			}
+			if nested := g.selection(fieldType.Name, depth+1); nested != "" {
+				fields = append(fields, field.Name+" "+nested)
+			}
+		}
+		if t.Kind == KindInterface || len(fields) == 0 {
+			fields = append([]string{"__typename"}, fields...)
+		}
+		return "{ " + strings.Join(fields, " ") + " }"
+	case KindUnion:
+		fields := []string{"__typename"}
+		for _, member := range t.PossibleTypes {
+			if nested := g.selection(member.Named(), depth); nested != "" {
+				fields = append(fields, "... on "+member.Named()+" "+nested)
+			}
+		}
+		return "{ " + strings.Join(fields, " ") + " }"
+	default:
+		return ""
+	}
+}
+
+// hasRequiredArgs reports whether a field cannot be selected without arguments
+func hasRequiredArgs(field Field) bool {
+	for _, arg := range field.Args {
+		if arg.Type.NonNull() && arg.DefaultValue == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// property converts a GraphQL argument into an MCP property
+func (g *Generator) property(arg InputValue) mcp.Property {
+	property := mcp.Property{
+		Type:        g.mcpType(arg.Type.Named()),
+		Description: strings.TrimSpace(arg.Description),
+	}
+	if arg.DefaultValue != nil {
+		var value interface{}
+		if err := json.Unmarshal([]byte(*arg.DefaultValue), &value); err == nil {
+			property.Default = value
+		} else {
+			property.Default = *arg.DefaultValue
+		}
+	}
+
+	t := g.schema.Type(arg.Type.Named())
+	if t != nil && t.Kind == KindEnum && !arg.Type.IsList() {
+		for _, value := range t.EnumValues {
+			property.Enum = append(property.Enum, value.Name)
+		}
+	}
+	if t != nil && t.Kind == KindInputObject && !arg.Type.IsList() {
+		names := make([]string, len(t.InputFields))
+		for i, field := range t.InputFields {
+			names[i] = field.Name
+		}
+		sort.Strings(names)
+		property.Description = fmt.Sprintf("%s (object with %d properties) - properties: %s",
+			property.Description, len(names), strings.Join(names, ", "))
+	}
+	if arg.Type.IsList() {
+		property.Description = fmt.Sprintf("%s (array of %s)", property.Description, property.Type)
+		property.Type = "array"
+	}
+	property.Description = strings.TrimSpace(property.Description)
+	return property
+}
+
+// mcpType maps a named GraphQL input type to an MCP type
+func (g *Generator) mcpType(name string) string {
+	switch name {
+	case "Int":
+		return "integer"
+	case "Float":
+		return "number"
+	case "Boolean":
+		return "boolean"
+	}
+	if t := g.schema.Type(name); t != nil && t.Kind == KindInputObject {
+		return "object"
+	}
+	return "string"
+}
+
+// fieldHandler executes a field's document with the call's arguments as variables
+func (g *Generator) fieldHandler(field, document string, arguments []string) mcp.ToolHandler {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		variables := make(map[string]interface{}, len(arguments))
+		for _, name := range arguments {
+			if value, exists := params[name]; exists {
+				variables[name] = value
+			}
+		}
+
+		data, errs, err := g.execute(ctx, document, variables, "")
+		if err != nil {
+			return nil, err
+		}
+		value, present := data[field]
+		if len(errs) > 0 {
+			if !present || value == nil {
+				return nil, fmt.Errorf("GraphQL errors: %s", joinErrors(errs))
+			}
+			// Partial results keep the errors next to the data that was resolved
+			return g.redact(map[string]interface{}{"data": value, "errors": errs}), nil
+		}
+		return g.redact(value), nil
+	}
+}
+
+// queryHandler executes the document supplied by the caller
+func (g *Generator) queryHandler() mcp.ToolHandler {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		document, _ := params["query"].(string)
+		if strings.TrimSpace(document) == "" {
+			return nil, fmt.Errorf("query is required")
+		}
+		variables, _ := params["variables"].(map[string]interface{})
+		operationName, _ := params["operation_name"].(string)
+
+		data, errs, err := g.execute(ctx, document, variables, operationName)
+		if err != nil {
+			return nil, err
+		}
+		if len(errs) > 0 {
+			if data == nil {
+				return nil, fmt.Errorf("GraphQL errors: %s", joinErrors(errs))
+			}
+			return g.redact(map[string]interface{}{"data": data, "errors": errs}), nil
+		}
+		return g.redact(data), nil
+	}
+}
+
+// execute posts a document to the endpoint and splits the response into data and errors
+func (g *Generator) execute(ctx context.Context, document string, variables map[string]interface{}, operationName string) (map[string]interface{}, []responseError, error) {
+	body := map[string]interface{}{"query": document}
+	if len(variables) > 0 {
+		body["variables"] = variables
+	}
+	if operationName != "" {
+		body["operationName"] = operationName
+	}
+
+	response, err := g.client.MakeRequest(ctx, "POST", g.endpoint, map[string]interface{}{"body": body})
+	if err != nil {
+		return nil, nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode GraphQL response: %w", err)
+	}
+	var result struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []responseError        `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, nil, fmt.Errorf("unexpected GraphQL response: %w", err)
+	}
+	return result.Data, result.Errors, nil
+}
+
+// redact applies response redaction, if configured
+func (g *Generator) redact(value interface{}) interface{} {
+	if g.redactor == nil {
+		return value
+	}
+	return g.redactor.Value(value)
+}
@@ -0,0 +1,223 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// graphQLRequest is the body of a request received by the test server
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables"`
+	OperationName string                 `json:"operationName"`
+}
+
+// newTestServer serves /graphql, recording requests and answering with respond
+func newTestServer(t *testing.T, respond func(graphQLRequest) interface{}) (*httptest.Server, *[]graphQLRequest) {
+	t.Helper()
+	var received []graphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/graphql", r.URL.Path)
+		assert.Equal(t, http.MethodPost, r.Method)
+		var request graphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		received = append(received, request)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(respond(request))
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func testConfig(baseURL string) *config.Config {
+	cfg := &config.Config{}
+	cfg.OpenAPI.BaseURL = baseURL
+	cfg.OpenAPI.GraphQL = config.GraphQLConfig{Enabled: true, SchemaPath: "../../examples/blog.graphql"}
+	cfg.Safety.Outbound.AllowPrivate = true
+	return cfg
+}
+
+func toolsByName(tools []mcp.Tool) map[string]mcp.Tool {
+	byName := make(map[string]mcp.Tool, len(tools))
+	for _, tool := range tools {
+		byName[tool.Name] = tool
+	}
+	return byName
+}
+
+func TestBuildTools_FromSDL(t *testing.T) {
+	tools, err := BuildTools(context.Background(), testConfig("http://localhost"), logrus.New())
+	require.NoError(t, err)
+
+	byName := toolsByName(tools)
+	require.Len(t, byName, 6)
+	for _, name := range []string{"user", "posts", "search", "create_post", "delete_post", QueryToolName} {
+		assert.Contains(t, byName, name)
+	}
+
+	user := byName["user"]
+	assert.Equal(t, "Fetch a user by ID", user.Description)
+	assert.Equal(t, []string{"id"}, user.InputSchema.Required)
+	assert.Equal(t, "string", user.InputSchema.Properties["id"].Type)
+	assert.Equal(t, &mcp.Operation{Method: "GET", Path: "/query/user", BaseURL: "http://localhost", OperationID: "user"}, user.Operation)
+
+	posts := byName["posts"]
+	assert.Empty(t, posts.InputSchema.Required)
+	assert.Equal(t, []string{"DRAFT", "PUBLISHED", "ARCHIVED"}, posts.InputSchema.Properties["status"].Enum)
+	assert.Equal(t, "integer", posts.InputSchema.Properties["first"].Type)
+	assert.Equal(t, float64(20), posts.InputSchema.Properties["first"].Default)
+
+	create := byName["create_post"]
+	assert.Equal(t, "POST", create.Operation.Method)
+	assert.Equal(t, "/mutation/createPost", create.Operation.Path)
+	input := create.InputSchema.Properties["input"]
+	assert.Equal(t, "object", input.Type)
+	assert.Equal(t, "(object with 3 properties) - properties: body, status, title", input.Description)
+
+	query := byName[QueryToolName]
+	assert.Equal(t, "POST", query.Operation.Method)
+	assert.Equal(t, []string{"query"}, query.InputSchema.Required)
+}
+
+func TestBuildTools_Filters(t *testing.T) {
+	cfg := testConfig("http://localhost")
+	cfg.Safety.ReadOnly = true
+	tools, err := BuildTools(context.Background(), cfg, logrus.New())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user", "posts", "search"}, toolNames(tools))
+
+	cfg = testConfig("http://localhost")
+	cfg.Filters.ExcludePaths = []string{"/query/search"}
+	cfg.OpenAPI.GraphQL.DisableQueryTool = true
+	tools, err = BuildTools(context.Background(), cfg, logrus.New())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"user", "posts", "create_post", "delete_post"}, toolNames(tools))
+}
+
+func toolNames(tools []mcp.Tool) []string {
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	return names
+}
+
+func TestDocument_SelectionDepth(t *testing.T) {
+	schema, err := LoadSchema("../../examples/blog.graphql")
+	require.NoError(t, err)
+	cfg := testConfig("http://localhost")
+	g := NewGenerator(schema, cfg, logrus.New(), nil)
+
+	user := schema.Query().Fields[0]
+	assert.Equal(t,
+		"query user($id: ID!) { user(id: $id) { id name email posts { id title body status publishedAt } } }",
+		g.document("query", user))
+
+	search := schema.Query().Fields[2]
+	assert.Equal(t,
+		"query search($term: String!) { search(term: $term) { __typename ... on User { id name email } ... on Post { id title body status publishedAt } } }",
+		withDepth(t, schema, 1).document("query", search))
+
+	deletePost := schema.Mutation().Fields[1]
+	assert.Equal(t, "mutation deletePost($id: ID!) { deletePost(id: $id) }", g.document("mutation", deletePost))
+}
+
+func withDepth(t *testing.T, schema *Schema, depth int) *Generator {
+	t.Helper()
+	cfg := testConfig("http://localhost")
+	cfg.OpenAPI.GraphQL.MaxDepth = depth
+	return NewGenerator(schema, cfg, logrus.New(), nil)
+}
+
+func TestFieldHandler(t *testing.T) {
+	server, received := newTestServer(t, func(request graphQLRequest) interface{} {
+		if request.Variables["id"] == "missing" {
+			return map[string]interface{}{
+				"data":   map[string]interface{}{"user": nil},
+				"errors": []interface{}{map[string]interface{}{"message": "user not found"}},
+			}
+		}
+		return map[string]interface{}{"data": map[string]interface{}{"user": map[string]interface{}{"id": "1", "name": "Ada"}}}
+	})
+
+	tools, err := BuildTools(context.Background(), testConfig(server.URL), logrus.New())
+	require.NoError(t, err)
+	user := toolsByName(tools)["user"]
+
+	result, err := user.Handler(context.Background(), map[string]interface{}{"id": "1", "unknown": true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"id": "1", "name": "Ada"}, result)
+	require.Len(t, *received, 1)
+	assert.Equal(t, map[string]interface{}{"id": "1"}, (*received)[0].Variables)
+	assert.Contains(t, (*received)[0].Query, "user(id: $id)")
+
+	_, err = user.Handler(context.Background(), map[string]interface{}{"id": "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GraphQL errors: user not found")
+}
+
+func TestQueryHandler(t *testing.T) {
+	server, received := newTestServer(t, func(request graphQLRequest) interface{} {
+		return map[string]interface{}{
+			"data":   map[string]interface{}{"posts": []interface{}{}},
+			"errors": []interface{}{map[string]interface{}{"message": "partial failure", "path": []interface{}{"posts"}}},
+		}
+	})
+
+	tools, err := BuildTools(context.Background(), testConfig(server.URL), logrus.New())
+	require.NoError(t, err)
+	query := toolsByName(tools)[QueryToolName]
+
+	result, err := query.Handler(context.Background(), map[string]interface{}{
+		"query":          "query Recent($n: Int) { posts(first: $n) { id } }",
+		"variables":      map[string]interface{}{"n": 2},
+		"operation_name": "Recent",
+	})
+	require.NoError(t, err)
+	partial := result.(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"posts": []interface{}{}}, partial["data"])
+	assert.Equal(t, []responseError{{Message: "partial failure", Path: []interface{}{"posts"}}}, partial["errors"])
+	assert.Equal(t, "Recent", (*received)[0].OperationName)
+	assert.Equal(t, float64(2), (*received)[0].Variables["n"])
+
+	_, err = query.Handler(context.Background(), map[string]interface{}{"query": " "})
+	assert.EqualError(t, err, "query is required")
+}
+
+func TestBuildTools_Introspection(t *testing.T) {
+	schema, err := LoadSchema("../../examples/blog.graphql")
+	require.NoError(t, err)
+	fromSDL, err := NewGenerator(schema, testConfig("http://localhost"), logrus.New(), nil).GenerateTools()
+	require.NoError(t, err)
+
+	server, received := newTestServer(t, func(graphQLRequest) interface{} {
+		return map[string]interface{}{"data": map[string]interface{}{"__schema": schema}}
+	})
+	cfg := testConfig(server.URL)
+	cfg.OpenAPI.GraphQL.SchemaPath = ""
+	introspected, err := BuildTools(context.Background(), cfg, logrus.New())
+	require.NoError(t, err)
+
+	assert.Contains(t, (*received)[0].Query, "__schema")
+	require.Len(t, introspected, len(fromSDL))
+	for i := range fromSDL {
+		assert.Equal(t, fromSDL[i].Name, introspected[i].Name)
+		assert.Equal(t, fromSDL[i].InputSchema, introspected[i].InputSchema)
+	}
+}
+
+func TestToolName(t *testing.T) {
+	assert.Equal(t, "create_post", toolName("createPost"))
+	assert.Equal(t, "user", toolName("user"))
+	assert.Equal(t, "get_user_by_id", toolName("getUserById"))
+}
@@ -0,0 +1,243 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"api-to-mcp/internal/utils"
+)
+
+// Type kinds, as reported by introspection
+const (
+	KindScalar      = "SCALAR"
+	KindObject      = "OBJECT"
+	KindInterface   = "INTERFACE"
+	KindUnion       = "UNION"
+	KindEnum        = "ENUM"
+	KindInputObject = "INPUT_OBJECT"
+	KindList        = "LIST"
+	KindNonNull     = "NON_NULL"
+)
+
+// Schema is the subset of a GraphQL schema needed to generate tools. Its JSON form is the
+// __schema object returned by introspection
+type Schema struct {
+	QueryType    *NamedRef `json:"queryType"`
+	MutationType *NamedRef `json:"mutationType"`
+	Types        []*Type   `json:"types"`
+
+	byName map[string]*Type
+}
+
+// NamedRef refers to a type by name
+type NamedRef struct {
+	Name string `json:"name"`
+}
+
+// Type is a named type
+type Type struct {
+	Kind          string       `json:"kind"`
+	Name          string       `json:"name"`
+	Description   string       `json:"description"`
+	Fields        []Field      `json:"fields"`
+	InputFields   []InputValue `json:"inputFields"`
+	EnumValues    []EnumValue  `json:"enumValues"`
+	Interfaces    []TypeRef    `json:"interfaces"`
+	PossibleTypes []TypeRef    `json:"possibleTypes"`
+}
+
+// Field is a field of an object or interface type
+type Field struct {
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Args        []InputValue `json:"args"`
+	Type        TypeRef      `json:"type"`
+}
+
+// InputValue is a field argument or an input object field
+type InputValue struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	Type         TypeRef `json:"type"`
+	DefaultValue *string `json:"defaultValue"`
+}
+
+// EnumValue is a value of an enum type
+type EnumValue struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// TypeRef references a type, wrapped in any number of list and non-null modifiers
+type TypeRef struct {
+	Kind   string   `json:"kind"`
+	Name   string   `json:"name"`
+	OfType *TypeRef `json:"ofType"`
+}
+
+// Named returns the name of the innermost type
+func (r TypeRef) Named() string {
+	for ref := &r; ref != nil; ref = ref.OfType {
+		if ref.Name != "" {
+			return ref.Name
+		}
+	}
+	return ""
+}
+
+// NonNull reports whether the outermost modifier is non-null
+func (r TypeRef) NonNull() bool {
+	return r.Kind == KindNonNull
+}
+
+// IsList reports whether the type is a list once any non-null modifier is removed
+func (r TypeRef) IsList() bool {
+	if r.Kind == KindNonNull && r.OfType != nil {
+		return r.OfType.Kind == KindList
+	}
+	return r.Kind == KindList
+}
+
+// String renders the reference in GraphQL syntax, such as [User!]!
+func (r TypeRef) String() string {
+	switch r.Kind {
+	case KindNonNull:
+		if r.OfType == nil {
+			return "!"
+		}
+		return r.OfType.String() + "!"
+	case KindList:
+		if r.OfType == nil {
+			return "[]"
+		}
+		return "[" + r.OfType.String() + "]"
+	default:
+		return r.Name
+	}
+}
+
+// Type returns a named type, or nil if the schema does not define it
+func (s *Schema) Type(name string) *Type {
+	if s.byName == nil {
+		s.byName = make(map[string]*Type, len(s.Types))
+		for _, t := range s.Types {
+			s.byName[t.Name] = t
+		}
+	}
+	return s.byName[name]
+}
+
+// Query returns the root query type
+func (s *Schema) Query() *Type {
+	if s.QueryType == nil {
+		return s.Type("Query")
+	}
+	return s.Type(s.QueryType.Name)
+}
+
+// Mutation returns the root mutation type, or nil when the schema has none
+func (s *Schema) Mutation() *Type {
+	if s.MutationType == nil {
+		return s.Type("Mutation")
+	}
+	return s.Type(s.MutationType.Name)
+}
+
+// introspectionQuery asks for every type with the fields, arguments and modifiers tools need
+const introspectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    types {
+      kind name description
+      fields(includeDeprecated: false) { name description args { ...InputValue } type { ...TypeRef } }
+      inputFields { ...InputValue }
+      enumValues(includeDeprecated: false) { name description }
+      interfaces { ...TypeRef }
+      possibleTypes { ...TypeRef }
+    }
+  }
+}
+fragment InputValue on __InputValue { name description type { ...TypeRef } defaultValue }
+fragment TypeRef on __Type {
+  kind name
+  ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } }
+}`
+
+// Introspect fetches the schema of a GraphQL endpoint
+func Introspect(ctx context.Context, client *utils.HTTPClient, endpoint string) (*Schema, error) {
+	response, err := client.MakeRequest(ctx, "POST", endpoint, map[string]interface{}{
+		"body": map[string]interface{}{"query": introspectionQuery},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("introspection request failed: %w", err)
+	}
+
+	data, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode introspection result: %w", err)
+	}
+	var result struct {
+		Data struct {
+			Schema *Schema `json:"__schema"`
+		} `json:"data"`
+		Errors []responseError `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode introspection result: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return nil, fmt.Errorf("introspection failed: %s", joinErrors(result.Errors))
+	}
+	if result.Data.Schema == nil {
+		return nil, fmt.Errorf("introspection returned no schema")
+	}
+	return result.Data.Schema, nil
+}
+
+// LoadSchema reads a schema from an SDL file or from a saved introspection result, either the
+// full {"data": {"__schema": ...}} response or just the __schema object
+func LoadSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GraphQL schema: %w", err)
+	}
+
+	if json.Valid(data) {
+		var saved struct {
+			Data struct {
+				Schema *Schema `json:"__schema"`
+			} `json:"data"`
+			Schema *Schema `json:"__schema"`
+		}
+		if err := json.Unmarshal(data, &saved); err != nil {
+			return nil, fmt.Errorf("failed to decode introspection result %s: %w", path, err)
+		}
+		if saved.Data.Schema != nil {
+			return saved.Data.Schema, nil
+		}
+		if saved.Schema != nil {
+			return saved.Schema, nil
+		}
+		return nil, fmt.Errorf("%s does not contain an introspection result", path)
+	}
+
+	schema, err := ParseSDL(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL schema %s: %w", path, err)
+	}
+	return schema, nil
+}
+
+// IsSDLFile reports whether a path names a GraphQL SDL file by its extension
+func IsSDLFile(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".graphql", ".graphqls", ".gql":
+		return true
+	}
+	return false
+}
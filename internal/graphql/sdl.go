@@ -0,0 +1,613 @@
+package graphql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// builtinScalars are defined by every schema
+var builtinScalars = []string{"Int", "Float", "String", "Boolean", "ID"}
+
+// token kinds produced by the SDL lexer
+const (
+	tokenEOF = iota
+	tokenPunct
+	tokenName
+	tokenNumber
+	tokenString
+)
+
+type token struct {
+	kind  int
+	value string
+	start int
+	end   int
+	line  int
+}
+
+// ParseSDL parses a schema written in the GraphQL schema definition language. Directives and
+// directive definitions are accepted and ignored
+func ParseSDL(source string) (*Schema, error) {
+	tokens, err := lex(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &sdlParser{source: source, tokens: tokens, types: make(map[string]*Type)}
+	if err := p.parseDocument(); err != nil {
+		return nil, err
+	}
+	return p.schema(), nil
+}
+
+// lex splits SDL source into tokens, dropping whitespace, commas and comments
+func lex(source string) ([]token, error) {
+	var tokens []token
+	line := 1
+	for i := 0; i < len(source); {
+		c := source[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(source) && source[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(source[i:], "..."):
+			tokens = append(tokens, token{kind: tokenPunct, value: "...", start: i, end: i + 3, line: line})
+			i += 3
+		case strings.ContainsRune("!$&()/:=@[]{}|", rune(c)):
+			tokens = append(tokens, token{kind: tokenPunct, value: string(c), start: i, end: i + 1, line: line})
+			i++
+		case strings.HasPrefix(source[i:], `"""`):
+			end := strings.Index(source[i+3:], `"""`)
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated block string", line)
+			}
+			raw := source[i+3 : i+3+end]
+			tokens = append(tokens, token{kind: tokenString, value: blockString(raw), start: i, end: i + 6 + end, line: line})
+			line += strings.Count(raw, "\n")
+			i += 6 + end
+		case c == '"':
+			j := i + 1
+			var value strings.Builder
+			for ; j < len(source) && source[j] != '"'; j++ {
+				if source[j] == '\n' {
+					return nil, fmt.Errorf("line %d: unterminated string", line)
+				}
+				if source[j] == '\\' && j+1 < len(source) {
+					j++
+					switch source[j] {
+					case 'n':
+						value.WriteByte('\n')
+					case 't':
+						value.WriteByte('\t')
+					default:
+						value.WriteByte(source[j])
+					}
+					continue
+				}
+				value.WriteByte(source[j])
+			}
+			if j >= len(source) {
+				return nil, fmt.Errorf("line %d: unterminated string", line)
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value.String(), start: i, end: j + 1, line: line})
+			i = j + 1
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := i + 1
+			for j < len(source) && strings.ContainsRune("0123456789.eE+-", rune(source[j])) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: source[i:j], start: i, end: j, line: line})
+			i = j
+		case c == '_' || unicode.IsLetter(rune(c)):
+			j := i + 1
+			for j < len(source) && (source[j] == '_' || unicode.IsLetter(rune(source[j])) || unicode.IsDigit(rune(source[j]))) {
+				j++
+			}
+			tokens = append(tokens, token{kind: tokenName, value: source[i:j], start: i, end: j, line: line})
+			i = j
+		default:
+			return nil, fmt.Errorf("line %d: unexpected character %q", line, c)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, start: len(source), end: len(source), line: line}), nil
+}
+
+// blockString removes the common indentation and surrounding blank lines of a block string
+func blockString(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(raw, `\"""`, `"""`), "\n")
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i := 1; i < len(lines) && indent > 0; i++ {
+		if len(lines[i]) >= indent {
+			lines[i] = lines[i][indent:]
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// sdlParser builds a schema from SDL tokens
+type sdlParser struct {
+	source   string
+	tokens   []token
+	pos      int
+	types    map[string]*Type
+	order    []string
+	query    string
+	mutation string
+}
+
+func (p *sdlParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *sdlParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// is reports whether the next token is the given punctuator or keyword
+func (p *sdlParser) is(value string) bool {
+	t := p.peek()
+	return (t.kind == tokenPunct || t.kind == tokenName) && t.value == value
+}
+
+// skip consumes the next token if it is the given punctuator or keyword
+func (p *sdlParser) skip(value string) bool {
+	if p.is(value) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *sdlParser) expect(value string) error {
+	if !p.skip(value) {
+		return p.unexpected(fmt.Sprintf("%q", value))
+	}
+	return nil
+}
+
+func (p *sdlParser) name() (string, error) {
+	t := p.peek()
+	if t.kind != tokenName {
+		return "", p.unexpected("a name")
+	}
+	p.pos++
+	return t.value, nil
+}
+
+func (p *sdlParser) unexpected(expected string) error {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return fmt.Errorf("line %d: expected %s, found end of document", t.line, expected)
+	}
+	return fmt.Errorf("line %d: expected %s, found %q", t.line, expected, t.value)
+}
+
+// description consumes an optional description string
+func (p *sdlParser) description() string {
+	if t := p.peek(); t.kind == tokenString {
+		p.pos++
+		return t.value
+	}
+	return ""
+}
+
+// define returns the named type, creating it on first use
+func (p *sdlParser) define(kind, name string) (*Type, error) {
+	if t, exists := p.types[name]; exists {
+		if t.Kind != kind {
+			return nil, fmt.Errorf("type %s is defined as both %s and %s", name, t.Kind, kind)
+		}
+		return t, nil
+	}
+	t := &Type{Kind: kind, Name: name}
+	p.types[name] = t
+	p.order = append(p.order, name)
+	return t, nil
+}
+
+func (p *sdlParser) parseDocument() error {
+	for p.peek().kind != tokenEOF {
+		if err := p.parseDefinition(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *sdlParser) parseDefinition() error {
+	description := p.description()
+	p.skip("extend")
+
+	keyword := p.peek()
+	if keyword.kind != tokenName {
+		return p.unexpected("a definition")
+	}
+	p.pos++
+
+	switch keyword.value {
+	case "schema":
+		return p.parseSchemaDefinition()
+	case "directive":
+		return p.parseDirectiveDefinition()
+	case "scalar":
+		return p.parseNamed(KindScalar, description, nil)
+	case "type":
+		return p.parseNamed(KindObject, description, p.parseFieldsDefinition)
+	case "interface":
+		return p.parseNamed(KindInterface, description, p.parseFieldsDefinition)
+	case "input":
+		return p.parseNamed(KindInputObject, description, p.parseInputFieldsDefinition)
+	case "enum":
+		return p.parseNamed(KindEnum, description, p.parseEnumValues)
+	case "union":
+		return p.parseNamed(KindUnion, description, p.parseUnionMembers)
+	default:
+		return fmt.Errorf("line %d: unsupported definition %q", keyword.line, keyword.value)
+	}
+}
+
+// parseNamed parses a type definition or extension; body parses what follows the directives
+func (p *sdlParser) parseNamed(kind, description string, body func(*Type) error) error {
+	name, err := p.name()
+	if err != nil {
+		return err
+	}
+	t, err := p.define(kind, name)
+	if err != nil {
+		return err
+	}
+	if description != "" {
+		t.Description = description
+	}
+
+	if p.skip("implements") {
+		p.skip("&")
+		for p.peek().kind == tokenName && !p.isDefinitionStart() {
+			iface, _ := p.name()
+			t.Interfaces = append(t.Interfaces, TypeRef{Kind: KindInterface, Name: iface})
+			if !p.skip("&") {
+				break
+			}
+		}
+	}
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if body == nil {
+		return nil
+	}
+	return body(t)
+}
+
+// isDefinitionStart reports whether the next token begins a new definition
+func (p *sdlParser) isDefinitionStart() bool {
+	switch p.peek().value {
+	case "schema", "scalar", "type", "interface", "union", "enum", "input", "directive", "extend":
+		return p.tokens[p.pos+1].kind == tokenName || p.tokens[p.pos+1].kind == tokenEOF
+	}
+	return false
+}
+
+func (p *sdlParser) parseSchemaDefinition() error {
+	if err := p.skipDirectives(); err != nil {
+		return err
+	}
+	if !p.skip("{") {
+		return nil
+	}
+	for !p.skip("}") {
+		operation, err := p.name()
+		if err != nil {
+			return err
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		name, err := p.name()
+		if err != nil {
+			return err
+		}
+		switch operation {
+		case "query":
+			p.query = name
+		case "mutation":
+			p.mutation = name
+		}
+	}
+	return nil
+}
+
+func (p *sdlParser) parseDirectiveDefinition() error {
+	if err := p.expect("@"); err != nil {
+		return err
+	}
+	if _, err := p.name(); err != nil {
+		return err
+	}
+	if p.is("(") {
+		if _, err := p.parseArgumentsDefinition(); err != nil {
+			return err
+		}
+	}
+	p.skip("repeatable")
+	if err := p.expect("on"); err != nil {
+		return err
+	}
+	p.skip("|")
+	for {
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if !p.skip("|") {
+			return nil
+		}
+	}
+}
+
+func (p *sdlParser) parseFieldsDefinition(t *Type) error {
+	if !p.skip("{") {
+		return nil
+	}
+	for !p.skip("}") {
+		field := Field{Description: p.description()}
+		var err error
+		if field.Name, err = p.name(); err != nil {
+			return err
+		}
+		if p.is("(") {
+			if field.Args, err = p.parseArgumentsDefinition(); err != nil {
+				return err
+			}
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		if field.Type, err = p.parseType(); err != nil {
+			return err
+		}
+		if err := p.skipDirectives(); err != nil {
+			return err
+		}
+		t.Fields = append(t.Fields, field)
+	}
+	return nil
+}
+
+func (p *sdlParser) parseInputFieldsDefinition(t *Type) error {
+	if !p.skip("{") {
+		return nil
+	}
+	for !p.skip("}") {
+		value, err := p.parseInputValue()
+		if err != nil {
+			return err
+		}
+		t.InputFields = append(t.InputFields, value)
+	}
+	return nil
+}
+
+func (p *sdlParser) parseArgumentsDefinition() ([]InputValue, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	args := make([]InputValue, 0)
+	for !p.skip(")") {
+		value, err := p.parseInputValue()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
+	}
+	return args, nil
+}
+
+func (p *sdlParser) parseInputValue() (InputValue, error) {
+	value := InputValue{Description: p.description()}
+	var err error
+	if value.Name, err = p.name(); err != nil {
+		return value, err
+	}
+	if err := p.expect(":"); err != nil {
+		return value, err
+	}
+	if value.Type, err = p.parseType(); err != nil {
+		return value, err
+	}
+	if p.skip("=") {
+		start := p.peek().start
+		if err := p.skipValue(); err != nil {
+			return value, err
+		}
+		raw := p.source[start:p.tokens[p.pos-1].end]
+		value.DefaultValue = &raw
+	}
+	return value, p.skipDirectives()
+}
+
+func (p *sdlParser) parseEnumValues(t *Type) error {
+	if !p.skip("{") {
+		return nil
+	}
+	for !p.skip("}") {
+		value := EnumValue{Description: p.description()}
+		var err error
+		if value.Name, err = p.name(); err != nil {
+			return err
+		}
+		if err := p.skipDirectives(); err != nil {
+			return err
+		}
+		t.EnumValues = append(t.EnumValues, value)
+	}
+	return nil
+}
+
+func (p *sdlParser) parseUnionMembers(t *Type) error {
+	if !p.skip("=") {
+		return nil
+	}
+	p.skip("|")
+	for {
+		member, err := p.name()
+		if err != nil {
+			return err
+		}
+		t.PossibleTypes = append(t.PossibleTypes, TypeRef{Kind: KindObject, Name: member})
+		if !p.skip("|") {
+			return nil
+		}
+	}
+}
+
+// parseType parses a type reference such as [String!]!
+func (p *sdlParser) parseType() (TypeRef, error) {
+	var ref TypeRef
+	if p.skip("[") {
+		inner, err := p.parseType()
+		if err != nil {
+			return ref, err
+		}
+		if err := p.expect("]"); err != nil {
+			return ref, err
+		}
+		ref = TypeRef{Kind: KindList, OfType: &inner}
+	} else {
+		name, err := p.name()
+		if err != nil {
+			return ref, err
+		}
+		ref = TypeRef{Name: name}
+	}
+	if p.skip("!") {
+		inner := ref
+		ref = TypeRef{Kind: KindNonNull, OfType: &inner}
+	}
+	return ref, nil
+}
+
+// skipDirectives consumes directive applications such as @deprecated(reason: "...")
+func (p *sdlParser) skipDirectives() error {
+	for p.skip("@") {
+		if _, err := p.name(); err != nil {
+			return err
+		}
+		if !p.skip("(") {
+			continue
+		}
+		for !p.skip(")") {
+			if _, err := p.name(); err != nil {
+				return err
+			}
+			if err := p.expect(":"); err != nil {
+				return err
+			}
+			if err := p.skipValue(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipValue consumes a constant value
+func (p *sdlParser) skipValue() error {
+	t := p.next()
+	switch {
+	case t.kind == tokenName || t.kind == tokenNumber || t.kind == tokenString:
+		return nil
+	case t.value == "[":
+		for !p.skip("]") {
+			if p.peek().kind == tokenEOF {
+				return p.unexpected(`"]"`)
+			}
+			if err := p.skipValue(); err != nil {
+				return err
+			}
+		}
+		return nil
+	case t.value == "{":
+		for !p.skip("}") {
+			if _, err := p.name(); err != nil {
+				return err
+			}
+			if err := p.expect(":"); err != nil {
+				return err
+			}
+			if err := p.skipValue(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	p.pos--
+	return p.unexpected("a value")
+}
+
+// schema assembles the parsed types, adding the built-in scalars and resolving reference kinds
+func (p *sdlParser) schema() *Schema {
+	for _, name := range builtinScalars {
+		if _, exists := p.types[name]; !exists {
+			p.types[name] = &Type{Kind: KindScalar, Name: name}
+			p.order = append(p.order, name)
+		}
+	}
+
+	s := &Schema{}
+	if p.query != "" {
+		s.QueryType = &NamedRef{Name: p.query}
+	} else if _, exists := p.types["Query"]; exists {
+		s.QueryType = &NamedRef{Name: "Query"}
+	}
+	if p.mutation != "" {
+		s.MutationType = &NamedRef{Name: p.mutation}
+	} else if _, exists := p.types["Mutation"]; exists {
+		s.MutationType = &NamedRef{Name: "Mutation"}
+	}
+
+	for _, name := range p.order {
+		t := p.types[name]
+		for i := range t.Fields {
+			p.resolve(&t.Fields[i].Type)
+			for j := range t.Fields[i].Args {
+				p.resolve(&t.Fields[i].Args[j].Type)
+			}
+		}
+		for i := range t.InputFields {
+			p.resolve(&t.InputFields[i].Type)
+		}
+		s.Types = append(s.Types, t)
+	}
+	return s
+}
+
+// resolve fills in the kind of the named type at the core of a reference
+func (p *sdlParser) resolve(ref *TypeRef) {
+	for ; ref != nil; ref = ref.OfType {
+		if ref.Name != "" {
+			if t, exists := p.types[ref.Name]; exists {
+				ref.Kind = t.Kind
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package graphql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSDL_Example(t *testing.T) {
+	schema, err := LoadSchema("../../examples/blog.graphql")
+	require.NoError(t, err)
+
+	require.NotNil(t, schema.Query())
+	require.NotNil(t, schema.Mutation())
+	assert.Equal(t, "Query", schema.Query().Name)
+	assert.Len(t, schema.Query().Fields, 3)
+
+	user := schema.Query().Fields[0]
+	assert.Equal(t, "user", user.Name)
+	assert.Equal(t, "Fetch a user by ID", user.Description)
+	require.Len(t, user.Args, 1)
+	assert.Equal(t, "ID!", user.Args[0].Type.String())
+	assert.True(t, user.Args[0].Type.NonNull())
+	assert.Equal(t, "User", user.Type.Named())
+	assert.Equal(t, KindObject, user.Type.Kind)
+
+	posts := schema.Query().Fields[1]
+	assert.Equal(t, "[Post!]!", posts.Type.String())
+	assert.True(t, posts.Type.IsList())
+	require.NotNil(t, posts.Args[1].DefaultValue)
+	assert.Equal(t, "20", *posts.Args[1].DefaultValue)
+	assert.Equal(t, KindEnum, posts.Args[0].Type.Kind)
+
+	assert.Len(t, schema.Type("PostStatus").EnumValues, 3)
+	assert.Len(t, schema.Type("SearchResult").PossibleTypes, 2)
+	assert.Equal(t, KindInputObject, schema.Type("PostInput").Kind)
+	assert.Equal(t, KindScalar, schema.Type("String").Kind)
+	assert.Equal(t, KindScalar, schema.Type("DateTime").Kind)
+}
+
+func TestParseSDL_ExtensionsAndDirectives(t *testing.T) {
+	schema, err := ParseSDL(`
+directive @auth(requires: [String!] = ["admin"]) repeatable on FIELD_DEFINITION | OBJECT
+
+# Root query
+type Query {
+  hello(name: String = "world" @deprecated(reason: "unused")): String @auth(requires: ["user"])
+}
+
+extend type Query {
+  """
+    Current time,
+    in UTC
+  """
+  now: String
+}
+
+type RootMutation { ping: Boolean }
+schema { query: Query, mutation: RootMutation }
+`)
+	require.NoError(t, err)
+
+	query := schema.Query()
+	require.Len(t, query.Fields, 2)
+	assert.Equal(t, `"world"`, *query.Fields[0].Args[0].DefaultValue)
+	assert.Equal(t, "Current time,\nin UTC", query.Fields[1].Description)
+	assert.Equal(t, "RootMutation", schema.Mutation().Name)
+}
+
+func TestParseSDL_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		err    string
+	}{
+		{"missing type", "type Query { hello: }", "line 1: expected a name"},
+		{"unterminated string", "type Query {\n  \"oops\n  hello: String }", "line 2: unterminated string"},
+		{"conflicting kinds", "type A { x: Int }\ninput A { x: Int }", "type A is defined as both OBJECT and INPUT_OBJECT"},
+		{"operation", "query { hello }", `unsupported definition "query"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSDL(tt.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
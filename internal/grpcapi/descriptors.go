@@ -0,0 +1,158 @@
+package grpcapi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// reflectionServicePrefix names the reflection services, which never become tools
+const reflectionServicePrefix = "grpc.reflection."
+
+// LoadDescriptorSet reads a FileDescriptorSet written by protoc --descriptor_set_out. The set must
+// include imports so every referenced message can be resolved
+func LoadDescriptorSet(path string) (*protoregistry.Files, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read descriptor set: %w", err)
+	}
+	var set descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("failed to decode descriptor set %s: %w", path, err)
+	}
+	files, err := protodesc.NewFiles(&set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptor set %s (was it written with --include_imports?): %w", path, err)
+	}
+	return files, nil
+}
+
+// Reflect fetches the descriptors of a server's services through server reflection. Only the
+// listed services are fetched, or every service when none are listed
+func Reflect(ctx context.Context, conn grpc.ClientConnInterface, services []string) (*protoregistry.Files, error) {
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("server reflection unavailable: %w", err)
+	}
+	defer stream.CloseSend()
+
+	r := &reflector{stream: stream, files: make(map[string]*descriptorpb.FileDescriptorProto)}
+	if len(services) == 0 {
+		if services, err = r.listServices(); err != nil {
+			return nil, err
+		}
+	}
+	for _, service := range services {
+		if strings.HasPrefix(service, reflectionServicePrefix) {
+			continue
+		}
+		if err := r.fetch(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: service},
+		}); err != nil {
+			return nil, fmt.Errorf("failed to resolve service %s: %w", service, err)
+		}
+	}
+	if err := r.fetchDependencies(); err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range r.files {
+		set.File = append(set.File, file)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("invalid descriptors from server reflection: %w", err)
+	}
+	return files, nil
+}
+
+// reflector collects file descriptors over a reflection stream
+type reflector struct {
+	stream reflectionpb.ServerReflection_ServerReflectionInfoClient
+	files  map[string]*descriptorpb.FileDescriptorProto
+}
+
+// roundTrip sends one reflection request and waits for its response
+func (r *reflector) roundTrip(request *reflectionpb.ServerReflectionRequest) (*reflectionpb.ServerReflectionResponse, error) {
+	if err := r.stream.Send(request); err != nil {
+		return nil, fmt.Errorf("reflection request failed: %w", err)
+	}
+	response, err := r.stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("reflection request failed: %w", err)
+	}
+	if e := response.GetErrorResponse(); e != nil {
+		return nil, fmt.Errorf("reflection error %d: %s", e.GetErrorCode(), e.GetErrorMessage())
+	}
+	return response, nil
+}
+
+// listServices lists the services the server exposes
+func (r *reflector) listServices() ([]string, error) {
+	response, err := r.roundTrip(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{ListServices: ""},
+	})
+	if err != nil {
+		return nil, err
+	}
+	services := make([]string, 0)
+	for _, service := range response.GetListServicesResponse().GetService() {
+		services = append(services, service.GetName())
+	}
+	return services, nil
+}
+
+// fetch records the file descriptors returned for a request
+func (r *reflector) fetch(request *reflectionpb.ServerReflectionRequest) error {
+	response, err := r.roundTrip(request)
+	if err != nil {
+		return err
+	}
+	for _, raw := range response.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		var file descriptorpb.FileDescriptorProto
+		if err := proto.Unmarshal(raw, &file); err != nil {
+			return fmt.Errorf("failed to decode file descriptor: %w", err)
+		}
+		r.files[file.GetName()] = &file
+	}
+	return nil
+}
+
+// fetchDependencies requests imported files the server has not sent yet
+func (r *reflector) fetchDependencies() error {
+	for {
+		missing := ""
+		for _, file := range r.files {
+			for _, dependency := range file.GetDependency() {
+				if _, exists := r.files[dependency]; !exists {
+					missing = dependency
+					break
+				}
+			}
+			if missing != "" {
+				break
+			}
+		}
+		if missing == "" {
+			return nil
+		}
+
+		if err := r.fetch(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: missing},
+		}); err != nil {
+			return fmt.Errorf("failed to resolve %s: %w", missing, err)
+		}
+		if _, exists := r.files[missing]; !exists {
+			return fmt.Errorf("server reflection did not return %s", missing)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/generator"
+	"api-to-mcp/internal/redact"
+	"api-to-mcp/internal/safety"
+	"api-to-mcp/internal/secrets"
+	"api-to-mcp/internal/utils"
+	"api-to-mcp/internal/version"
+	"api-to-mcp/pkg/mcp"
+	"api-to-mcp/pkg/openapi"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// defaultTimeout bounds calls when no timeout is configured, as for tenants
+const defaultTimeout = 30 * time.Second
+
+// Methods are classified like REST verbs so read-only mode, filters, confirmation, write budgets
+// and role method rules apply: NO_SIDE_EFFECTS methods and read_methods matches are GET,
+// IDEMPOTENT methods are PUT and everything else is POST
+const (
+	readMethod       = "GET"
+	idempotentMethod = "PUT"
+	writeMethod      = "POST"
+)
+
+// Patterns splitting CamelCase method names into snake_case tool names
+var (
+	acronymBoundary = regexp.MustCompile(`([A-Z]+)([A-Z][a-z])`)
+	camelBoundary   = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+)
+
+// Generator generates MCP tools from gRPC service descriptors
+type Generator struct {
+	files    *protoregistry.Files
+	config   *config.Config
+	logger   *logrus.Logger
+	conn     grpc.ClientConnInterface
+	filter   *generator.MCPToolGenerator
+	redactor *redact.Redactor
+	timeout  time.Duration
+	names    map[string]bool
+}
+
+// BuildTools connects to the configured gRPC target, loads its descriptors from the descriptor
+// set or through server reflection, and generates its tools
+func BuildTools(ctx context.Context, cfg *config.Config, logger *logrus.Logger) ([]mcp.Tool, error) {
+	conn, err := Dial(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var files *protoregistry.Files
+	if set := cfg.OpenAPI.GRPC.DescriptorSet; set != "" {
+		files, err = LoadDescriptorSet(set)
+	} else {
+		logger.WithField("target", cfg.OpenAPI.GRPC.Target).Info("Loading gRPC descriptors through server reflection")
+		reflectCtx, cancel := context.WithTimeout(ctx, timeout(cfg))
+		files, err = Reflect(reflectCtx, conn, cfg.OpenAPI.GRPC.Services)
+		cancel()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return NewGenerator(files, cfg, logger, conn).GenerateTools()
+}
+
+// Dial creates a lazily connecting client for the configured target that only reaches addresses
+// allowed by the outbound policy
+func Dial(cfg *config.Config) (*grpc.ClientConn, error) {
+	outbound, err := generator.NewOutboundPolicy(cfg)
+	if err != nil {
+		return nil, err
+	}
+	target := cfg.OpenAPI.GRPC.Target
+	host, _, err := net.SplitHostPort(strings.TrimPrefix(target, "dns:///"))
+	if err != nil {
+		host = target
+	}
+	if err := outbound.CheckHost(host); err != nil {
+		return nil, err
+	}
+
+	creds := credentials.NewTLS(&tls.Config{MinVersion: tls.VersionTLS12})
+	if cfg.OpenAPI.GRPC.Plaintext {
+		creds = insecure.NewCredentials()
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: outbound.DialControl}
+	conn, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", address)
+		}),
+		grpc.WithUserAgent("api-to-mcp/"+version.Get().Version),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client for %s: %w", target, err)
+	}
+	return conn, nil
+}
+
+// timeout returns the configured call timeout
+func timeout(cfg *config.Config) time.Duration {
+	if cfg.OpenAPI.GRPC.Timeout > 0 {
+		return cfg.OpenAPI.GRPC.Timeout
+	}
+	return defaultTimeout
+}
+
+// NewGenerator creates a generator whose tools call methods over conn
+func NewGenerator(files *protoregistry.Files, cfg *config.Config, logger *logrus.Logger, conn grpc.ClientConnInterface) *Generator {
+	return &Generator{
+		files:   files,
+		config:  cfg,
+		logger:  logger,
+		conn:    conn,
+		filter:  generator.NewMCPToolGenerator(nil, cfg, logger),
+		timeout: timeout(cfg),
+	}
+}
+
+// GenerateTools generates a tool per unary method of the selected services
+func (g *Generator) GenerateTools() ([]mcp.Tool, error) {
+	g.logger.Info("Generating MCP tools from gRPC services")
+
+	redactor, err := generator.NewResponseRedactor(g.config)
+	if err != nil {
+		return nil, err
+	}
+	g.redactor = redactor
+	g.names = make(map[string]bool)
+
+	services, err := g.services()
+	if err != nil {
+		return nil, err
+	}
+
+	tools := make([]mcp.Tool, 0)
+	for _, service := range services {
+		methods := service.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			method := methods.Get(i)
+			fields := logrus.Fields{"service": service.FullName(), "method": method.Name()}
+			if method.IsStreamingClient() || method.IsStreamingServer() {
+				g.logger.WithFields(fields).Debug("Skipping streaming gRPC method")
+				continue
+			}
+			endpoint := openapi.Endpoint{
+				Method: g.classify(method),
+				Path:   fmt.Sprintf("/%s/%s", service.FullName(), method.Name()),
+			}
+			if !g.filter.IsEndpointIncluded(endpoint) {
+				g.logger.WithFields(fields).Debug("Skipping filtered gRPC method")
+				continue
+			}
+			tools = append(tools, g.methodTool(service, method, endpoint))
+		}
+	}
+
+	g.logger.WithField("tool_count", len(tools)).Info("Generated MCP tools")
+	if len(tools) == 0 {
+		return nil, fmt.Errorf("no tools could be generated: no unary methods passed the filters")
+	}
+	return tools, nil
+}
+
+// services returns the configured services, or every non-reflection service, ordered by name
+func (g *Generator) services() ([]protoreflect.ServiceDescriptor, error) {
+	if names := g.config.OpenAPI.GRPC.Services; len(names) > 0 {
+		services := make([]protoreflect.ServiceDescriptor, 0, len(names))
+		for _, name := range names {
+			descriptor, err := g.files.FindDescriptorByName(protoreflect.FullName(name))
+			if err != nil {
+				return nil, fmt.Errorf("gRPC service %s not found: %w", name, err)
+			}
+			service, ok := descriptor.(protoreflect.ServiceDescriptor)
+			if !ok {
+				return nil, fmt.Errorf("%s is not a gRPC service", name)
+			}
+			services = append(services, service)
+		}
+		return services, nil
+	}
+
+	services := make([]protoreflect.ServiceDescriptor, 0)
+	g.files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		for i := 0; i < file.Services().Len(); i++ {
+			service := file.Services().Get(i)
+			if !strings.HasPrefix(string(service.FullName()), reflectionServicePrefix) {
+				services = append(services, service)
+			}
+		}
+		return true
+	})
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].FullName() < services[j].FullName()
+	})
+	return services, nil
+}
+
+// classify maps a method to the HTTP method its safety semantics follow
+func (g *Generator) classify(method protoreflect.MethodDescriptor) string {
+	options, _ := method.Options().(*descriptorpb.MethodOptions)
+	if options.GetIdempotencyLevel() == descriptorpb.MethodOptions_NO_SIDE_EFFECTS {
+		return readMethod
+	}
+	for _, pattern := range g.config.OpenAPI.GRPC.ReadMethods {
+		if matched, _ := path.Match(pattern, string(method.Name())); matched {
+			return readMethod
+		}
+	}
+	if options.GetIdempotencyLevel() == descriptorpb.MethodOptions_IDEMPOTENT {
+		return idempotentMethod
+	}
+	return writeMethod
+}
+
+// methodTool generates the tool for a unary method
+func (g *Generator) methodTool(service protoreflect.ServiceDescriptor, method protoreflect.MethodDescriptor, endpoint openapi.Endpoint) mcp.Tool {
+	name := toolName(string(method.Name()))
+	if g.names[name] {
+		name = toolName(string(service.Name())) + "_" + name
+	}
+	g.names[name] = true
+
+	description := comments(method)
+	if description == "" {
+		description = fmt.Sprintf("gRPC %s/%s", service.FullName(), method.Name())
+	}
+
+	inputSchema := g.inputSchema(method.Input())
+	if safety.RequiresConfirmation(g.config.Safety.Confirmation, endpoint.Method, endpoint.Path) {
+		inputSchema.Properties[safety.ConfirmArgument] = mcp.Property{
+			Type:        "string",
+			Description: "Confirmation token returned by a previous call with the same arguments; omit on the first call",
+		}
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"tool_name": name,
+		"path":      endpoint.Path,
+		"method":    endpoint.Method,
+	}).Debug("Generated tool for gRPC method")
+
+	return mcp.Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: inputSchema,
+		Handler:     g.handler(method, endpoint.Path),
+		Operation: &mcp.Operation{
+			Method:      endpoint.Method,
+			Path:        endpoint.Path,
+			BaseURL:     g.config.OpenAPI.GRPC.Target,
+			OperationID: string(method.FullName()),
+		},
+	}
+}
+
+// toolName converts a CamelCase method name to a snake_case tool name
+func toolName(method string) string {
+	name := acronymBoundary.ReplaceAllString(method, "${1}_${2}")
+	return strings.ToLower(camelBoundary.ReplaceAllString(name, "${1}_${2}"))
+}
+
+// comments returns the leading comments of a descriptor, when the descriptors carry source info
+func comments(descriptor protoreflect.Descriptor) string {
+	location := descriptor.ParentFile().SourceLocations().ByDescriptor(descriptor)
+	return strings.TrimSpace(location.LeadingComments)
+}
+
+// inputSchema converts a request message into an input schema keyed by proto field names
+func (g *Generator) inputSchema(message protoreflect.MessageDescriptor) *mcp.InputSchema {
+	schema := &mcp.InputSchema{
+		Type:       "object",
+		Properties: make(map[string]mcp.Property),
+		Required:   make([]string, 0),
+	}
+	fields := message.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		schema.Properties[string(field.Name())] = g.property(field)
+		if field.Cardinality() == protoreflect.Required {
+			schema.Required = append(schema.Required, string(field.Name()))
+		}
+	}
+	return schema
+}
+
+// property converts a message field into an MCP property
+func (g *Generator) property(field protoreflect.FieldDescriptor) mcp.Property {
+	description := comments(field)
+	if oneof := field.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+		description = strings.TrimSpace(fmt.Sprintf("%s (one of %s)", description, oneof.Name()))
+	}
+
+	if field.IsMap() {
+		value := valueProperty(field.MapValue())
+		return mcp.Property{
+			Type:        "object",
+			Description: strings.TrimSpace(fmt.Sprintf("%s (map of %s)", description, value.Type)),
+		}
+	}
+
+	property := valueProperty(field)
+	if description != "" {
+		property.Description = strings.TrimSpace(description + " " + property.Description)
+	}
+	if field.IsList() {
+		property.Description = strings.TrimSpace(fmt.Sprintf("%s (array of %s)", property.Description, property.Type))
+		property.Type = "array"
+		property.Format = ""
+		property.Enum = nil
+	}
+	return property
+}
+
+// valueProperty converts the type of a single field value, ignoring repetition
+func valueProperty(field protoreflect.FieldDescriptor) mcp.Property {
+	switch field.Kind() {
+	case protoreflect.BoolKind:
+		return mcp.Property{Type: "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return mcp.Property{Type: "integer", Format: "int32"}
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return mcp.Property{Type: "integer", Format: "uint32"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return mcp.Property{Type: "integer", Format: "int64"}
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return mcp.Property{Type: "integer", Format: "uint64"}
+	case protoreflect.FloatKind:
+		return mcp.Property{Type: "number", Format: "float"}
+	case protoreflect.DoubleKind:
+		return mcp.Property{Type: "number", Format: "double"}
+	case protoreflect.BytesKind:
+		return mcp.Property{Type: "string", Format: "byte", Description: "(base64)"}
+	case protoreflect.EnumKind:
+		values := field.Enum().Values()
+		enum := make([]string, values.Len())
+		for i := range enum {
+			enum[i] = string(values.Get(i).Name())
+		}
+		return mcp.Property{Type: "string", Enum: enum}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageProperty(field.Message())
+	default:
+		return mcp.Property{Type: "string"}
+	}
+}
+
+// messageProperty converts a message type, mapping well-known types to their JSON forms
+func messageProperty(message protoreflect.MessageDescriptor) mcp.Property {
+	switch message.FullName() {
+	case "google.protobuf.Timestamp":
+		return mcp.Property{Type: "string", Format: "date-time"}
+	case "google.protobuf.Duration":
+		return mcp.Property{Type: "string", Description: "(duration such as 1.5s)"}
+	case "google.protobuf.FieldMask":
+		return mcp.Property{Type: "string", Description: "(comma-separated field paths)"}
+	case "google.protobuf.ListValue":
+		return mcp.Property{Type: "array"}
+	case "google.protobuf.Value":
+		return mcp.Property{Type: "object", Description: "(any JSON value)"}
+	case "google.protobuf.Struct", "google.protobuf.Any", "google.protobuf.Empty":
+		return mcp.Property{Type: "object"}
+	case "google.protobuf.BoolValue", "google.protobuf.StringValue", "google.protobuf.BytesValue",
+		"google.protobuf.Int32Value", "google.protobuf.UInt32Value", "google.protobuf.Int64Value",
+		"google.protobuf.UInt64Value", "google.protobuf.FloatValue", "google.protobuf.DoubleValue":
+		return valueProperty(message.Fields().ByName("value"))
+	}
+
+	fields := message.Fields()
+	names := make([]string, fields.Len())
+	for i := range names {
+		names[i] = string(fields.Get(i).Name())
+	}
+	sort.Strings(names)
+	return mcp.Property{
+		Type:        "object",
+		Description: fmt.Sprintf("(object with %d properties) - properties: %s", len(names), strings.Join(names, ", ")),
+	}
+}
+
+// handler transcodes JSON arguments into the request message, invokes the method and transcodes
+// the response back to JSON
+func (g *Generator) handler(method protoreflect.MethodDescriptor, fullMethod string) mcp.ToolHandler {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		arguments, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode arguments: %w", err)
+		}
+		request := dynamicpb.NewMessage(method.Input())
+		if err := protojson.Unmarshal(arguments, request); err != nil {
+			return nil, fmt.Errorf("invalid arguments for %s: %w", method.FullName(), err)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+		ctx, err = g.outgoingContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		g.logger.WithFields(logrus.Fields{
+			"method":         fullMethod,
+			"correlation_id": utils.RequestID(ctx),
+		}).Debug("Making gRPC call")
+
+		response := dynamicpb.NewMessage(method.Output())
+		if err := g.conn.Invoke(ctx, fullMethod, request, response); err != nil {
+			st := status.Convert(err)
+			return nil, fmt.Errorf("gRPC call failed: %s: %s", st.Code(), secrets.ScrubString(st.Message()))
+		}
+
+		data, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode response: %w", err)
+		}
+		var result interface{}
+		if err := json.Unmarshal(data, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		if g.redactor != nil {
+			return g.redactor.Value(result), nil
+		}
+		return result, nil
+	}
+}
+
+// outgoingContext attaches the correlation ID and upstream credentials as request metadata
+func (g *Generator) outgoingContext(ctx context.Context) (context.Context, error) {
+	if header, id := g.config.Observability.CorrelationHeader, utils.RequestID(ctx); header != "" && id != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(header), id)
+	}
+
+	key, prefix := "", ""
+	switch g.config.Auth.Type {
+	case "":
+		return ctx, nil
+	case "bearer":
+		key, prefix = "authorization", "Bearer "
+	case "apikey":
+		key = "x-api-key"
+	default:
+		g.logger.Warnf("Unsupported authentication type for gRPC: %s", g.config.Auth.Type)
+		return ctx, nil
+	}
+
+	value, err := g.config.Auth.Token.Reveal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve upstream credentials: %w", err)
+	}
+	defer secrets.Wipe(value)
+	return metadata.AppendToOutgoingContext(ctx, key, prefix+string(value)), nil
+}
@@ -0,0 +1,278 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// startServer serves the health service with server reflection on a loopback listener
+func startServer(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("orders", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	reflection.Register(server)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	return listener.Addr().String()
+}
+
+func testConfig(target string) *config.Config {
+	cfg := &config.Config{}
+	cfg.OpenAPI.GRPC = config.GRPCConfig{Enabled: true, Target: target, Plaintext: true}
+	cfg.Safety.Outbound.AllowPrivate = true
+	return cfg
+}
+
+func findTool(tools []mcp.Tool, name string) *mcp.Tool {
+	for i := range tools {
+		if tools[i].Name == name {
+			return &tools[i]
+		}
+	}
+	return nil
+}
+
+func TestBuildTools_Reflection(t *testing.T) {
+	cfg := testConfig(startServer(t))
+	cfg.OpenAPI.GRPC.ReadMethods = []string{"Check"}
+
+	tools, err := BuildTools(context.Background(), cfg, logrus.New())
+	require.NoError(t, err)
+
+	// Watch is server streaming and is skipped
+	require.Len(t, tools, 1)
+	check := tools[0]
+	assert.Equal(t, "check", check.Name)
+	assert.Equal(t, "GET", check.Operation.Method)
+	assert.Equal(t, "/grpc.health.v1.Health/Check", check.Operation.Path)
+	assert.Equal(t, "string", check.InputSchema.Properties["service"].Type)
+
+	result, err := check.Handler(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"status": "SERVING"}, result)
+
+	result, err = check.Handler(context.Background(), map[string]interface{}{"service": "orders"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"status": "NOT_SERVING"}, result)
+
+	_, err = check.Handler(context.Background(), map[string]interface{}{"service": "missing"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "gRPC call failed: NotFound")
+
+	_, err = check.Handler(context.Background(), map[string]interface{}{"unknown": 1})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid arguments for grpc.health.v1.Health.Check")
+}
+
+func TestBuildTools_DescriptorSet(t *testing.T) {
+	set := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		protodesc.ToFileDescriptorProto(healthpb.File_grpc_health_v1_health_proto),
+	}}
+	data, err := proto.Marshal(set)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "health.pb")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	cfg := testConfig(startServer(t))
+	cfg.OpenAPI.GRPC.DescriptorSet = path
+	cfg.OpenAPI.GRPC.Services = []string{"grpc.health.v1.Health"}
+
+	tools, err := BuildTools(context.Background(), cfg, logrus.New())
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "POST", tools[0].Operation.Method)
+
+	result, err := tools[0].Handler(context.Background(), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"status": "SERVING"}, result)
+}
+
+func TestBuildTools_UnknownService(t *testing.T) {
+	cfg := testConfig(startServer(t))
+	cfg.OpenAPI.GRPC.Services = []string{"example.Missing"}
+
+	_, err := BuildTools(context.Background(), cfg, logrus.New())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "example.Missing")
+}
+
+func TestBuildTools_OutboundPolicy(t *testing.T) {
+	cfg := testConfig(startServer(t))
+	cfg.Safety.Outbound.AllowPrivate = false
+
+	_, err := BuildTools(context.Background(), cfg, logrus.New())
+	require.Error(t, err)
+}
+
+// inventoryFiles builds a registry for a hand-written service covering the field type mapping
+func inventoryFiles(t *testing.T) *protoregistry.Files {
+	t.Helper()
+	timestamp := protodesc.ToFileDescriptorProto(timestamppb.File_google_protobuf_timestamp_proto)
+	file := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("inventory.proto"),
+		Package:    proto.String("example.inventory"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Condition"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("CONDITION_UNSPECIFIED"), Number: proto.Int32(0)},
+				{Name: proto.String("NEW"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Item"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("sku", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					field("count", 2, descriptorpb.FieldDescriptorProto_TYPE_INT64, ""),
+				},
+			},
+			{
+				Name: proto.String("UpdateItemRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					field("item", 1, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".example.inventory.Item"),
+					repeated(field("tags", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, "")),
+					field("condition", 3, descriptorpb.FieldDescriptorProto_TYPE_ENUM, ".example.inventory.Condition"),
+					field("seen_at", 4, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".google.protobuf.Timestamp"),
+					repeated(field("labels", 5, descriptorpb.FieldDescriptorProto_TYPE_MESSAGE, ".example.inventory.UpdateItemRequest.LabelsEntry")),
+					field("photo", 6, descriptorpb.FieldDescriptorProto_TYPE_BYTES, ""),
+					field("price", 7, descriptorpb.FieldDescriptorProto_TYPE_DOUBLE, ""),
+				},
+				NestedType: []*descriptorpb.DescriptorProto{{
+					Name: proto.String("LabelsEntry"),
+					Field: []*descriptorpb.FieldDescriptorProto{
+						field("key", 1, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+						field("value", 2, descriptorpb.FieldDescriptorProto_TYPE_STRING, ""),
+					},
+					Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+				}},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("Inventory"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{
+						Name:       proto.String("GetItem"),
+						InputType:  proto.String(".example.inventory.Item"),
+						OutputType: proto.String(".example.inventory.Item"),
+						Options:    &descriptorpb.MethodOptions{IdempotencyLevel: descriptorpb.MethodOptions_NO_SIDE_EFFECTS.Enum()},
+					},
+					{
+						Name:       proto.String("UpdateItem"),
+						InputType:  proto.String(".example.inventory.UpdateItemRequest"),
+						OutputType: proto.String(".example.inventory.Item"),
+						Options:    &descriptorpb.MethodOptions{IdempotencyLevel: descriptorpb.MethodOptions_IDEMPOTENT.Enum()},
+					},
+				},
+			},
+			{
+				Name: proto.String("Warehouse"),
+				Method: []*descriptorpb.MethodDescriptorProto{{
+					Name:       proto.String("GetItem"),
+					InputType:  proto.String(".example.inventory.Item"),
+					OutputType: proto.String(".example.inventory.Item"),
+				}},
+			},
+		},
+	}
+
+	files, err := protodesc.NewFiles(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{timestamp, file}})
+	require.NoError(t, err)
+	return files
+}
+
+func field(name string, number int32, kind descriptorpb.FieldDescriptorProto_Type, typeName string) *descriptorpb.FieldDescriptorProto {
+	f := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String(name),
+		JsonName: proto.String(name),
+		Number:   proto.Int32(number),
+		Type:     kind.Enum(),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+	}
+	if typeName != "" {
+		f.TypeName = proto.String(typeName)
+	}
+	return f
+}
+
+func repeated(f *descriptorpb.FieldDescriptorProto) *descriptorpb.FieldDescriptorProto {
+	f.Label = descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	return f
+}
+
+func TestGenerateTools_Schema(t *testing.T) {
+	cfg := testConfig("localhost:50051")
+	tools, err := NewGenerator(inventoryFiles(t), cfg, logrus.New(), nil).GenerateTools()
+	require.NoError(t, err)
+	require.Len(t, tools, 3)
+
+	get := findTool(tools, "get_item")
+	require.NotNil(t, get)
+	assert.Equal(t, "GET", get.Operation.Method)
+	assert.Equal(t, "/example.inventory.Inventory/GetItem", get.Operation.Path)
+	assert.Equal(t, "gRPC example.inventory.Inventory/GetItem", get.Description)
+
+	// The second GetItem is disambiguated by its service
+	warehouse := findTool(tools, "warehouse_get_item")
+	require.NotNil(t, warehouse)
+	assert.Equal(t, "POST", warehouse.Operation.Method)
+
+	update := findTool(tools, "update_item")
+	require.NotNil(t, update)
+	assert.Equal(t, "PUT", update.Operation.Method)
+
+	properties := update.InputSchema.Properties
+	assert.Equal(t, "object", properties["item"].Type)
+	assert.Contains(t, properties["item"].Description, "properties: count, sku")
+	assert.Equal(t, "array", properties["tags"].Type)
+	assert.Equal(t, "(array of string)", properties["tags"].Description)
+	assert.Equal(t, []string{"CONDITION_UNSPECIFIED", "NEW"}, properties["condition"].Enum)
+	assert.Equal(t, "date-time", properties["seen_at"].Format)
+	assert.Equal(t, "object", properties["labels"].Type)
+	assert.Equal(t, "(map of string)", properties["labels"].Description)
+	assert.Equal(t, "byte", properties["photo"].Format)
+	assert.Equal(t, "number", properties["price"].Type)
+}
+
+func TestGenerateTools_Filters(t *testing.T) {
+	cfg := testConfig("localhost:50051")
+	cfg.Safety.ReadOnly = true
+
+	tools, err := NewGenerator(inventoryFiles(t), cfg, logrus.New(), nil).GenerateTools()
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "get_item", tools[0].Name)
+}
+
+func TestToolName(t *testing.T) {
+	assert.Equal(t, "get_item", toolName("GetItem"))
+	assert.Equal(t, "get_http_config", toolName("GetHTTPConfig"))
+	assert.Equal(t, "check", toolName("Check"))
+}
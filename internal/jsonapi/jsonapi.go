@@ -0,0 +1,252 @@
+package jsonapi
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strconv"
+	"strings"
+
+	"api-to-mcp/pkg/mcp"
+)
+
+// MediaType is the JSON:API media type, which marks JSON:API operations in a specification
+const MediaType = "application/vnd.api+json"
+
+// Names of the page-based pagination parameters
+const (
+	PageNumber = "page[number]"
+	PageSize   = "page[size]"
+)
+
+// maxDepth bounds how deep included resources are nested into one another when flattening
+const maxDepth = 4
+
+// IsMediaType reports whether a content type is the JSON:API media type, with or without
+// parameters
+func IsMediaType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), MediaType)
+}
+
+// PageParameters describes the page[number] and page[size] parameters added to collections
+func PageParameters() map[string]mcp.Property {
+	one := 1.0
+	return map[string]mcp.Property{
+		PageNumber: {Type: "integer", Description: "Page to return, starting at 1", Minimum: &one},
+		PageSize:   {Type: "integer", Description: "Number of resources per page", Minimum: &one},
+	}
+}
+
+// IsDocument reports whether a response is a JSON:API document: an object with primary data
+// made of resource objects, or with errors or a jsonapi member
+func IsDocument(response interface{}) bool {
+	body, ok := response.(map[string]interface{})
+	if !ok {
+		return false
+	}
+	if _, ok := body["jsonapi"].(map[string]interface{}); ok {
+		return true
+	}
+	data, ok := body["data"]
+	if !ok {
+		_, hasErrors := body["errors"].([]interface{})
+		return hasErrors
+	}
+	switch data := data.(type) {
+	case map[string]interface{}:
+		return isResource(data)
+	case []interface{}:
+		for _, item := range data {
+			resource, ok := item.(map[string]interface{})
+			if !ok || !isResource(resource) {
+				return false
+			}
+		}
+		return len(data) > 0 || body["links"] != nil || body["meta"] != nil
+	}
+	return false
+}
+
+// isResource reports whether an object is a resource object or resource identifier
+func isResource(object map[string]interface{}) bool {
+	_, ok := object["type"].(string)
+	return ok
+}
+
+// Simplify rewrites a JSON:API document so results read as plain objects: each resource
+// becomes its id, type and attributes, with relationships as identifiers and relationship
+// links under links. With flatten, identifiers of included resources are replaced by the
+// resources themselves and the included member is dropped. A next_page member gives the
+// page[number] of the next page when the next link carries one
+func Simplify(response interface{}, flatten bool) interface{} {
+	body, ok := response.(map[string]interface{})
+	if !ok || !IsDocument(body) {
+		return response
+	}
+
+	s := &simplifier{flatten: flatten, included: make(map[string]map[string]interface{})}
+	included, _ := body["included"].([]interface{})
+	for _, item := range included {
+		if resource, ok := item.(map[string]interface{}); ok {
+			s.included[key(resource)] = resource
+		}
+	}
+
+	result := make(map[string]interface{}, len(body))
+	for name, value := range body {
+		switch name {
+		case "data":
+			result[name] = s.data(value, nil)
+		case "included":
+			if flatten {
+				continue
+			}
+			items := make([]interface{}, 0, len(included))
+			for _, item := range included {
+				items = append(items, s.value(item, nil))
+			}
+			result[name] = items
+		case "jsonapi":
+			// The version object says nothing about the results
+		default:
+			result[name] = value
+		}
+	}
+	if page, ok := nextPage(body); ok {
+		result["next_page"] = page
+	}
+	return result
+}
+
+// simplifier rewrites the resources of one document
+type simplifier struct {
+	flatten  bool
+	included map[string]map[string]interface{}
+}
+
+// data simplifies primary data or relationship data: a resource, an array of resources or null
+func (s *simplifier) data(data interface{}, ancestors []string) interface{} {
+	items, ok := data.([]interface{})
+	if !ok {
+		return s.value(data, ancestors)
+	}
+	simplified := make([]interface{}, len(items))
+	for i, item := range items {
+		simplified[i] = s.value(item, ancestors)
+	}
+	return simplified
+}
+
+// value simplifies a single resource object or identifier, resolving identifiers of included
+// resources when flattening
+func (s *simplifier) value(item interface{}, ancestors []string) interface{} {
+	resource, ok := item.(map[string]interface{})
+	if !ok || !isResource(resource) {
+		return item
+	}
+	k := key(resource)
+	if s.flatten && len(ancestors) > 0 && len(ancestors) < maxDepth && !contains(ancestors, k) {
+		if full, ok := s.included[k]; ok {
+			resource = full
+		}
+	}
+	return s.resource(resource, append(ancestors[:len(ancestors):len(ancestors)], k))
+}
+
+// resource merges a resource's attributes and relationships into one object next to its id
+// and type
+func (s *simplifier) resource(resource map[string]interface{}, ancestors []string) map[string]interface{} {
+	result := make(map[string]interface{})
+	attributes, _ := resource["attributes"].(map[string]interface{})
+	for name, value := range attributes {
+		result[name] = value
+	}
+
+	links := make(map[string]interface{})
+	if self := link(resource["links"], "self"); self != "" {
+		links["self"] = self
+	}
+	relationships, _ := resource["relationships"].(map[string]interface{})
+	for name, value := range relationships {
+		relationship, ok := value.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if data, ok := relationship["data"]; ok {
+			if _, exists := result[name]; !exists {
+				result[name] = s.data(data, ancestors)
+			}
+		}
+		related := link(relationship["links"], "related")
+		if related == "" {
+			related = link(relationship["links"], "self")
+		}
+		if related != "" {
+			links[name] = related
+		}
+	}
+	if _, exists := result["links"]; !exists && len(links) > 0 {
+		result["links"] = links
+	}
+	if meta, ok := resource["meta"]; ok {
+		if _, exists := result["meta"]; !exists {
+			result["meta"] = meta
+		}
+	}
+
+	// Identifiers come last so attributes cannot shadow them
+	result["type"] = resource["type"]
+	if id, ok := resource["id"]; ok {
+		result["id"] = id
+	} else if lid, ok := resource["lid"]; ok {
+		result["lid"] = lid
+	}
+	return result
+}
+
+// link returns a link by name from a links object; links are strings or objects with an href
+func link(links interface{}, name string) string {
+	object, ok := links.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	switch value := object[name].(type) {
+	case string:
+		return value
+	case map[string]interface{}:
+		href, _ := value["href"].(string)
+		return href
+	}
+	return ""
+}
+
+// nextPage returns the page[number] of the document's next link
+func nextPage(body map[string]interface{}) (int, bool) {
+	next := link(body["links"], "next")
+	if next == "" {
+		return 0, false
+	}
+	parsed, err := neturl.Parse(next)
+	if err != nil {
+		return 0, false
+	}
+	page, err := strconv.Atoi(parsed.Query().Get(PageNumber))
+	if err != nil {
+		return 0, false
+	}
+	return page, true
+}
+
+// key identifies a resource by type and id
+func key(resource map[string]interface{}) string {
+	return fmt.Sprintf("%v/%v", resource["type"], resource["id"])
+}
+
+func contains(keys []string, k string) bool {
+	for _, existing := range keys {
+		if existing == k {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package jsonapi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const articles = `{
+  "jsonapi": {"version": "1.1"},
+  "links": {
+    "self": "https://api.example.com/articles?page[number]=1",
+    "next": "https://api.example.com/articles?page%5Bnumber%5D=2&page%5Bsize%5D=1"
+  },
+  "meta": {"total": 2},
+  "data": [{
+    "type": "articles",
+    "id": "1",
+    "attributes": {"title": "JSON:API paints my bikeshed!"},
+    "relationships": {
+      "author": {
+        "links": {"self": "https://api.example.com/articles/1/relationships/author", "related": "https://api.example.com/articles/1/author"},
+        "data": {"type": "people", "id": "9"}
+      },
+      "comments": {"data": [{"type": "comments", "id": "5"}]},
+      "tags": {"links": {"related": {"href": "https://api.example.com/articles/1/tags"}}}
+    },
+    "links": {"self": "https://api.example.com/articles/1"}
+  }],
+  "included": [
+    {"type": "people", "id": "9", "attributes": {"name": "Dan"}, "relationships": {"articles": {"data": [{"type": "articles", "id": "1"}]}}},
+    {"type": "comments", "id": "5", "attributes": {"body": "First!"}, "relationships": {"author": {"data": {"type": "people", "id": "9"}}}}
+  ]
+}`
+
+func decode(t *testing.T, document string) interface{} {
+	t.Helper()
+	var value interface{}
+	require.NoError(t, json.Unmarshal([]byte(document), &value))
+	return value
+}
+
+func TestIsDocument(t *testing.T) {
+	assert.True(t, IsDocument(decode(t, articles)))
+	assert.True(t, IsDocument(decode(t, `{"data": {"type": "people", "id": "1"}}`)))
+	assert.True(t, IsDocument(decode(t, `{"data": [], "links": {"self": "/people"}}`)))
+	assert.True(t, IsDocument(decode(t, `{"errors": [{"status": "404"}]}`)))
+	assert.False(t, IsDocument(decode(t, `{"data": [1, 2]}`)))
+	assert.False(t, IsDocument(decode(t, `{"data": {"name": "x"}}`)))
+	assert.False(t, IsDocument(decode(t, `{"data": []}`)))
+	assert.False(t, IsDocument(decode(t, `[{"type": "people"}]`)))
+}
+
+func TestSimplify(t *testing.T) {
+	result := Simplify(decode(t, articles), false).(map[string]interface{})
+
+	assert.NotContains(t, result, "jsonapi")
+	assert.Equal(t, map[string]interface{}{"total": float64(2)}, result["meta"])
+	assert.Equal(t, 2, result["next_page"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"type":   "articles",
+		"id":     "1",
+		"title":  "JSON:API paints my bikeshed!",
+		"author": map[string]interface{}{"type": "people", "id": "9"},
+		"comments": []interface{}{
+			map[string]interface{}{"type": "comments", "id": "5"},
+		},
+		"links": map[string]interface{}{
+			"self":   "https://api.example.com/articles/1",
+			"author": "https://api.example.com/articles/1/author",
+			"tags":   "https://api.example.com/articles/1/tags",
+		},
+	}}, result["data"])
+
+	included := result["included"].([]interface{})
+	require.Len(t, included, 2)
+	assert.Equal(t, map[string]interface{}{
+		"type":     "people",
+		"id":       "9",
+		"name":     "Dan",
+		"articles": []interface{}{map[string]interface{}{"type": "articles", "id": "1"}},
+	}, included[0])
+}
+
+func TestSimplify_FlattenIncluded(t *testing.T) {
+	result := Simplify(decode(t, articles), true).(map[string]interface{})
+	assert.NotContains(t, result, "included")
+
+	article := result["data"].([]interface{})[0].(map[string]interface{})
+	author := article["author"].(map[string]interface{})
+	assert.Equal(t, "Dan", author["name"])
+	// Resolving stops at resources already on the path, so cycles end in identifiers
+	assert.Equal(t, []interface{}{map[string]interface{}{"type": "articles", "id": "1"}}, author["articles"])
+
+	comment := article["comments"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "First!", comment["body"])
+	// Nested includes are resolved too
+	assert.Equal(t, "Dan", comment["author"].(map[string]interface{})["name"])
+}
+
+func TestSimplify_LeavesOtherResponses(t *testing.T) {
+	plain := decode(t, `{"data": [1, 2], "next": 2}`)
+	assert.Equal(t, plain, Simplify(plain, true))
+	assert.Equal(t, "text", Simplify("text", false))
+
+	errors := decode(t, `{"errors": [{"status": "422", "detail": "title is required"}]}`)
+	assert.Equal(t, errors, Simplify(errors, false))
+
+	single := Simplify(decode(t, `{"data": {"type": "people", "id": "1", "attributes": {"id": "shadow", "name": "Ann"}}}`), false)
+	assert.Equal(t, map[string]interface{}{
+		"data": map[string]interface{}{"type": "people", "id": "1", "name": "Ann"},
+	}, single)
+}
+
+func TestIsMediaType(t *testing.T) {
+	assert.True(t, IsMediaType("application/vnd.api+json"))
+	assert.True(t, IsMediaType(`application/vnd.api+json; ext="https://jsonapi.org/ext/atomic"`))
+	assert.False(t, IsMediaType("application/json"))
+}
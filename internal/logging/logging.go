@@ -0,0 +1,119 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/redact"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Loggers holds the application, access and audit loggers; Access and Audit are nil when not configured
+type Loggers struct {
+	App    *logrus.Logger
+	Access *logrus.Logger
+	Audit  *logrus.Logger
+
+	closers []io.Closer
+}
+
+// NewLoggers builds the loggers described by the logging configuration
+func NewLoggers(cfg config.LoggingConfig) (*Loggers, error) {
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("invalid logging.level: %w", err)
+	}
+
+	formatter, err := newFormatter(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	loggers := &Loggers{}
+	if loggers.App, err = loggers.newLogger(cfg.Output, cfg.Rotation, level, formatter); err != nil {
+		loggers.Close()
+		return nil, err
+	}
+	if cfg.Access.Output != "" {
+		if loggers.Access, err = loggers.newLogger(cfg.Access.Output, cfg.Rotation, logrus.InfoLevel, formatter); err != nil {
+			loggers.Close()
+			return nil, err
+		}
+	}
+	if cfg.Audit.Output != "" {
+		if loggers.Audit, err = loggers.newLogger(cfg.Audit.Output, cfg.Rotation, logrus.InfoLevel, formatter); err != nil {
+			loggers.Close()
+			return nil, err
+		}
+	}
+
+	return loggers, nil
+}
+
+// Close closes every log file opened by the loggers
+func (l *Loggers) Close() error {
+	var firstErr error
+	for _, closer := range l.closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	l.closers = nil
+	return firstErr
+}
+
+// newLogger creates a logger writing to output
+func (l *Loggers) newLogger(output string, rotation config.RotationConfig, level logrus.Level, formatter logrus.Formatter) (*logrus.Logger, error) {
+	writer, err := l.open(output, rotation)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(writer)
+	logger.SetLevel(level)
+	logger.SetFormatter(formatter)
+	return logger, nil
+}
+
+// open resolves an output name: stdout, stderr (the default) or a rotated file path
+func (l *Loggers) open(output string, rotation config.RotationConfig) (io.Writer, error) {
+	switch output {
+	case "", "stderr":
+		return os.Stderr, nil
+	case "stdout":
+		return os.Stdout, nil
+	}
+
+	file, err := NewRotatingFile(output, int64(rotation.MaxSizeMB)*1024*1024, rotation.MaxAge, rotation.MaxBackups)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log output %s: %w", output, err)
+	}
+	l.closers = append(l.closers, file)
+	return file, nil
+}
+
+// newFormatter returns the configured formatter, wrapped with redaction when enabled
+func newFormatter(cfg config.LoggingConfig) (logrus.Formatter, error) {
+	var formatter logrus.Formatter = &logrus.TextFormatter{}
+	if cfg.Format == "json" {
+		formatter = &logrus.JSONFormatter{}
+	}
+
+	if !cfg.Redaction.Enabled {
+		return formatter, nil
+	}
+	redactor, err := redact.New(redact.Rules{
+		Fields:   cfg.Redaction.Fields,
+		Paths:    cfg.Redaction.Paths,
+		Patterns: cfg.Redaction.Patterns,
+		Mask:     cfg.Redaction.Mask,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid logging.redaction rules: %w", err)
+	}
+	return &redact.Formatter{Inner: formatter, Redactor: redactor}, nil
+}
@@ -0,0 +1,136 @@
+package logging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat names rotated files, e.g. server.log.20240102-150405.000
+const backupTimeFormat = "20060102-150405.000"
+
+// RotatingFile is an io.Writer appending to a file that is rotated by size and age
+type RotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// NewRotatingFile opens path for appending; a zero maxSize or maxAge disables that trigger
+// and a zero maxBackups keeps every rotated file
+func NewRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	f := &RotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p, rotating first when it would exceed the size limit or the file is too old
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sizeExceeded := f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize
+	ageExceeded := f.maxAge > 0 && time.Since(f.openedAt) >= f.maxAge
+	if sizeExceeded || ageExceeded {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// open opens the log file for appending, continuing an existing file
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	return nil
+}
+
+// rotate renames the current file with a timestamp suffix, reopens the path and prunes old backups
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+
+	backup := f.path + "." + time.Now().Format(backupTimeFormat)
+	if err := os.Rename(f.path, backup); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	return f.prune()
+}
+
+// prune removes the oldest backups beyond maxBackups
+func (f *RotatingFile) prune() error {
+	if f.maxBackups <= 0 {
+		return nil
+	}
+
+	backups, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return fmt.Errorf("failed to list log backups: %w", err)
+	}
+	rotated := make([]string, 0, len(backups))
+	for _, backup := range backups {
+		if _, err := time.Parse(backupTimeFormat, strings.TrimPrefix(backup, f.path+".")); err == nil {
+			rotated = append(rotated, backup)
+		}
+	}
+	if len(rotated) <= f.maxBackups {
+		return nil
+	}
+
+	// Timestamp suffixes sort chronologically
+	sort.Strings(rotated)
+	for _, backup := range rotated[:len(rotated)-f.maxBackups] {
+		if err := os.Remove(backup); err != nil {
+			return fmt.Errorf("failed to remove log backup: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile_RotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "server.log")
+	file, err := NewRotatingFile(path, 10, 0, 0)
+	require.NoError(t, err)
+	defer file.Close()
+
+	_, err = file.Write([]byte("12345678\n"))
+	require.NoError(t, err)
+	_, err = file.Write([]byte("abcdefgh\n"))
+	require.NoError(t, err)
+
+	current, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "abcdefgh\n", string(current))
+
+	backups, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	rotated, err := os.ReadFile(backups[0])
+	require.NoError(t, err)
+	assert.Equal(t, "12345678\n", string(rotated))
+}
+
+func TestRotatingFile_RotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	file, err := NewRotatingFile(path, 0, time.Millisecond, 0)
+	require.NoError(t, err)
+	defer file.Close()
+
+	time.Sleep(5 * time.Millisecond)
+	_, err = file.Write([]byte("line\n"))
+	require.NoError(t, err)
+
+	backups, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+}
+
+func TestRotatingFile_PrunesBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "server.log")
+	file, err := NewRotatingFile(path, 5, 0, 2)
+	require.NoError(t, err)
+	defer file.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := file.Write([]byte(strings.Repeat("x", 5)))
+		require.NoError(t, err)
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	backups, err := filepath.Glob(path + ".*")
+	require.NoError(t, err)
+	assert.Len(t, backups, 2)
+}
+
+func TestRotatingFile_AppendsToExistingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "server.log")
+	require.NoError(t, os.WriteFile(path, []byte("old\n"), 0644))
+
+	file, err := NewRotatingFile(path, 0, 0, 0)
+	require.NoError(t, err)
+	_, err = file.Write([]byte("new\n"))
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "old\nnew\n", string(content))
+}
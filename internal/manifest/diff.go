@@ -0,0 +1,179 @@
+package manifest
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Change describes a single difference between two versions of a tool
+type Change struct {
+	Message  string `json:"message"`
+	Breaking bool   `json:"breaking"`
+}
+
+// ToolChange groups the changes detected for one tool
+type ToolChange struct {
+	Name    string   `json:"name"`
+	Changes []Change `json:"changes"`
+}
+
+// DiffResult is the comparison of two toolsets
+type DiffResult struct {
+	Added   []string     `json:"added"`
+	Removed []string     `json:"removed"`
+	Changed []ToolChange `json:"changed"`
+}
+
+// HasBreakingChanges reports whether clients of the old toolset may fail against the new one
+func (d *DiffResult) HasBreakingChanges() bool {
+	if len(d.Removed) > 0 {
+		return true
+	}
+	for _, tool := range d.Changed {
+		for _, change := range tool.Changes {
+			if change.Breaking {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsEmpty reports whether the toolsets are identical
+func (d *DiffResult) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares two manifests. Removed tools, removed or retyped arguments, newly required
+// arguments and removed enum values are breaking; everything else is informational.
+func Diff(oldManifest, newManifest *Manifest) *DiffResult {
+	result := &DiffResult{
+		Added:   make([]string, 0),
+		Removed: make([]string, 0),
+		Changed: make([]ToolChange, 0),
+	}
+
+	oldTools := indexTools(oldManifest)
+	newTools := indexTools(newManifest)
+
+	for name := range newTools {
+		if _, exists := oldTools[name]; !exists {
+			result.Added = append(result.Added, name)
+		}
+	}
+	for name, oldTool := range oldTools {
+		newTool, exists := newTools[name]
+		if !exists {
+			result.Removed = append(result.Removed, name)
+			continue
+		}
+		if changes := diffTool(oldTool, newTool); len(changes) > 0 {
+			result.Changed = append(result.Changed, ToolChange{Name: name, Changes: changes})
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Removed)
+	sort.Slice(result.Changed, func(i, j int) bool {
+		return result.Changed[i].Name < result.Changed[j].Name
+	})
+
+	return result
+}
+
+// indexTools maps tool names to their manifest entries
+func indexTools(m *Manifest) map[string]Tool {
+	index := make(map[string]Tool, len(m.Tools))
+	for _, tool := range m.Tools {
+		index[tool.Name] = tool
+	}
+	return index
+}
+
+// diffTool compares two versions of the same tool
+func diffTool(oldTool, newTool Tool) []Change {
+	changes := make([]Change, 0)
+
+	if oldTool.Description != newTool.Description {
+		changes = append(changes, Change{Message: "description changed"})
+	}
+
+	if oldTool.Endpoint != nil && newTool.Endpoint != nil &&
+		(oldTool.Endpoint.Method != newTool.Endpoint.Method || oldTool.Endpoint.Path != newTool.Endpoint.Path) {
+		changes = append(changes, Change{
+			Message: fmt.Sprintf("endpoint changed from %s %s to %s %s",
+				oldTool.Endpoint.Method, oldTool.Endpoint.Path, newTool.Endpoint.Method, newTool.Endpoint.Path),
+		})
+	}
+
+	if oldTool.InputSchema == nil || newTool.InputSchema == nil {
+		return changes
+	}
+	oldProps := oldTool.InputSchema.Properties
+	newProps := newTool.InputSchema.Properties
+	oldRequired := stringSet(oldTool.InputSchema.Required)
+	newRequired := stringSet(newTool.InputSchema.Required)
+
+	for _, name := range sortedKeys(oldProps) {
+		newProp, exists := newProps[name]
+		if !exists {
+			changes = append(changes, Change{Message: fmt.Sprintf("argument %q removed", name), Breaking: true})
+			continue
+		}
+		oldProp := oldProps[name]
+		if oldProp.Type != newProp.Type {
+			changes = append(changes, Change{
+				Message:  fmt.Sprintf("argument %q type changed from %s to %s", name, oldProp.Type, newProp.Type),
+				Breaking: true,
+			})
+		}
+		if len(oldProp.Enum) > 0 {
+			allowed := stringSet(newProp.Enum)
+			for _, value := range oldProp.Enum {
+				if len(newProp.Enum) > 0 && !allowed[value] {
+					changes = append(changes, Change{
+						Message:  fmt.Sprintf("argument %q no longer accepts %q", name, value),
+						Breaking: true,
+					})
+				}
+			}
+		}
+	}
+
+	for _, name := range sortedKeys(newProps) {
+		if _, exists := oldProps[name]; !exists {
+			changes = append(changes, Change{
+				Message:  fmt.Sprintf("argument %q added", name),
+				Breaking: newRequired[name],
+			})
+			continue
+		}
+		if newRequired[name] && !oldRequired[name] {
+			changes = append(changes, Change{Message: fmt.Sprintf("argument %q is now required", name), Breaking: true})
+		}
+		if oldRequired[name] && !newRequired[name] {
+			changes = append(changes, Change{Message: fmt.Sprintf("argument %q is now optional", name)})
+		}
+	}
+
+	return changes
+}
+
+// stringSet builds a lookup set from a slice
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+	return set
+}
+
+// sortedKeys returns the keys of a map in sorted order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+package manifest
+
+import (
+	"testing"
+
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff_NoChanges(t *testing.T) {
+	m := &Manifest{Tools: []Tool{{Name: "a", InputSchema: &mcp.InputSchema{Type: "object"}}}}
+
+	result := Diff(m, m)
+
+	assert.True(t, result.IsEmpty())
+	assert.False(t, result.HasBreakingChanges())
+}
+
+func TestDiff_AddedAndRemoved(t *testing.T) {
+	oldManifest := &Manifest{Tools: []Tool{{Name: "a"}, {Name: "b"}}}
+	newManifest := &Manifest{Tools: []Tool{{Name: "b"}, {Name: "c"}}}
+
+	result := Diff(oldManifest, newManifest)
+
+	assert.Equal(t, []string{"c"}, result.Added)
+	assert.Equal(t, []string{"a"}, result.Removed)
+	assert.True(t, result.HasBreakingChanges())
+}
+
+func TestDiff_SchemaChanges(t *testing.T) {
+	oldManifest := &Manifest{Tools: []Tool{{
+		Name: "update",
+		InputSchema: &mcp.InputSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"id":     {Type: "integer"},
+				"status": {Type: "string", Enum: []string{"a", "b"}},
+				"note":   {Type: "string"},
+			},
+			Required: []string{"id"},
+		},
+	}}}
+	newManifest := &Manifest{Tools: []Tool{{
+		Name: "update",
+		InputSchema: &mcp.InputSchema{
+			Type: "object",
+			Properties: map[string]mcp.Property{
+				"id":     {Type: "string"},
+				"status": {Type: "string", Enum: []string{"a"}},
+				"tag":    {Type: "string"},
+			},
+			Required: []string{"id"},
+		},
+	}}}
+
+	result := Diff(oldManifest, newManifest)
+
+	require.Len(t, result.Changed, 1)
+	messages := make(map[string]bool)
+	for _, change := range result.Changed[0].Changes {
+		messages[change.Message] = change.Breaking
+	}
+	assert.Equal(t, true, messages[`argument "id" type changed from integer to string`])
+	assert.Equal(t, true, messages[`argument "status" no longer accepts "b"`])
+	assert.Equal(t, true, messages[`argument "note" removed`])
+	assert.Contains(t, messages, `argument "tag" added`)
+	assert.False(t, messages[`argument "tag" added`])
+	assert.True(t, result.HasBreakingChanges())
+}
+
+func TestDiff_NewlyRequired(t *testing.T) {
+	schema := func(required ...string) *mcp.InputSchema {
+		return &mcp.InputSchema{
+			Type:       "object",
+			Properties: map[string]mcp.Property{"limit": {Type: "integer"}},
+			Required:   required,
+		}
+	}
+	oldManifest := &Manifest{Tools: []Tool{{Name: "list", InputSchema: schema()}}}
+	newManifest := &Manifest{Tools: []Tool{{Name: "list", InputSchema: schema("limit")}}}
+
+	result := Diff(oldManifest, newManifest)
+
+	assert.True(t, result.HasBreakingChanges())
+	assert.False(t, Diff(newManifest, oldManifest).HasBreakingChanges())
+}
@@ -0,0 +1,104 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/pkg/mcp"
+)
+
+// FormatVersion is the version of the manifest file format
+const FormatVersion = "1"
+
+// Manifest is a static, serializable description of a generated toolset
+type Manifest struct {
+	FormatVersion string     `json:"formatVersion"`
+	Server        ServerInfo `json:"server"`
+	SpecPath      string     `json:"specPath"`
+	Tools         []Tool     `json:"tools"`
+}
+
+// ServerInfo identifies the MCP server the toolset was generated for
+type ServerInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Tool is a manifest entry describing one tool and its upstream endpoint
+type Tool struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	InputSchema *mcp.InputSchema `json:"inputSchema"`
+	Endpoint    *mcp.Operation   `json:"endpoint,omitempty"`
+}
+
+// Build creates a manifest from generated tools, ordered by tool name
+func Build(tools []mcp.Tool, cfg *config.Config) *Manifest {
+	return &Manifest{
+		FormatVersion: FormatVersion,
+		Server: ServerInfo{
+			Name:    cfg.MCP.ServerName,
+			Version: cfg.MCP.Version,
+		},
+		SpecPath: cfg.OpenAPI.SpecPath,
+		Tools:    toolEntries(tools),
+	}
+}
+
+// toolEntries converts generated tools into manifest entries ordered by tool name
+func toolEntries(tools []mcp.Tool) []Tool {
+	entries := make([]Tool, 0, len(tools))
+	for _, tool := range tools {
+		entries = append(entries, Tool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: tool.InputSchema,
+			Endpoint:    tool.Operation,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+	return entries
+}
+
+// Marshal encodes the manifest as indented JSON
+func (m *Manifest) Marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// Write writes the manifest to a file
+func (m *Manifest) Write(path string) error {
+	data, err := m.Marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// Load reads a manifest from a file
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if m.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported manifest format version: %s", m.FormatVersion)
+	}
+	return &m, nil
+}
@@ -0,0 +1,61 @@
+package manifest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testTools() []mcp.Tool {
+	return []mcp.Tool{
+		{
+			Name:        "updatepet",
+			Description: "Update a pet",
+			InputSchema: &mcp.InputSchema{
+				Type:       "object",
+				Properties: map[string]mcp.Property{"id": {Type: "integer"}},
+				Required:   []string{"id"},
+			},
+			Operation: &mcp.Operation{Method: "PUT", Path: "/pet"},
+		},
+		{
+			Name:        "addpet",
+			Description: "Add a pet",
+			InputSchema: &mcp.InputSchema{Type: "object", Properties: map[string]mcp.Property{}},
+			Operation:   &mcp.Operation{Method: "POST", Path: "/pet"},
+		},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	cfg := &config.Config{
+		OpenAPI: config.OpenAPIConfig{SpecPath: "./petstore.yaml"},
+		MCP:     config.MCPConfig{ServerName: "test", Version: "1.2.3"},
+	}
+
+	m := Build(testTools(), cfg)
+
+	assert.Equal(t, FormatVersion, m.FormatVersion)
+	assert.Equal(t, "test", m.Server.Name)
+	assert.Equal(t, "./petstore.yaml", m.SpecPath)
+	require.Len(t, m.Tools, 2)
+	assert.Equal(t, "addpet", m.Tools[0].Name)
+	assert.Equal(t, "POST", m.Tools[0].Endpoint.Method)
+	assert.Equal(t, "updatepet", m.Tools[1].Name)
+}
+
+func TestWriteAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	m := Build(testTools(), &config.Config{})
+
+	require.NoError(t, m.Write(path))
+
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, m, loaded)
+}
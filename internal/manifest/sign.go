@@ -0,0 +1,197 @@
+package manifest
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/pkg/mcp"
+)
+
+// PEM block types of keys and detached signatures
+const (
+	privateKeyType = "API-TO-MCP PRIVATE KEY"
+	publicKeyType  = "API-TO-MCP PUBLIC KEY"
+	signatureType  = "API-TO-MCP SIGNATURE"
+)
+
+// SignatureSuffix is appended to a manifest path to name its default detached signature
+const SignatureSuffix = ".sig"
+
+// WriteKeyPair generates an Ed25519 key pair, writing the private key to path and the public
+// key to path.pub
+func WriteKeyPair(path string) (string, error) {
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	keyID := KeyID(public)
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: privateKeyType, Headers: map[string]string{"Key-Id": keyID}, Bytes: private.Seed()})
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: publicKeyType, Headers: map[string]string{"Key-Id": keyID}, Bytes: public})
+
+	if err := os.WriteFile(path, privatePEM, 0600); err != nil {
+		return "", fmt.Errorf("failed to write private key: %w", err)
+	}
+	if err := os.WriteFile(path+".pub", publicPEM, 0644); err != nil {
+		return "", fmt.Errorf("failed to write public key: %w", err)
+	}
+	return keyID, nil
+}
+
+// KeyID returns a short fingerprint identifying a public key
+func KeyID(public ed25519.PublicKey) string {
+	sum := sha256.Sum256(public)
+	return hex.EncodeToString(sum[:8])
+}
+
+// LoadPrivateKey reads a private key written by WriteKeyPair
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path, privateKeyType)
+	if err != nil {
+		return nil, err
+	}
+	if len(block.Bytes) != ed25519.SeedSize {
+		return nil, fmt.Errorf("invalid private key in %s", path)
+	}
+	return ed25519.NewKeyFromSeed(block.Bytes), nil
+}
+
+// LoadPublicKey reads a public key written by WriteKeyPair
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path, publicKeyType)
+	if err != nil {
+		return nil, err
+	}
+	if len(block.Bytes) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid public key in %s", path)
+	}
+	return ed25519.PublicKey(block.Bytes), nil
+}
+
+// readPEM reads the single PEM block of the expected type from a file
+func readPEM(path, blockType string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("%s does not contain an %s", path, blockType)
+	}
+	return block, nil
+}
+
+// Sign creates a detached signature over the exact manifest bytes
+func Sign(data []byte, key ed25519.PrivateKey) []byte {
+	public := key.Public().(ed25519.PublicKey)
+	return pem.EncodeToMemory(&pem.Block{
+		Type: signatureType,
+		Headers: map[string]string{
+			"Key-Id":    KeyID(public),
+			"Signed-At": time.Now().UTC().Format(time.RFC3339),
+		},
+		Bytes: ed25519.Sign(key, data),
+	})
+}
+
+// Verify checks a detached signature over the exact manifest bytes
+func Verify(data, signature []byte, key ed25519.PublicKey) error {
+	block, _ := pem.Decode(signature)
+	if block == nil || block.Type != signatureType {
+		return fmt.Errorf("malformed manifest signature")
+	}
+	if keyID := block.Headers["Key-Id"]; keyID != "" && keyID != KeyID(key) {
+		return fmt.Errorf("manifest was signed with key %s, not %s", keyID, KeyID(key))
+	}
+	if !ed25519.Verify(key, data, block.Bytes) {
+		return fmt.Errorf("manifest signature does not verify")
+	}
+	return nil
+}
+
+// LoadVerified reads a manifest after verifying its detached signature with a public key
+func LoadVerified(path, signaturePath, publicKeyPath string) (*Manifest, error) {
+	key, err := LoadPublicKey(publicKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	signature, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest signature: %w", err)
+	}
+	if err := Verify(data, signature, key); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return Load(path)
+}
+
+// MatchTools checks that generated tools are exactly the tools described by a manifest
+func (m *Manifest) MatchTools(tools []mcp.Tool) error {
+	built := &Manifest{Tools: toolEntries(tools)}
+	expected, err := json.Marshal(m.Tools)
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest tools: %w", err)
+	}
+	actual, err := json.Marshal(built.Tools)
+	if err != nil {
+		return fmt.Errorf("failed to encode generated tools: %w", err)
+	}
+	if bytes.Equal(expected, actual) {
+		return nil
+	}
+
+	diff := Diff(m, built)
+	return fmt.Errorf("generated toolset differs from the signed manifest (added %v, removed %v, %d changed)",
+		diff.Added, diff.Removed, changedCount(m, built))
+}
+
+// changedCount counts tools present in both manifests whose entries differ in any way
+func changedCount(a, b *Manifest) int {
+	entries := make(map[string][]byte, len(a.Tools))
+	for _, tool := range a.Tools {
+		data, _ := json.Marshal(tool)
+		entries[tool.Name] = data
+	}
+	count := 0
+	for _, tool := range b.Tools {
+		previous, exists := entries[tool.Name]
+		if !exists {
+			continue
+		}
+		if data, _ := json.Marshal(tool); !bytes.Equal(previous, data) {
+			count++
+		}
+	}
+	return count
+}
+
+// VerifyToolset enforces a manifest pin: the manifest signature must verify and the generated
+// tools must match it exactly. It does nothing when no manifest is configured
+func VerifyToolset(pin config.ManifestConfig, tools []mcp.Tool) error {
+	if pin.Path == "" {
+		return nil
+	}
+	signature := pin.Signature
+	if signature == "" {
+		signature = pin.Path + SignatureSuffix
+	}
+
+	signed, err := LoadVerified(pin.Path, signature, pin.PublicKey)
+	if err != nil {
+		return err
+	}
+	return signed.MatchTools(tools)
+}
@@ -0,0 +1,72 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"api-to-mcp/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSignAndVerify(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "manifest.key")
+	keyID, err := WriteKeyPair(keyPath)
+	require.NoError(t, err)
+	assert.Len(t, keyID, 16)
+
+	private, err := LoadPrivateKey(keyPath)
+	require.NoError(t, err)
+	public, err := LoadPublicKey(keyPath + ".pub")
+	require.NoError(t, err)
+	info, err := os.Stat(keyPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	data := []byte(`{"formatVersion":"1"}`)
+	signature := Sign(data, private)
+	assert.NoError(t, Verify(data, signature, public))
+	assert.Error(t, Verify([]byte(`{"formatVersion":"2"}`), signature, public))
+
+	_, err = WriteKeyPair(filepath.Join(dir, "other.key"))
+	require.NoError(t, err)
+	other, err := LoadPublicKey(filepath.Join(dir, "other.key.pub"))
+	require.NoError(t, err)
+	assert.ErrorContains(t, Verify(data, signature, other), "signed with key")
+
+	_, err = LoadPublicKey(keyPath)
+	assert.Error(t, err)
+}
+
+func TestLoadVerified_MatchTools(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "manifest.key")
+	_, err := WriteKeyPair(keyPath)
+	require.NoError(t, err)
+	private, err := LoadPrivateKey(keyPath)
+	require.NoError(t, err)
+
+	cfg := &config.Config{}
+	path := filepath.Join(dir, "tools.json")
+	require.NoError(t, Build(testTools(), cfg).Write(path))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path+SignatureSuffix, Sign(data, private), 0644))
+
+	signed, err := LoadVerified(path, path+SignatureSuffix, keyPath+".pub")
+	require.NoError(t, err)
+	assert.NoError(t, signed.MatchTools(testTools()))
+
+	changed := testTools()
+	changed[0].Description = "Replace a pet"
+	assert.ErrorContains(t, signed.MatchTools(changed), "1 changed")
+	assert.ErrorContains(t, signed.MatchTools(changed[1:]), "removed [updatepet]")
+
+	// Tampering with the file breaks the signature
+	require.NoError(t, os.WriteFile(path, append(data, ' '), 0644))
+	_, err = LoadVerified(path, path+SignatureSuffix, keyPath+".pub")
+	assert.ErrorContains(t, err, "does not verify")
+}
@@ -0,0 +1,200 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OverflowLabel replaces tool names once the registry tracks its maximum number of tools
+const OverflowLabel = "_other"
+
+// DefaultBuckets are the latency histogram upper bounds in seconds
+var DefaultBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// toolKey identifies a tool within a namespace
+type toolKey struct {
+	Namespace string
+	Tool      string
+}
+
+// toolStats holds the counters and latency histogram of one tool
+type toolStats struct {
+	successes   int64
+	errors      int64
+	bucketCount []int64
+	sum         float64
+	max         float64
+}
+
+// Registry records tool call metrics with bounded label cardinality
+type Registry struct {
+	mu       sync.Mutex
+	maxTools int
+	buckets  []float64
+	tools    map[toolKey]*toolStats
+}
+
+// NewRegistry creates a registry tracking at most maxTools distinct tools
+func NewRegistry(maxTools int) *Registry {
+	return &Registry{
+		maxTools: maxTools,
+		buckets:  DefaultBuckets,
+		tools:    make(map[toolKey]*toolStats),
+	}
+}
+
+// ObserveCall records the outcome and latency of a tool call
+func (r *Registry) ObserveCall(namespace, tool string, duration time.Duration, err error) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := toolKey{Namespace: namespace, Tool: tool}
+	stats, exists := r.tools[key]
+	if !exists {
+		if r.maxTools > 0 && len(r.tools) >= r.maxTools {
+			key.Tool = OverflowLabel
+			stats = r.tools[key]
+		}
+		if stats == nil {
+			stats = &toolStats{bucketCount: make([]int64, len(r.buckets))}
+			r.tools[key] = stats
+		}
+	}
+
+	if err != nil {
+		stats.errors++
+	} else {
+		stats.successes++
+	}
+
+	seconds := duration.Seconds()
+	stats.sum += seconds
+	if seconds > stats.max {
+		stats.max = seconds
+	}
+	for i, bound := range r.buckets {
+		if seconds <= bound {
+			stats.bucketCount[i]++
+		}
+	}
+}
+
+// ToolSummary is the aggregated view of a tool's metrics
+type ToolSummary struct {
+	Namespace      string  `json:"namespace"`
+	Tool           string  `json:"tool"`
+	Calls          int64   `json:"calls"`
+	Errors         int64   `json:"errors"`
+	ErrorRate      float64 `json:"errorRate"`
+	MeanLatencyMs  float64 `json:"meanLatencyMs"`
+	MaxLatencyMs   float64 `json:"maxLatencyMs"`
+	P95LatencyMsLE float64 `json:"p95LatencyMsUpperBound"`
+}
+
+// Summary returns per-tool summaries ordered by namespace and tool name
+func (r *Registry) Summary() []ToolSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	summaries := make([]ToolSummary, 0, len(r.tools))
+	for key, stats := range r.tools {
+		calls := stats.successes + stats.errors
+		summary := ToolSummary{
+			Namespace:    key.Namespace,
+			Tool:         key.Tool,
+			Calls:        calls,
+			Errors:       stats.errors,
+			MaxLatencyMs: stats.max * 1000,
+		}
+		if calls > 0 {
+			summary.ErrorRate = float64(stats.errors) / float64(calls)
+			summary.MeanLatencyMs = stats.sum / float64(calls) * 1000
+			summary.P95LatencyMsLE = r.quantileUpperBound(stats, calls, 0.95) * 1000
+		}
+		summaries = append(summaries, summary)
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Namespace != summaries[j].Namespace {
+			return summaries[i].Namespace < summaries[j].Namespace
+		}
+		return summaries[i].Tool < summaries[j].Tool
+	})
+	return summaries
+}
+
+// quantileUpperBound returns the smallest bucket bound containing the quantile, or the max latency
+func (r *Registry) quantileUpperBound(stats *toolStats, calls int64, q float64) float64 {
+	target := int64(float64(calls)*q + 0.5)
+	for i, bound := range r.buckets {
+		if stats.bucketCount[i] >= target {
+			return bound
+		}
+	}
+	return stats.max
+}
+
+// WritePrometheus writes the metrics in the Prometheus text exposition format
+func (r *Registry) WritePrometheus(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	keys := make([]toolKey, 0, len(r.tools))
+	var totalSuccesses, totalErrors int64
+	for key, stats := range r.tools {
+		keys = append(keys, key)
+		totalSuccesses += stats.successes
+		totalErrors += stats.errors
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		return keys[i].Tool < keys[j].Tool
+	})
+
+	var b strings.Builder
+	b.WriteString("# HELP apitomcp_calls_total Total tool calls by outcome.\n")
+	b.WriteString("# TYPE apitomcp_calls_total counter\n")
+	fmt.Fprintf(&b, "apitomcp_calls_total{outcome=\"success\"} %d\n", totalSuccesses)
+	fmt.Fprintf(&b, "apitomcp_calls_total{outcome=\"error\"} %d\n", totalErrors)
+
+	b.WriteString("# HELP apitomcp_tool_calls_total Tool calls by namespace, tool and outcome.\n")
+	b.WriteString("# TYPE apitomcp_tool_calls_total counter\n")
+	for _, key := range keys {
+		stats := r.tools[key]
+		labels := toolLabels(key)
+		fmt.Fprintf(&b, "apitomcp_tool_calls_total{%s,outcome=\"success\"} %d\n", labels, stats.successes)
+		fmt.Fprintf(&b, "apitomcp_tool_calls_total{%s,outcome=\"error\"} %d\n", labels, stats.errors)
+	}
+
+	b.WriteString("# HELP apitomcp_tool_call_duration_seconds Tool call latency by namespace and tool.\n")
+	b.WriteString("# TYPE apitomcp_tool_call_duration_seconds histogram\n")
+	for _, key := range keys {
+		stats := r.tools[key]
+		labels := toolLabels(key)
+		for i, bound := range r.buckets {
+			fmt.Fprintf(&b, "apitomcp_tool_call_duration_seconds_bucket{%s,le=\"%g\"} %d\n", labels, bound, stats.bucketCount[i])
+		}
+		calls := stats.successes + stats.errors
+		fmt.Fprintf(&b, "apitomcp_tool_call_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, calls)
+		fmt.Fprintf(&b, "apitomcp_tool_call_duration_seconds_sum{%s} %g\n", labels, stats.sum)
+		fmt.Fprintf(&b, "apitomcp_tool_call_duration_seconds_count{%s} %d\n", labels, calls)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// toolLabels formats the namespace and tool labels
+func toolLabels(key toolKey) string {
+	return fmt.Sprintf("namespace=%q,tool=%q", key.Namespace, key.Tool)
+}
@@ -0,0 +1,63 @@
+package metrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveCall_Summary(t *testing.T) {
+	registry := NewRegistry(10)
+
+	registry.ObserveCall("default", "getpet", 20*time.Millisecond, nil)
+	registry.ObserveCall("default", "getpet", 40*time.Millisecond, errors.New("boom"))
+	registry.ObserveCall("default", "addpet", 2*time.Second, nil)
+
+	summary := registry.Summary()
+	require.Len(t, summary, 2)
+
+	assert.Equal(t, "addpet", summary[0].Tool)
+	assert.Equal(t, int64(1), summary[0].Calls)
+
+	assert.Equal(t, "getpet", summary[1].Tool)
+	assert.Equal(t, int64(2), summary[1].Calls)
+	assert.Equal(t, int64(1), summary[1].Errors)
+	assert.InDelta(t, 0.5, summary[1].ErrorRate, 0.001)
+	assert.InDelta(t, 30, summary[1].MeanLatencyMs, 0.1)
+	assert.Equal(t, 50.0, summary[1].P95LatencyMsLE)
+}
+
+func TestObserveCall_BoundedCardinality(t *testing.T) {
+	registry := NewRegistry(2)
+
+	registry.ObserveCall("default", "a", time.Millisecond, nil)
+	registry.ObserveCall("default", "b", time.Millisecond, nil)
+	registry.ObserveCall("default", "c", time.Millisecond, nil)
+	registry.ObserveCall("default", "d", time.Millisecond, nil)
+	registry.ObserveCall("default", "a", time.Millisecond, nil)
+
+	tools := make(map[string]int64)
+	for _, summary := range registry.Summary() {
+		tools[summary.Tool] = summary.Calls
+	}
+	assert.Equal(t, map[string]int64{"a": 2, "b": 1, OverflowLabel: 2}, tools)
+}
+
+func TestWritePrometheus(t *testing.T) {
+	registry := NewRegistry(10)
+	registry.ObserveCall("github", "listrepos", 300*time.Millisecond, nil)
+
+	var out strings.Builder
+	require.NoError(t, registry.WritePrometheus(&out))
+
+	text := out.String()
+	assert.Contains(t, text, `apitomcp_calls_total{outcome="success"} 1`)
+	assert.Contains(t, text, `apitomcp_tool_calls_total{namespace="github",tool="listrepos",outcome="success"} 1`)
+	assert.Contains(t, text, `apitomcp_tool_call_duration_seconds_bucket{namespace="github",tool="listrepos",le="0.25"} 0`)
+	assert.Contains(t, text, `apitomcp_tool_call_duration_seconds_bucket{namespace="github",tool="listrepos",le="0.5"} 1`)
+	assert.Contains(t, text, `apitomcp_tool_call_duration_seconds_count{namespace="github",tool="listrepos"} 1`)
+}
@@ -0,0 +1,133 @@
+package netguard
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// BlockedError is returned when an outbound destination is not permitted
+type BlockedError struct {
+	Destination string
+	Reason      string
+}
+
+func (e *BlockedError) Error() string {
+	return fmt.Sprintf("outbound request to %s blocked: %s", e.Destination, e.Reason)
+}
+
+// reservedNetworks are non-public ranges not covered by the net.IP classification helpers
+var reservedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",     // "this" network
+	"100.64.0.0/10", // carrier-grade NAT
+	"192.0.0.0/24",  // IETF protocol assignments
+	"198.18.0.0/15", // benchmarking
+	"64:ff9b::/96",  // NAT64, may embed private IPv4 addresses
+)
+
+// Policy decides which hosts and addresses outbound requests may reach
+type Policy struct {
+	hosts        []string
+	networks     []*net.IPNet
+	allowPrivate bool
+}
+
+// NewPolicy creates a policy; an empty hosts list allows any host name, and private, loopback and
+// link-local addresses are refused unless allowPrivate is set or they fall inside one of cidrs
+func NewPolicy(hosts, cidrs []string, allowPrivate bool) (*Policy, error) {
+	p := &Policy{allowPrivate: allowPrivate}
+	for _, host := range hosts {
+		p.hosts = append(p.hosts, strings.ToLower(strings.TrimSpace(host)))
+	}
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", cidr, err)
+		}
+		p.networks = append(p.networks, network)
+	}
+	return p, nil
+}
+
+// CheckHost verifies a host name against the allow-list; an IP literal also passes when it is
+// inside an allowed CIDR
+func (p *Policy) CheckHost(host string) error {
+	if p == nil || len(p.hosts) == 0 {
+		return nil
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, allowed := range p.hosts {
+		if host == allowed {
+			return nil
+		}
+		if strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:]) {
+			return nil
+		}
+	}
+	if ip := net.ParseIP(host); ip != nil && p.inAllowedNetwork(ip) {
+		return nil
+	}
+
+	return &BlockedError{Destination: host, Reason: "host is not in the outbound allow-list"}
+}
+
+// CheckIP verifies that a resolved address may be contacted
+func (p *Policy) CheckIP(ip net.IP) error {
+	if p == nil || p.allowPrivate || !IsPrivate(ip) || p.inAllowedNetwork(ip) {
+		return nil
+	}
+	return &BlockedError{Destination: ip.String(), Reason: "private, loopback and link-local addresses require an allowed CIDR"}
+}
+
+// DialControl is a net.Dialer Control function enforcing CheckIP on every connection, after DNS
+// resolution, so rebinding a permitted name to a private address is refused too
+func (p *Policy) DialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid dial address %s: %w", address, err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return &BlockedError{Destination: address, Reason: "unresolved address"}
+	}
+	return p.CheckIP(ip)
+}
+
+// inAllowedNetwork reports whether ip is inside an allowed CIDR
+func (p *Policy) inAllowedNetwork(ip net.IP) bool {
+	for _, network := range p.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPrivate reports whether ip is not a public unicast address
+func IsPrivate(ip net.IP) bool {
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, network := range reservedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// mustParseCIDRs parses constant CIDRs
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
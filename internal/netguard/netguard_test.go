@@ -0,0 +1,62 @@
+package netguard
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckHost(t *testing.T) {
+	policy, err := NewPolicy([]string{"api.example.com", "*.trusted.io"}, []string{"10.1.0.0/16"}, false)
+	require.NoError(t, err)
+
+	assert.NoError(t, policy.CheckHost("api.example.com"))
+	assert.NoError(t, policy.CheckHost("API.Example.com."))
+	assert.NoError(t, policy.CheckHost("eu.trusted.io"))
+	assert.NoError(t, policy.CheckHost("10.1.2.3"))
+
+	for _, host := range []string{"evil.com", "trusted.io", "api.example.com.evil.com", "10.2.0.1"} {
+		err := policy.CheckHost(host)
+		var blocked *BlockedError
+		assert.True(t, errors.As(err, &blocked), host)
+	}
+}
+
+func TestCheckHost_EmptyAllowListAllowsAnyName(t *testing.T) {
+	policy, err := NewPolicy(nil, nil, false)
+	require.NoError(t, err)
+	assert.NoError(t, policy.CheckHost("anything.example.org"))
+}
+
+func TestCheckIP_BlocksPrivateByDefault(t *testing.T) {
+	policy, err := NewPolicy(nil, []string{"127.0.0.0/8"}, false)
+	require.NoError(t, err)
+
+	for _, ip := range []string{"10.0.0.1", "192.168.1.1", "172.16.0.1", "169.254.169.254", "::1", "fd00::1", "0.0.0.0", "100.64.0.1"} {
+		assert.Error(t, policy.CheckIP(net.ParseIP(ip)), ip)
+	}
+	assert.NoError(t, policy.CheckIP(net.ParseIP("127.0.0.1")))
+	assert.NoError(t, policy.CheckIP(net.ParseIP("93.184.216.34")))
+}
+
+func TestCheckIP_AllowPrivate(t *testing.T) {
+	policy, err := NewPolicy(nil, nil, true)
+	require.NoError(t, err)
+	assert.NoError(t, policy.CheckIP(net.ParseIP("10.0.0.1")))
+}
+
+func TestDialControl(t *testing.T) {
+	policy, err := NewPolicy(nil, nil, false)
+	require.NoError(t, err)
+
+	assert.Error(t, policy.DialControl("tcp4", "127.0.0.1:80", nil))
+	assert.NoError(t, policy.DialControl("tcp4", "93.184.216.34:443", nil))
+}
+
+func TestNewPolicy_InvalidCIDR(t *testing.T) {
+	_, err := NewPolicy(nil, []string{"10.0.0.0/33"}, false)
+	assert.Error(t, err)
+}
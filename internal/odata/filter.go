@@ -0,0 +1,348 @@
+package odata
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// functions are the canonical functions a $filter expression may call
+var functions = map[string]bool{
+	"contains": true, "endswith": true, "startswith": true, "length": true, "indexof": true,
+	"substring": true, "matchespattern": true, "tolower": true, "toupper": true, "trim": true,
+	"concat": true, "year": true, "month": true, "day": true, "hour": true, "minute": true,
+	"second": true, "fractionalseconds": true, "totalseconds": true, "date": true, "time": true,
+	"totaloffsetminutes": true, "now": true, "mindatetime": true, "maxdatetime": true,
+	"round": true, "floor": true, "ceiling": true, "cast": true, "isof": true,
+	"geo.distance": true, "geo.intersects": true, "geo.length": true,
+	"hassubset": true, "hassubsequence": true,
+	// OData 2 and 3 names still served by many services
+	"substringof": true,
+}
+
+// Operators of $filter expressions by precedence
+var (
+	comparisonOperators     = map[string]bool{"eq": true, "ne": true, "gt": true, "ge": true, "lt": true, "le": true, "has": true}
+	additiveOperators       = map[string]bool{"add": true, "sub": true}
+	multiplicativeOperators = map[string]bool{"mul": true, "div": true, "divby": true, "mod": true}
+	keywords                = map[string]bool{"and": true, "or": true, "not": true, "in": true}
+)
+
+// guidPattern matches unquoted GUID literals, which may start with a letter
+var guidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// Token kinds of a $filter expression
+const (
+	tokenEnd = iota
+	tokenIdentifier
+	tokenLiteral
+	tokenPunctuation
+)
+
+type token struct {
+	kind     int
+	text     string
+	position int
+}
+
+// ValidateFilter checks that a $filter value is a well-formed OData expression: balanced
+// parentheses, terminated strings, operands between operators and known function names. It
+// does not check property names, which only the service knows
+func ValidateFilter(filter string) error {
+	tokens, err := tokenize(filter)
+	if err != nil {
+		return err
+	}
+	p := &filterParser{tokens: tokens}
+	if p.peek().kind == tokenEnd {
+		return fmt.Errorf("invalid $filter: expression is empty")
+	}
+	if err := p.expression(); err != nil {
+		return err
+	}
+	if t := p.peek(); t.kind != tokenEnd {
+		return p.errorf(t, "unexpected %q", t.text)
+	}
+	return nil
+}
+
+// tokenize splits a $filter expression into identifiers, literals and punctuation
+func tokenize(filter string) ([]token, error) {
+	tokens := make([]token, 0)
+	for i := 0; i < len(filter); {
+		c := filter[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '\'':
+			end, err := stringEnd(filter, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{tokenLiteral, filter[i:end], i})
+			i = end
+		case strings.IndexByte("(),/:-", c) >= 0:
+			tokens = append(tokens, token{tokenPunctuation, string(c), i})
+			i++
+		case guidPattern.MatchString(filter[i:]):
+			length := len(guidPattern.FindString(filter[i:]))
+			tokens = append(tokens, token{tokenLiteral, filter[i : i+length], i})
+			i += length
+		case c >= '0' && c <= '9':
+			// Numbers, dates, times and durations
+			start := i
+			for i < len(filter) && (isWordByte(filter[i]) || strings.IndexByte(".:-+", filter[i]) >= 0) {
+				i++
+			}
+			tokens = append(tokens, token{tokenLiteral, filter[start:i], start})
+		case isWordByte(c) || c == '$' || c == '@':
+			start := i
+			i++
+			for i < len(filter) && (isWordByte(filter[i]) || filter[i] == '.') {
+				i++
+			}
+			if i < len(filter) && filter[i] == '\'' {
+				// Typed literals such as duration'P1D' or geography'POINT(0 0)'
+				end, err := stringEnd(filter, i)
+				if err != nil {
+					return nil, err
+				}
+				tokens = append(tokens, token{tokenLiteral, filter[start:end], start})
+				i = end
+				continue
+			}
+			tokens = append(tokens, token{tokenIdentifier, filter[start:i], start})
+		default:
+			return nil, fmt.Errorf("invalid $filter at position %d: unexpected character %q", i+1, c)
+		}
+	}
+	return append(tokens, token{kind: tokenEnd, position: len(filter)}), nil
+}
+
+// stringEnd returns the index after the quoted string starting at start; quotes inside the
+// string are doubled
+func stringEnd(filter string, start int) (int, error) {
+	for i := start + 1; i < len(filter); i++ {
+		if filter[i] != '\'' {
+			continue
+		}
+		if i+1 < len(filter) && filter[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i + 1, nil
+	}
+	return 0, fmt.Errorf("invalid $filter at position %d: unterminated string", start+1)
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// filterParser is a recursive descent parser over $filter tokens
+type filterParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *filterParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+// accept consumes the next token when it is the given punctuation or keyword
+func (p *filterParser) accept(text string) bool {
+	t := p.peek()
+	if (t.kind == tokenPunctuation || t.kind == tokenIdentifier) && t.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) expect(text string) error {
+	if !p.accept(text) {
+		t := p.peek()
+		if t.kind == tokenEnd {
+			return p.errorf(t, "expected %q before end of expression", text)
+		}
+		return p.errorf(t, "expected %q, found %q", text, t.text)
+	}
+	return nil
+}
+
+func (p *filterParser) errorf(t token, format string, args ...interface{}) error {
+	return fmt.Errorf("invalid $filter at position %d: %s", t.position+1, fmt.Sprintf(format, args...))
+}
+
+// expression parses: and-expression ("or" and-expression)*
+func (p *filterParser) expression() error {
+	if err := p.and(); err != nil {
+		return err
+	}
+	for p.accept("or") {
+		if err := p.and(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// and parses: not-expression ("and" not-expression)*
+func (p *filterParser) and() error {
+	if err := p.not(); err != nil {
+		return err
+	}
+	for p.accept("and") {
+		if err := p.not(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// not parses: "not" not | comparison
+func (p *filterParser) not() error {
+	if p.accept("not") {
+		return p.not()
+	}
+	return p.comparison()
+}
+
+// comparison parses: additive [(comparison-operator additive) | ("in" list)]
+func (p *filterParser) comparison() error {
+	if err := p.additive(); err != nil {
+		return err
+	}
+	t := p.peek()
+	if t.kind == tokenIdentifier && comparisonOperators[t.text] {
+		p.next()
+		return p.additive()
+	}
+	if p.accept("in") {
+		if err := p.expect("("); err != nil {
+			return err
+		}
+		return p.list()
+	}
+	return nil
+}
+
+// additive parses: multiplicative (("add" | "sub") multiplicative)*
+func (p *filterParser) additive() error {
+	if err := p.multiplicative(); err != nil {
+		return err
+	}
+	for t := p.peek(); t.kind == tokenIdentifier && additiveOperators[t.text]; t = p.peek() {
+		p.next()
+		if err := p.multiplicative(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// multiplicative parses: primary (("mul" | "div" | "divby" | "mod") primary)*
+func (p *filterParser) multiplicative() error {
+	if err := p.primary(); err != nil {
+		return err
+	}
+	for t := p.peek(); t.kind == tokenIdentifier && multiplicativeOperators[t.text]; t = p.peek() {
+		p.next()
+		if err := p.primary(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// primary parses a parenthesized expression, negation, literal, function call or member path
+func (p *filterParser) primary() error {
+	t := p.next()
+	switch t.kind {
+	case tokenEnd:
+		return p.errorf(t, "expected an operand before end of expression")
+	case tokenLiteral:
+		return nil
+	case tokenPunctuation:
+		switch t.text {
+		case "(":
+			if err := p.expression(); err != nil {
+				return err
+			}
+			return p.expect(")")
+		case "-":
+			return p.primary()
+		}
+		return p.errorf(t, "expected an operand, found %q", t.text)
+	}
+
+	if keywords[t.text] || comparisonOperators[t.text] || additiveOperators[t.text] || multiplicativeOperators[t.text] {
+		return p.errorf(t, "expected an operand, found operator %q", t.text)
+	}
+	if p.accept("(") {
+		if !functions[strings.ToLower(t.text)] {
+			return p.errorf(t, "unknown function %q", t.text)
+		}
+		if p.accept(")") {
+			return nil
+		}
+		return p.list()
+	}
+	return p.path()
+}
+
+// list parses the comma-separated arguments after an opening parenthesis, through the closing one
+func (p *filterParser) list() error {
+	for {
+		if err := p.expression(); err != nil {
+			return err
+		}
+		if p.accept(")") {
+			return nil
+		}
+		if err := p.expect(","); err != nil {
+			return err
+		}
+	}
+}
+
+// path parses the rest of a member path, including any and all lambdas
+func (p *filterParser) path() error {
+	for p.accept("/") {
+		t := p.next()
+		if t.kind != tokenIdentifier {
+			return p.errorf(t, "expected a property name after '/'")
+		}
+		if t.text != "any" && t.text != "all" {
+			continue
+		}
+		if err := p.expect("("); err != nil {
+			return err
+		}
+		if p.accept(")") {
+			if t.text == "all" {
+				return p.errorf(t, "all requires a lambda expression")
+			}
+			return nil
+		}
+		if variable := p.next(); variable.kind != tokenIdentifier {
+			return p.errorf(variable, "expected a lambda variable")
+		}
+		if err := p.expect(":"); err != nil {
+			return err
+		}
+		if err := p.expression(); err != nil {
+			return err
+		}
+		return p.expect(")")
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package odata
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateFilter_Valid(t *testing.T) {
+	filters := []string{
+		"Name eq 'Milk'",
+		"Price gt 20 and Price le 100",
+		"not (Rating lt 3) or Discontinued eq true",
+		"contains(tolower(Name),'red') and startswith(Category/Name,'Bev')",
+		"Name eq 'O''Neil'",
+		"Price sub 5 gt Cost mul 2",
+		"-Balance gt 0",
+		"Status in ('Open','Pending')",
+		"CreatedAt ge 2024-01-01T00:00:00Z and Birthday eq 1990-05-17",
+		"Id eq 01234567-89ab-cdef-0123-456789abcdef",
+		"Id eq ab234567-89ab-cdef-0123-456789abcdef",
+		"Tags/any(t: t eq 'urgent')",
+		"Orders/all(o: o/Total gt 10.5)",
+		"Friends/any()",
+		"Duration lt duration'PT1H'",
+		"geo.distance(Location, geography'POINT(-122 47)') lt 10",
+		"Style has Namespace.Color'Yellow'",
+		"substringof('Alfreds', CompanyName) eq true",
+		"year(now()) eq 2024",
+		"Address/City eq null",
+	}
+	for _, filter := range filters {
+		assert.NoError(t, ValidateFilter(filter), filter)
+	}
+}
+
+func TestValidateFilter_Invalid(t *testing.T) {
+	tests := []struct {
+		filter string
+		err    string
+	}{
+		{"", "expression is empty"},
+		{"Name eq 'Milk", "position 9: unterminated string"},
+		{"Name eq", "position 8: expected an operand before end of expression"},
+		{"Name = 'Milk'", "position 6: unexpected character '='"},
+		{"(Price gt 20", `expected ")" before end of expression`},
+		{"Price gt 20)", `position 12: unexpected ")"`},
+		{"Price gt and 20", `position 10: expected an operand, found operator "and"`},
+		{"Name eq 'a' Price gt 1", `position 13: unexpected "Price"`},
+		{"lowercase(Name) eq 'x'", `position 1: unknown function "lowercase"`},
+		{"Status in 'Open'", `expected "(", found "'Open'"`},
+		{"Tags/any(t t eq 'x')", `expected ":", found "t"`},
+		{"Tags/all()", "all requires a lambda expression"},
+		{"Price && 1", "unexpected character '&'"},
+	}
+	for _, tt := range tests {
+		err := ValidateFilter(tt.filter)
+		if assert.Error(t, err, tt.filter) {
+			assert.Contains(t, err.Error(), tt.err, tt.filter)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package odata
+
+import (
+	"fmt"
+	neturl "net/url"
+	"strings"
+
+	"api-to-mcp/pkg/mcp"
+)
+
+// Names of the OData system query options the generator recognizes
+const (
+	Filter  = "$filter"
+	Select  = "$select"
+	Expand  = "$expand"
+	OrderBy = "$orderby"
+	Top     = "$top"
+	Skip    = "$skip"
+	Count   = "$count"
+	Search  = "$search"
+)
+
+// Keys carrying the link to the next page of a collection: OData 4 and the OData 3 JSON
+// light format
+var nextLinkKeys = []string{"@odata.nextLink", "odata.nextLink"}
+
+// options describes each system query option
+var options = map[string]mcp.Property{
+	Filter: {
+		Type: "string",
+		Description: "OData filter expression, e.g. Price gt 20 and contains(Name,'red'). Operators: eq, ne, gt, ge, lt, le, " +
+			"has, in, and, or, not, add, sub, mul, div, mod; strings use single quotes, doubled to escape",
+	},
+	Select:  {Type: "string", Description: "Comma-separated properties to return, e.g. Id,Name"},
+	Expand:  {Type: "string", Description: "Comma-separated navigation properties to include inline, e.g. Orders,Customer"},
+	OrderBy: {Type: "string", Description: "Comma-separated properties to sort by, each optionally followed by asc or desc, e.g. Name desc"},
+	Top:     {Type: "integer", Description: "Maximum number of items to return"},
+	Skip:    {Type: "integer", Description: "Number of items to skip before returning results"},
+	Count:   {Type: "boolean", Description: "Include the total number of matching items as @odata.count"},
+	Search:  {Type: "string", Description: "Free-text search expression"},
+}
+
+// IsSystemOption reports whether a query parameter is an OData system query option
+func IsSystemOption(name string) bool {
+	_, ok := options[name]
+	return ok
+}
+
+// Describe documents a system query option, keeping the specification's description when it
+// has one; $top and $skip become non-negative integers and $count a boolean
+func Describe(name string, property mcp.Property) mcp.Property {
+	option, ok := options[name]
+	if !ok {
+		return property
+	}
+	if property.Description == "" {
+		property.Description = option.Description
+	}
+	switch name {
+	case Top, Skip:
+		property.Type = "integer"
+		property.Format = ""
+		if property.Minimum == nil {
+			zero := 0.0
+			property.Minimum = &zero
+		}
+	case Count:
+		property.Type = "boolean"
+	}
+	return property
+}
+
+// NextLink returns the link to the next page of a collection response
+func NextLink(response interface{}) (string, bool) {
+	body, ok := response.(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	for _, key := range nextLinkKeys {
+		if link, ok := body[key].(string); ok && link != "" {
+			return link, true
+		}
+	}
+	return "", false
+}
+
+// MergePage appends the items of a page to a collection response and replaces its next link
+// with the page's
+func MergePage(response, page interface{}) (interface{}, error) {
+	body, ok := response.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("OData response is not an object")
+	}
+	next, ok := page.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("OData page is not an object")
+	}
+	items, ok := body["value"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("OData response has no value array")
+	}
+	more, ok := next["value"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("OData page has no value array")
+	}
+
+	merged := make(map[string]interface{}, len(body))
+	for key, value := range body {
+		merged[key] = value
+	}
+	merged["value"] = append(items[:len(items):len(items)], more...)
+	for _, key := range nextLinkKeys {
+		delete(merged, key)
+		if link, ok := next[key]; ok {
+			merged[key] = link
+		}
+	}
+	return merged, nil
+}
+
+// ResolveNextLink resolves a next link against the URL of the request that returned it and
+// returns it relative to baseURL; links leaving the base URL are refused, so credentials are
+// never sent to another origin
+func ResolveNextLink(baseURL, requestPath, link string) (string, error) {
+	base, err := neturl.Parse(strings.TrimSuffix(baseURL, "/") + requestPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid request URL: %w", err)
+	}
+	ref, err := neturl.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("invalid next link %q: %w", link, err)
+	}
+	resolved := base.ResolveReference(ref).String()
+
+	prefix := strings.TrimSuffix(baseURL, "/")
+	if !strings.HasPrefix(resolved, prefix+"/") && !strings.HasPrefix(resolved, prefix+"?") {
+		return "", fmt.Errorf("next link %s is outside the base URL", link)
+	}
+	return strings.TrimPrefix(resolved, prefix), nil
+}
@@ -0,0 +1,83 @@
+package odata
+
+import (
+	"testing"
+
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribe(t *testing.T) {
+	filter := Describe(Filter, mcp.Property{Type: "string"})
+	assert.Contains(t, filter.Description, "OData filter expression")
+
+	kept := Describe(Select, mcp.Property{Type: "string", Description: "Columns"})
+	assert.Equal(t, "Columns", kept.Description)
+
+	top := Describe(Top, mcp.Property{Type: "number", Format: "double"})
+	assert.Equal(t, "integer", top.Type)
+	assert.Empty(t, top.Format)
+	require.NotNil(t, top.Minimum)
+	assert.Equal(t, 0.0, *top.Minimum)
+
+	assert.Equal(t, "boolean", Describe(Count, mcp.Property{Type: "string"}).Type)
+	assert.Equal(t, mcp.Property{Type: "string"}, Describe("limit", mcp.Property{Type: "string"}))
+	assert.True(t, IsSystemOption("$orderby"))
+	assert.False(t, IsSystemOption("orderby"))
+}
+
+func TestNextLinkAndMergePage(t *testing.T) {
+	first := map[string]interface{}{
+		"@odata.context":  "$metadata#People",
+		"value":           []interface{}{"a"},
+		"@odata.nextLink": "People?$skiptoken=1",
+	}
+	link, ok := NextLink(first)
+	assert.True(t, ok)
+	assert.Equal(t, "People?$skiptoken=1", link)
+
+	merged, err := MergePage(first, map[string]interface{}{"value": []interface{}{"b", "c"}})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"@odata.context": "$metadata#People",
+		"value":          []interface{}{"a", "b", "c"},
+	}, merged)
+	_, ok = NextLink(merged)
+	assert.False(t, ok)
+	// The first page is left untouched
+	assert.Len(t, first["value"], 1)
+
+	link, ok = NextLink(map[string]interface{}{"odata.nextLink": "People?$skip=10"})
+	assert.True(t, ok)
+	assert.Equal(t, "People?$skip=10", link)
+
+	_, err = MergePage(first, map[string]interface{}{"error": "x"})
+	assert.ErrorContains(t, err, "OData page has no value array")
+}
+
+func TestResolveNextLink(t *testing.T) {
+	base := "https://odata.example.com/v4"
+	tests := []struct {
+		link string
+		want string
+		err  string
+	}{
+		{"https://odata.example.com/v4/People?$skiptoken=1", "/People?$skiptoken=1", ""},
+		{"People?$skiptoken=2", "/People?$skiptoken=2", ""},
+		{"/v4/People?$skip=20", "/People?$skip=20", ""},
+		{"https://attacker.example.com/v4/People", "", "outside the base URL"},
+		{"https://odata.example.com/v4evil/People", "", "outside the base URL"},
+		{"/other/People", "", "outside the base URL"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveNextLink(base, "/People", tt.link)
+		if tt.err != "" {
+			assert.ErrorContains(t, err, tt.err, tt.link)
+			continue
+		}
+		require.NoError(t, err, tt.link)
+		assert.Equal(t, tt.want, got, tt.link)
+	}
+}
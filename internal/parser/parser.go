@@ -33,20 +33,22 @@ func (p *OpenAPIParser) ParseSpec() (*openapi.ParsedSpec, error) {
 		return nil, fmt.Errorf("specification file not found: %s", p.specPath)
 	}
 
-	// Load the OpenAPI document
-	loader := openapi3.NewLoader()
-	doc, err := loader.LoadFromFile(p.specPath)
+	data, err := os.ReadFile(p.specPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
+		return nil, fmt.Errorf("failed to read specification: %w", err)
 	}
 
-	// Validate the document
-	if err := doc.Validate(loader.Context); err != nil {
-		return nil, fmt.Errorf("invalid OpenAPI specification: %w", err)
+	// Postman collections are converted directly; everything else is loaded as OpenAPI
+	var parsedSpec *openapi.ParsedSpec
+	if IsPostmanCollection(data) {
+		p.logger.Info("Detected Postman collection")
+		parsedSpec, err = p.convertPostmanCollection(data)
+	} else {
+		parsedSpec, err = p.loadOpenAPI()
+	}
+	if err != nil {
+		return nil, err
 	}
-
-	// Convert to our internal representation
-	parsedSpec := p.convertToParsedSpec(doc)
 
 	// Validate the parsed specification
 	validator := NewValidator(p.logger)
@@ -64,6 +66,23 @@ func (p *OpenAPIParser) ParseSpec() (*openapi.ParsedSpec, error) {
 	return parsedSpec, nil
 }
 
+// loadOpenAPI loads and validates an OpenAPI document and converts it
+func (p *OpenAPIParser) loadOpenAPI() (*openapi.ParsedSpec, error) {
+	loader := openapi3.NewLoader()
+	doc, err := loader.LoadFromFile(p.specPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load OpenAPI spec: %w", err)
+	}
+
+	// Validate the document
+	if err := doc.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI specification: %w", err)
+	}
+
+	// Convert to our internal representation
+	return p.convertToParsedSpec(doc), nil
+}
+
 // convertToParsedSpec converts OpenAPI3 document to our internal representation
 func (p *OpenAPIParser) convertToParsedSpec(doc *openapi3.T) *openapi.ParsedSpec {
 	spec := &openapi.ParsedSpec{
@@ -152,11 +171,12 @@ func (p *OpenAPIParser) convertParameter(param *openapi3.ParameterRef) openapi.P
 	}
 
 	return openapi.Parameter{
-		Name:        param.Value.Name,
-		In:          param.Value.In,
-		Description: param.Value.Description,
-		Required:    param.Value.Required,
-		Schema:      p.convertSchema(param.Value.Schema),
+		Name:          param.Value.Name,
+		In:            param.Value.In,
+		Description:   param.Value.Description,
+		Required:      param.Value.Required,
+		Schema:        p.convertSchema(param.Value.Schema),
+		AllowReserved: param.Value.AllowReserved,
 	}
 }
 
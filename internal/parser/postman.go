@@ -0,0 +1,540 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"api-to-mcp/pkg/openapi"
+)
+
+// postmanSchemaPrefix identifies Postman collection documents by their info.schema URL
+const postmanSchemaPrefix = "https://schema.getpostman.com/json/collection/"
+
+// postmanVariablePattern matches {{variable}} references
+var postmanVariablePattern = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// nonIdentifierPattern matches runs of characters not allowed in generated operation IDs
+var nonIdentifierPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// postmanManagedHeaders are set by the HTTP client or auth configuration, not by tool arguments
+var postmanManagedHeaders = map[string]bool{
+	"content-type":  true,
+	"accept":        true,
+	"authorization": true,
+	"user-agent":    true,
+}
+
+// postmanCollection is the subset of a Postman v2.0/v2.1 collection that describes requests
+type postmanCollection struct {
+	Info struct {
+		Name        string          `json:"name"`
+		Description json.RawMessage `json:"description"`
+		Schema      string          `json:"schema"`
+		Version     json.RawMessage `json:"version"`
+	} `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanKeyValue `json:"variable"`
+	Auth     *postmanAuth      `json:"auth"`
+}
+
+// postmanItem is a folder (with items) or a request
+type postmanItem struct {
+	Name        string            `json:"name"`
+	Description json.RawMessage   `json:"description"`
+	Item        []postmanItem     `json:"item"`
+	Request     *postmanRequest   `json:"request"`
+	Response    []postmanResponse `json:"response"`
+	Auth        *postmanAuth      `json:"auth"`
+}
+
+type postmanRequest struct {
+	Method      string            `json:"method"`
+	Header      []postmanKeyValue `json:"header"`
+	URL         postmanURL        `json:"url"`
+	Body        *postmanBody      `json:"body"`
+	Description json.RawMessage   `json:"description"`
+	Auth        *postmanAuth      `json:"auth"`
+}
+
+// postmanURL is either a raw string or a structured URL
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     postmanSegments   `json:"host"`
+	Path     postmanSegments   `json:"path"`
+	Query    []postmanKeyValue `json:"query"`
+	Variable []postmanKeyValue `json:"variable"`
+}
+
+// UnmarshalJSON accepts both the string and the object form of a URL
+func (u *postmanURL) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		u.Raw = raw
+		return nil
+	}
+	type plain postmanURL
+	return json.Unmarshal(data, (*plain)(u))
+}
+
+// postmanSegments is a host or path given as a dotted/slashed string or as a list of segments
+type postmanSegments []string
+
+// UnmarshalJSON accepts both forms of host and path
+func (s *postmanSegments) UnmarshalJSON(data []byte) error {
+	var joined string
+	if err := json.Unmarshal(data, &joined); err == nil {
+		*s = strings.FieldsFunc(joined, func(r rune) bool { return r == '/' })
+		return nil
+	}
+	var segments []interface{}
+	if err := json.Unmarshal(data, &segments); err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		switch v := segment.(type) {
+		case string:
+			*s = append(*s, v)
+		case map[string]interface{}:
+			// v2.1 allows path segments as {"type": "string", "value": "..."}
+			if value, ok := v["value"].(string); ok {
+				*s = append(*s, value)
+			}
+		}
+	}
+	return nil
+}
+
+type postmanKeyValue struct {
+	Key         string          `json:"key"`
+	Value       interface{}     `json:"value"`
+	Description json.RawMessage `json:"description"`
+	Disabled    bool            `json:"disabled"`
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw"`
+	URLEncoded []postmanKeyValue `json:"urlencoded"`
+	FormData   []postmanKeyValue `json:"formdata"`
+	Options    struct {
+		Raw struct {
+			Language string `json:"language"`
+		} `json:"raw"`
+	} `json:"options"`
+}
+
+type postmanResponse struct {
+	Name   string            `json:"name"`
+	Code   int               `json:"code"`
+	Header []postmanKeyValue `json:"header"`
+	Body   string            `json:"body"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	APIKey []postmanKeyValue `json:"apikey"`
+}
+
+// IsPostmanCollection reports whether a document is a Postman collection
+func IsPostmanCollection(data []byte) bool {
+	var probe struct {
+		Info struct {
+			Schema string `json:"schema"`
+		} `json:"info"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return false
+	}
+	return strings.HasPrefix(probe.Info.Schema, postmanSchemaPrefix)
+}
+
+// convertPostmanCollection converts a Postman v2.0/v2.1 collection into the internal representation
+func (p *OpenAPIParser) convertPostmanCollection(data []byte) (*openapi.ParsedSpec, error) {
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("failed to decode Postman collection: %w", err)
+	}
+	schema := strings.TrimPrefix(collection.Info.Schema, postmanSchemaPrefix)
+	if !strings.HasPrefix(schema, "v2.0") && !strings.HasPrefix(schema, "v2.1") {
+		return nil, fmt.Errorf("unsupported Postman collection schema: %s", collection.Info.Schema)
+	}
+
+	c := &postmanConverter{
+		variables: make(map[string]string),
+		names:     make(map[string]int),
+		spec: &openapi.ParsedSpec{
+			Info: openapi.Info{
+				Title:       collection.Info.Name,
+				Version:     postmanVersion(collection.Info.Version),
+				Description: postmanText(collection.Info.Description),
+			},
+			Servers:    make([]openapi.Server, 0),
+			Endpoints:  make([]openapi.Endpoint, 0),
+			Components: make(map[string]openapi.Component),
+		},
+	}
+	for _, variable := range collection.Variable {
+		c.variables[variable.Key] = fmt.Sprint(valueOrEmpty(variable.Value))
+	}
+
+	c.convertItems(collection.Item, collection.Auth)
+	c.spec.Auth = c.auth
+	return c.spec, nil
+}
+
+// postmanConverter accumulates endpoints while walking a collection's folders
+type postmanConverter struct {
+	spec      *openapi.ParsedSpec
+	variables map[string]string
+	names     map[string]int
+	auth      *openapi.AuthScheme
+}
+
+// convertItems converts requests, descending into folders; auth is inherited from parents
+func (c *postmanConverter) convertItems(items []postmanItem, inherited *postmanAuth) {
+	for _, item := range items {
+		auth := inherited
+		if item.Auth != nil {
+			auth = item.Auth
+		}
+		if item.Request == nil {
+			c.convertItems(item.Item, auth)
+			continue
+		}
+		if item.Request.Auth != nil {
+			auth = item.Request.Auth
+		}
+		c.convertRequest(item, auth)
+	}
+}
+
+// convertRequest converts a single request into an endpoint
+func (c *postmanConverter) convertRequest(item postmanItem, auth *postmanAuth) {
+	request := item.Request
+	method := strings.ToUpper(request.Method)
+	if method == "" {
+		method = "GET"
+	}
+
+	url := request.URL
+	if len(url.Path) == 0 && len(url.Host) == 0 && url.Raw != "" {
+		url = parseRawPostmanURL(url.Raw)
+	}
+	c.recordServer(url)
+
+	endpoint := openapi.Endpoint{
+		Method:      method,
+		OperationID: c.operationID(item.Name, method),
+		Summary:     item.Name,
+		Description: postmanText(request.Description),
+		Parameters:  make([]openapi.Parameter, 0),
+		Responses:   make(map[string]openapi.Response),
+	}
+	if endpoint.Description == "" {
+		endpoint.Description = postmanText(item.Description)
+	}
+
+	// Path segments: :name and unresolved {{name}} become path parameters
+	pathVariables := make(map[string]postmanKeyValue, len(url.Variable))
+	for _, variable := range url.Variable {
+		pathVariables[variable.Key] = variable
+	}
+	segments := make([]string, 0, len(url.Path))
+	for _, segment := range url.Path {
+		name := ""
+		if strings.HasPrefix(segment, ":") {
+			name = segment[1:]
+		} else if match := postmanVariablePattern.FindStringSubmatch(segment); match != nil && match[0] == segment {
+			if _, known := c.variables[match[1]]; !known {
+				name = match[1]
+			}
+		}
+		if name == "" {
+			segments = append(segments, c.substitute(segment))
+			continue
+		}
+
+		segments = append(segments, "{"+name+"}")
+		variable := pathVariables[name]
+		endpoint.Parameters = append(endpoint.Parameters, openapi.Parameter{
+			Name:        name,
+			In:          "path",
+			Description: postmanText(variable.Description),
+			Required:    true,
+			Schema:      openapi.Schema{Type: inferPostmanType(variable.Value)},
+		})
+	}
+	endpoint.Path = "/" + strings.Join(segments, "/")
+
+	for _, query := range url.Query {
+		if query.Key == "" {
+			continue
+		}
+		endpoint.Parameters = append(endpoint.Parameters, openapi.Parameter{
+			Name:        query.Key,
+			In:          "query",
+			Description: postmanText(query.Description),
+			Schema:      openapi.Schema{Type: inferPostmanType(query.Value)},
+		})
+	}
+
+	for _, header := range request.Header {
+		if header.Key == "" || header.Disabled || postmanManagedHeaders[strings.ToLower(header.Key)] {
+			continue
+		}
+		endpoint.Parameters = append(endpoint.Parameters, openapi.Parameter{
+			Name:        header.Key,
+			In:          "header",
+			Description: postmanText(header.Description),
+			Schema:      openapi.Schema{Type: "string"},
+		})
+	}
+
+	if request.Body != nil {
+		endpoint.RequestBody = c.convertBody(request.Body)
+	}
+
+	for _, response := range item.Response {
+		code := strconv.Itoa(response.Code)
+		if response.Code == 0 {
+			code = "default"
+		}
+		converted := openapi.Response{Description: response.Name, Content: make(map[string]openapi.MediaType)}
+		var sample interface{}
+		if err := json.Unmarshal([]byte(response.Body), &sample); err == nil {
+			converted.Content["application/json"] = openapi.MediaType{Schema: inferSchema(sample)}
+		}
+		endpoint.Responses[code] = converted
+	}
+	if len(endpoint.Responses) == 0 {
+		endpoint.Responses["200"] = openapi.Response{Description: "Successful response"}
+	}
+
+	c.recordAuth(auth)
+	c.spec.Endpoints = append(c.spec.Endpoints, endpoint)
+}
+
+// convertBody converts a request body; JSON samples become schemas
+func (c *postmanConverter) convertBody(body *postmanBody) *openapi.RequestBody {
+	requestBody := &openapi.RequestBody{Content: make(map[string]openapi.MediaType)}
+
+	switch body.Mode {
+	case "raw":
+		var sample interface{}
+		if err := json.Unmarshal([]byte(c.substituteJSON(body.Raw)), &sample); err == nil {
+			requestBody.Content["application/json"] = openapi.MediaType{Schema: inferSchema(sample)}
+		} else {
+			contentType := "text/plain"
+			if body.Options.Raw.Language == "xml" {
+				contentType = "application/xml"
+			}
+			requestBody.Content[contentType] = openapi.MediaType{Schema: openapi.Schema{Type: "string"}}
+		}
+	case "urlencoded", "formdata":
+		schema := openapi.Schema{Type: "object", Properties: make(map[string]openapi.Schema)}
+		fields := body.URLEncoded
+		contentType := "application/x-www-form-urlencoded"
+		if body.Mode == "formdata" {
+			fields = body.FormData
+			contentType = "multipart/form-data"
+		}
+		for _, field := range fields {
+			if field.Key == "" || field.Disabled {
+				continue
+			}
+			schema.Properties[field.Key] = openapi.Schema{Type: "string", Description: postmanText(field.Description)}
+		}
+		requestBody.Content[contentType] = openapi.MediaType{Schema: schema}
+	default:
+		return nil
+	}
+	return requestBody
+}
+
+// recordServer records the first request's scheme and host as the spec's server
+func (c *postmanConverter) recordServer(url postmanURL) {
+	if len(c.spec.Servers) > 0 || len(url.Host) == 0 {
+		return
+	}
+	host := c.substitute(strings.Join(url.Host, "."))
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	c.spec.Servers = append(c.spec.Servers, openapi.Server{URL: host})
+}
+
+// recordAuth records the first authentication scheme used by a request
+func (c *postmanConverter) recordAuth(auth *postmanAuth) {
+	if c.auth != nil || auth == nil {
+		return
+	}
+	switch auth.Type {
+	case "bearer", "oauth2":
+		c.auth = &openapi.AuthScheme{Type: "bearer"}
+	case "basic":
+		c.auth = &openapi.AuthScheme{Type: "basic"}
+	case "apikey":
+		scheme := &openapi.AuthScheme{Type: "apikey", In: "header"}
+		for _, setting := range auth.APIKey {
+			switch setting.Key {
+			case "key":
+				scheme.Name = fmt.Sprint(valueOrEmpty(setting.Value))
+			case "in":
+				scheme.In = fmt.Sprint(valueOrEmpty(setting.Value))
+			}
+		}
+		c.auth = scheme
+	}
+}
+
+// operationID derives a unique snake_case operation ID from a request name
+func (c *postmanConverter) operationID(name, method string) string {
+	id := strings.Trim(nonIdentifierPattern.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if id == "" {
+		id = strings.ToLower(method)
+	}
+	c.names[id]++
+	if count := c.names[id]; count > 1 {
+		id = fmt.Sprintf("%s_%d", id, count)
+	}
+	return id
+}
+
+// substitute replaces known collection variables, leaving unknown references intact
+func (c *postmanConverter) substitute(s string) string {
+	return postmanVariablePattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := postmanVariablePattern.FindStringSubmatch(ref)[1]
+		if value, known := c.variables[name]; known {
+			return value
+		}
+		return ref
+	})
+}
+
+// substituteJSON replaces variables in a raw JSON body; unknown references, which are often
+// unquoted placeholders for numbers, become null so the sample still parses
+func (c *postmanConverter) substituteJSON(raw string) string {
+	return postmanVariablePattern.ReplaceAllStringFunc(raw, func(ref string) string {
+		name := postmanVariablePattern.FindStringSubmatch(ref)[1]
+		if value, known := c.variables[name]; known {
+			return value
+		}
+		return "null"
+	})
+}
+
+// parseRawPostmanURL splits a raw URL such as {{baseUrl}}/users/:id?active=true
+func parseRawPostmanURL(raw string) postmanURL {
+	var url postmanURL
+	rest := raw
+	if i := strings.Index(rest, "?"); i >= 0 {
+		for _, pair := range strings.Split(rest[i+1:], "&") {
+			key, value, _ := strings.Cut(pair, "=")
+			url.Query = append(url.Query, postmanKeyValue{Key: key, Value: value})
+		}
+		rest = rest[:i]
+	}
+	scheme := ""
+	if i := strings.Index(rest, "://"); i >= 0 {
+		scheme, rest = rest[:i+3], rest[i+3:]
+	}
+	parts := strings.Split(rest, "/")
+	url.Host = postmanSegments{scheme + parts[0]}
+	for _, part := range parts[1:] {
+		if part != "" {
+			url.Path = append(url.Path, part)
+		}
+	}
+	return url
+}
+
+// inferSchema derives a schema from a JSON sample
+func inferSchema(sample interface{}) openapi.Schema {
+	switch v := sample.(type) {
+	case map[string]interface{}:
+		schema := openapi.Schema{Type: "object", Properties: make(map[string]openapi.Schema, len(v))}
+		for key, value := range v {
+			schema.Properties[key] = inferSchema(value)
+		}
+		return schema
+	case []interface{}:
+		items := openapi.Schema{Type: "string"}
+		if len(v) > 0 {
+			items = inferSchema(v[0])
+		}
+		return openapi.Schema{Type: "array", Items: &items}
+	case float64:
+		if v == float64(int64(v)) {
+			return openapi.Schema{Type: "integer"}
+		}
+		return openapi.Schema{Type: "number"}
+	case bool:
+		return openapi.Schema{Type: "boolean"}
+	default:
+		return openapi.Schema{Type: "string"}
+	}
+}
+
+// inferPostmanType guesses a parameter type from its example value
+func inferPostmanType(value interface{}) string {
+	s, ok := value.(string)
+	if !ok {
+		return inferSchema(value).Type
+	}
+	if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return "integer"
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return "number"
+	}
+	if s == "true" || s == "false" {
+		return "boolean"
+	}
+	return "string"
+}
+
+// postmanText reads a description given either as a string or as {"content": "..."}
+func postmanText(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var structured struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &structured); err == nil {
+		return structured.Content
+	}
+	return ""
+}
+
+// postmanVersion reads info.version, which may be a string or {major, minor, patch}
+func postmanVersion(raw json.RawMessage) string {
+	if len(raw) > 0 {
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil && text != "" {
+			return text
+		}
+		var semver struct {
+			Major, Minor, Patch int
+		}
+		if err := json.Unmarshal(raw, &semver); err == nil {
+			return fmt.Sprintf("%d.%d.%d", semver.Major, semver.Minor, semver.Patch)
+		}
+	}
+	return "1.0.0"
+}
+
+// valueOrEmpty returns a variable value, or an empty string for null
+func valueOrEmpty(value interface{}) interface{} {
+	if value == nil {
+		return ""
+	}
+	return value
+}
@@ -0,0 +1,153 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"api-to-mcp/pkg/openapi"
+)
+
+func parsePostman(t *testing.T, collection string) *openapi.ParsedSpec {
+	t.Helper()
+	specPath := filepath.Join(t.TempDir(), "collection.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(collection), 0644))
+
+	spec, err := NewOpenAPIParser(specPath, logrus.New()).ParseSpec()
+	require.NoError(t, err)
+	return spec
+}
+
+func findEndpoint(t *testing.T, spec *openapi.ParsedSpec, operationID string) openapi.Endpoint {
+	t.Helper()
+	for _, endpoint := range spec.Endpoints {
+		if endpoint.OperationID == operationID {
+			return endpoint
+		}
+	}
+	t.Fatalf("endpoint %s not found", operationID)
+	return openapi.Endpoint{}
+}
+
+func TestIsPostmanCollection(t *testing.T) {
+	assert.True(t, IsPostmanCollection([]byte(`{"info": {"schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"}}`)))
+	assert.False(t, IsPostmanCollection([]byte(`{"openapi": "3.0.0", "info": {"title": "x"}}`)))
+	assert.False(t, IsPostmanCollection([]byte("openapi: 3.0.0\n")))
+}
+
+func TestParseSpec_PostmanExample(t *testing.T) {
+	spec, err := NewOpenAPIParser("../../examples/petstore.postman_collection.json", logrus.New()).ParseSpec()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Petstore", spec.Info.Title)
+	assert.Equal(t, "1.0.0", spec.Info.Version)
+	require.Len(t, spec.Servers, 1)
+	assert.Equal(t, "https://petstore.example.com/v1", spec.Servers[0].URL)
+	require.NotNil(t, spec.Auth)
+	assert.Equal(t, "bearer", spec.Auth.Type)
+	assert.Len(t, spec.Endpoints, 4)
+
+	list := findEndpoint(t, spec, "list_pets")
+	assert.Equal(t, "GET", list.Method)
+	assert.Equal(t, "/pets", list.Path)
+	require.Len(t, list.Parameters, 2)
+	assert.Equal(t, "limit", list.Parameters[0].Name)
+	assert.Equal(t, "query", list.Parameters[0].In)
+	assert.Equal(t, "integer", list.Parameters[0].Schema.Type)
+	assert.Equal(t, "Maximum number of pets to return", list.Parameters[0].Description)
+	assert.Equal(t, "array", list.Responses["200"].Content["application/json"].Schema.Type)
+
+	get := findEndpoint(t, spec, "get_pet")
+	assert.Equal(t, "/pets/{petId}", get.Path)
+	require.Len(t, get.Parameters, 1)
+	assert.Equal(t, "path", get.Parameters[0].In)
+	assert.True(t, get.Parameters[0].Required)
+	assert.Equal(t, "The pet's ID", get.Parameters[0].Description)
+	assert.Contains(t, get.Responses, "200")
+
+	create := findEndpoint(t, spec, "create_pet")
+	assert.Equal(t, "POST", create.Method)
+	assert.Equal(t, "/pets", create.Path)
+	require.NotNil(t, create.RequestBody)
+	schema := create.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, "string", schema.Properties["name"].Type)
+	assert.Equal(t, "integer", schema.Properties["age"].Type)
+	// Content-Type is managed by the client; custom headers are kept
+	require.Len(t, create.Parameters, 1)
+	assert.Equal(t, "X-Request-Id", create.Parameters[0].Name)
+	assert.Equal(t, "header", create.Parameters[0].In)
+}
+
+func TestParseSpec_PostmanV20(t *testing.T) {
+	spec := parsePostman(t, `{
+  "info": {
+    "name": "Orders",
+    "version": {"major": 2, "minor": 1, "patch": 0},
+    "schema": "https://schema.getpostman.com/json/collection/v2.0.0/collection.json"
+  },
+  "item": [
+    {
+      "name": "Admin",
+      "auth": {"type": "apikey", "apikey": [{"key": "key", "value": "X-Api-Key"}, {"key": "in", "value": "header"}]},
+      "item": [
+        {
+          "name": "Update order",
+          "request": {
+            "method": "put",
+            "url": "http://{{host}}/orders/{{orderId}}?notify=true",
+            "body": {"mode": "raw", "raw": "{\"quantity\": {{quantity}}, \"price\": 9.5, \"tags\": [\"a\"]}"}
+          }
+        },
+        {
+          "name": "Update order",
+          "request": {
+            "method": "POST",
+            "url": "http://{{host}}/orders/{{orderId}}/notes",
+            "body": {"mode": "urlencoded", "urlencoded": [{"key": "note"}, {"key": "hidden", "disabled": true}]}
+          }
+        }
+      ]
+    }
+  ]
+}`)
+
+	assert.Equal(t, "2.1.0", spec.Info.Version)
+	require.NotNil(t, spec.Auth)
+	assert.Equal(t, openapi.AuthScheme{Type: "apikey", Name: "X-Api-Key", In: "header"}, *spec.Auth)
+	require.Len(t, spec.Endpoints, 2)
+
+	update := findEndpoint(t, spec, "update_order")
+	assert.Equal(t, "PUT", update.Method)
+	assert.Equal(t, "/orders/{orderId}", update.Path)
+	require.Len(t, update.Parameters, 2)
+	assert.Equal(t, "orderId", update.Parameters[0].Name)
+	assert.Equal(t, "path", update.Parameters[0].In)
+	assert.Equal(t, "notify", update.Parameters[1].Name)
+	assert.Equal(t, "boolean", update.Parameters[1].Schema.Type)
+
+	body := update.RequestBody.Content["application/json"].Schema
+	assert.Equal(t, "string", body.Properties["quantity"].Type)
+	assert.Equal(t, "number", body.Properties["price"].Type)
+	assert.Equal(t, "array", body.Properties["tags"].Type)
+	assert.Equal(t, "string", body.Properties["tags"].Items.Type)
+
+	notes := findEndpoint(t, spec, "update_order_2")
+	assert.Equal(t, "/orders/{orderId}/notes", notes.Path)
+	form := notes.RequestBody.Content["application/x-www-form-urlencoded"].Schema
+	assert.Contains(t, form.Properties, "note")
+	assert.NotContains(t, form.Properties, "hidden")
+}
+
+func TestParseSpec_PostmanUnsupportedSchema(t *testing.T) {
+	specPath := filepath.Join(t.TempDir(), "collection.json")
+	require.NoError(t, os.WriteFile(specPath, []byte(`{"info": {"name": "Old", "schema": "https://schema.getpostman.com/json/collection/v1.0.0/collection.json"}, "item": []}`), 0644))
+
+	_, err := NewOpenAPIParser(specPath, logrus.New()).ParseSpec()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unsupported Postman collection schema")
+}
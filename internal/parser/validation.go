@@ -19,6 +19,16 @@ func (e *ValidationError) Error() string {
 	return fmt.Sprintf("validation error in field '%s': %s", e.Field, e.Message)
 }
 
+// ValidationWarning represents a non-fatal issue that degrades generated tools
+type ValidationWarning struct {
+	Field   string
+	Message string
+}
+
+func (w ValidationWarning) String() string {
+	return fmt.Sprintf("%s: %s", w.Field, w.Message)
+}
+
 // Validator validates OpenAPI specifications
 type Validator struct {
 	logger *logrus.Logger
@@ -272,6 +282,38 @@ func (v *Validator) validateComponent(component openapi.Component, name string)
 	return nil
 }
 
+// CollectWarnings returns non-fatal issues found in a parsed specification
+func (v *Validator) CollectWarnings(spec *openapi.ParsedSpec) []ValidationWarning {
+	warnings := make([]ValidationWarning, 0)
+
+	if len(spec.Servers) == 0 {
+		warnings = append(warnings, ValidationWarning{
+			Field:   "servers",
+			Message: "no servers declared, base_url must be configured",
+		})
+	}
+
+	for _, endpoint := range spec.Endpoints {
+		field := fmt.Sprintf("%s %s", endpoint.Method, endpoint.Path)
+
+		if endpoint.OperationID == "" {
+			warnings = append(warnings, ValidationWarning{
+				Field:   field,
+				Message: "missing operationId, tool name will be derived from the path",
+			})
+		}
+
+		if endpoint.Summary == "" && endpoint.Description == "" {
+			warnings = append(warnings, ValidationWarning{
+				Field:   field,
+				Message: "missing summary and description, tool description will be generic",
+			})
+		}
+	}
+
+	return warnings
+}
+
 // Helper methods for validation
 
 func (v *Validator) isValidMethod(method string, validMethods []string) bool {
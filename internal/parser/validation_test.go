@@ -207,3 +207,38 @@ func TestValidationError_Error(t *testing.T) {
 	expected := "validation error in field 'test.field': test message"
 	assert.Equal(t, expected, err.Error())
 }
+
+func TestCollectWarnings(t *testing.T) {
+	logger := logrus.New()
+	validator := NewValidator(logger)
+
+	spec := &openapi.ParsedSpec{
+		Info: openapi.Info{
+			Title:   "Test API",
+			Version: "1.0.0",
+		},
+		Endpoints: []openapi.Endpoint{
+			{
+				Path:        "/users",
+				Method:      "GET",
+				OperationID: "listUsers",
+				Summary:     "List users",
+			},
+			{
+				Path:   "/users/{id}",
+				Method: "DELETE",
+			},
+		},
+	}
+
+	warnings := validator.CollectWarnings(spec)
+
+	fields := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		fields = append(fields, warning.Field)
+	}
+	assert.Len(t, warnings, 3)
+	assert.Contains(t, fields, "servers")
+	assert.Contains(t, fields, "DELETE /users/{id}")
+	assert.NotContains(t, fields, "GET /users")
+}
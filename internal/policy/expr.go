@@ -0,0 +1,718 @@
+package policy
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expression is a compiled policy expression. The syntax is a small subset of CEL:
+//
+//	literals     1000, 2.5, 'text', "text", true, false, null, [1, 2]
+//	variables    tool, namespace, method, path, client.id, client.roles, arguments.amount
+//	access       a.b, a['b'], a[0]; missing fields evaluate to null
+//	operators    ! - * / + == != < <= > >= in && ||
+//	functions    has(x), size(x), matches(s, re), startsWith(s, p), endsWith(s, p), lower(s), upper(s)
+type Expression struct {
+	source string
+	root   node
+}
+
+// node is an evaluable expression tree node
+type node func(vars map[string]interface{}) (interface{}, error)
+
+// Compile parses an expression
+func Compile(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	p := &parser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && !p.at(tokenEOF, "") {
+		err = fmt.Errorf("unexpected %q", p.peek().text)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid expression %q: %w", source, err)
+	}
+	return &Expression{source: source, root: root}, nil
+}
+
+// String returns the expression source
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Eval evaluates the expression against a set of variables
+func (e *Expression) Eval(vars map[string]interface{}) (interface{}, error) {
+	return e.root(vars)
+}
+
+// EvalBool evaluates an expression that must produce a boolean
+func (e *Expression) EvalBool(vars map[string]interface{}) (bool, error) {
+	value, err := e.root(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression %q produced %T, not a boolean", e.source, value)
+	}
+	return b, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenString
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// operators are matched longest first
+var operators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "(", ")", "[", "]", ",", ".", "+", "-", "*", "/"}
+
+// tokenize splits an expression into tokens
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	runes := []rune(source)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{tokenNumber, string(runes[start:i])})
+		case r == '\'' || r == '"':
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string")
+			}
+			i++
+			tokens = append(tokens, token{tokenString, sb.String()})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{tokenIdent, string(runes[start:i])})
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, token{tokenOperator, op})
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF}), nil
+}
+
+// parser is a recursive descent parser over tokens
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) at(kind tokenKind, text string) bool {
+	t := p.peek()
+	return t.kind == kind && (text == "" || t.text == text)
+}
+
+func (p *parser) accept(kind tokenKind, text string) bool {
+	if p.at(kind, text) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(text string) error {
+	if !p.accept(tokenOperator, text) {
+		return fmt.Errorf("expected %q, found %q", text, p.peek().text)
+	}
+	return nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenOperator, "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical(left, right, true)
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseComparison()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept(tokenOperator, "&&") {
+		right, err := p.parseComparison()
+		if err != nil {
+			return nil, err
+		}
+		left = logical(left, right, false)
+	}
+	return left, nil
+}
+
+// logical builds a short-circuiting && or || node
+func logical(left, right node, or bool) node {
+	return func(vars map[string]interface{}) (interface{}, error) {
+		l, err := asBool(left(vars))
+		if err != nil {
+			return nil, err
+		}
+		if l == or {
+			return l, nil
+		}
+		return asBool(right(vars))
+	}
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.accept(tokenIdent, "in") {
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]interface{}) (interface{}, error) {
+			l, r, err := evalBoth(left, right, vars)
+			if err != nil {
+				return nil, err
+			}
+			return contains(r, l)
+		}, nil
+	}
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if !p.accept(tokenOperator, op) {
+			continue
+		}
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]interface{}) (interface{}, error) {
+			l, r, err := evalBoth(left, right, vars)
+			if err != nil {
+				return nil, err
+			}
+			return compare(op, l, r)
+		}, nil
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdditive() (node, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for p.at(tokenOperator, "+") || p.at(tokenOperator, "-") {
+		op := p.peek().text
+		p.pos++
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmetic(op, left, right)
+	}
+	return left, nil
+}
+
+func (p *parser) parseMultiplicative() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.at(tokenOperator, "*") || p.at(tokenOperator, "/") {
+		op := p.peek().text
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = arithmetic(op, left, right)
+	}
+	return left, nil
+}
+
+// arithmetic builds a numeric operator node; + also concatenates strings
+func arithmetic(op string, left, right node) node {
+	return func(vars map[string]interface{}) (interface{}, error) {
+		l, r, err := evalBoth(left, right, vars)
+		if err != nil {
+			return nil, err
+		}
+		if ls, ok := l.(string); ok && op == "+" {
+			if rs, ok := r.(string); ok {
+				return ls + rs, nil
+			}
+		}
+		a, aok := toNumber(l)
+		b, bok := toNumber(r)
+		if !aok || !bok {
+			return nil, fmt.Errorf("operator %s needs numbers, got %T and %T", op, l, r)
+		}
+		switch op {
+		case "+":
+			return a + b, nil
+		case "-":
+			return a - b, nil
+		case "*":
+			return a * b, nil
+		default:
+			if b == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			return a / b, nil
+		}
+	}
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.accept(tokenOperator, "!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]interface{}) (interface{}, error) {
+			b, err := asBool(operand(vars))
+			return !b, err
+		}, nil
+	}
+	if p.accept(tokenOperator, "-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]interface{}) (interface{}, error) {
+			value, err := operand(vars)
+			if err != nil {
+				return nil, err
+			}
+			n, ok := toNumber(value)
+			if !ok {
+				return nil, fmt.Errorf("cannot negate %T", value)
+			}
+			return -n, nil
+		}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	current, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch {
+		case p.accept(tokenOperator, "."):
+			name := p.peek()
+			if name.kind != tokenIdent {
+				return nil, fmt.Errorf("expected field name after '.', found %q", name.text)
+			}
+			p.pos++
+			current = field(current, constant(name.text))
+		case p.accept(tokenOperator, "["):
+			index, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect("]"); err != nil {
+				return nil, err
+			}
+			current = field(current, index)
+		default:
+			return current, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenNumber:
+		p.pos++
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", t.text)
+		}
+		return constant(n), nil
+	case tokenString:
+		p.pos++
+		return constant(t.text), nil
+	case tokenIdent:
+		p.pos++
+		switch t.text {
+		case "true":
+			return constant(true), nil
+		case "false":
+			return constant(false), nil
+		case "null":
+			return constant(nil), nil
+		}
+		if p.accept(tokenOperator, "(") {
+			return p.parseCall(t.text)
+		}
+		name := t.text
+		return func(vars map[string]interface{}) (interface{}, error) {
+			return vars[name], nil
+		}, nil
+	case tokenOperator:
+		if p.accept(tokenOperator, "(") {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			return inner, p.expect(")")
+		}
+		if p.accept(tokenOperator, "[") {
+			items, err := p.parseList("]")
+			if err != nil {
+				return nil, err
+			}
+			return func(vars map[string]interface{}) (interface{}, error) {
+				list := make([]interface{}, len(items))
+				for i, item := range items {
+					value, err := item(vars)
+					if err != nil {
+						return nil, err
+					}
+					list[i] = value
+				}
+				return list, nil
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unexpected %q", t.text)
+}
+
+// parseList parses comma separated expressions up to a closing operator
+func (p *parser) parseList(closing string) ([]node, error) {
+	var items []node
+	if p.accept(tokenOperator, closing) {
+		return items, nil
+	}
+	for {
+		item, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if p.accept(tokenOperator, closing) {
+			return items, nil
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// parseCall parses the arguments of a built-in function
+func (p *parser) parseCall(name string) (node, error) {
+	args, err := p.parseList(")")
+	if err != nil {
+		return nil, err
+	}
+	f, exists := functions[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown function %s", name)
+	}
+	if len(args) != f.arity {
+		return nil, fmt.Errorf("%s takes %d argument(s), got %d", name, f.arity, len(args))
+	}
+	return func(vars map[string]interface{}) (interface{}, error) {
+		values := make([]interface{}, len(args))
+		for i, arg := range args {
+			value, err := arg(vars)
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+		}
+		return f.fn(values)
+	}, nil
+}
+
+// builtin is a function callable from expressions
+type builtin func(args []interface{}) (interface{}, error)
+
+// function is a built-in and its arity
+type function struct {
+	fn    builtin
+	arity int
+}
+
+// functions are the built-ins callable from expressions
+var functions = map[string]function{}
+
+func init() {
+	register := func(name string, arity int, fn builtin) {
+		functions[name] = function{fn: fn, arity: arity}
+	}
+	register("has", 1, func(args []interface{}) (interface{}, error) {
+		return args[0] != nil, nil
+	})
+	register("size", 1, func(args []interface{}) (interface{}, error) {
+		switch v := args[0].(type) {
+		case string:
+			return float64(len([]rune(v))), nil
+		case []interface{}:
+			return float64(len(v)), nil
+		case map[string]interface{}:
+			return float64(len(v)), nil
+		case nil:
+			return float64(0), nil
+		}
+		return nil, fmt.Errorf("size of %T is undefined", args[0])
+	})
+	register("matches", 2, stringFunc(func(s, pattern string) (interface{}, error) {
+		return regexp.MatchString(pattern, s)
+	}))
+	register("startsWith", 2, stringFunc(func(s, prefix string) (interface{}, error) {
+		return strings.HasPrefix(s, prefix), nil
+	}))
+	register("endsWith", 2, stringFunc(func(s, suffix string) (interface{}, error) {
+		return strings.HasSuffix(s, suffix), nil
+	}))
+	register("lower", 1, func(args []interface{}) (interface{}, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("lower needs a string, got %T", args[0])
+		}
+		return strings.ToLower(s), nil
+	})
+	register("upper", 1, func(args []interface{}) (interface{}, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("upper needs a string, got %T", args[0])
+		}
+		return strings.ToUpper(s), nil
+	})
+}
+
+// stringFunc adapts a two-string function into a builtin
+func stringFunc(fn func(a, b string) (interface{}, error)) builtin {
+	return func(args []interface{}) (interface{}, error) {
+		a, aok := args[0].(string)
+		b, bok := args[1].(string)
+		if !aok || !bok {
+			return nil, fmt.Errorf("expected string arguments, got %T and %T", args[0], args[1])
+		}
+		return fn(a, b)
+	}
+}
+
+// constant returns a node producing a fixed value
+func constant(value interface{}) node {
+	return func(map[string]interface{}) (interface{}, error) {
+		return value, nil
+	}
+}
+
+// field builds a member or index access node; missing members evaluate to null
+func field(target, key node) node {
+	return func(vars map[string]interface{}) (interface{}, error) {
+		container, k, err := evalBoth(target, key, vars)
+		if err != nil {
+			return nil, err
+		}
+		switch c := container.(type) {
+		case nil:
+			return nil, nil
+		case map[string]interface{}:
+			name, ok := k.(string)
+			if !ok {
+				return nil, fmt.Errorf("object key must be a string, got %T", k)
+			}
+			return c[name], nil
+		case []interface{}:
+			n, ok := toNumber(k)
+			if !ok {
+				return nil, fmt.Errorf("list index must be a number, got %T", k)
+			}
+			if i := int(n); float64(i) == n && i >= 0 && i < len(c) {
+				return c[i], nil
+			}
+			return nil, nil
+		}
+		return nil, fmt.Errorf("cannot access %v of %T", k, container)
+	}
+}
+
+// evalBoth evaluates two operands
+func evalBoth(left, right node, vars map[string]interface{}) (interface{}, interface{}, error) {
+	l, err := left(vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	r, err := right(vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	return l, r, nil
+}
+
+// asBool requires an operand to be a boolean
+func asBool(value interface{}, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected a boolean, got %T", value)
+	}
+	return b, nil
+}
+
+// toNumber converts numeric values to float64, the type JSON arguments decode to
+func toNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// equal compares values, treating all numeric types alike
+func equal(a, b interface{}) bool {
+	if x, ok := toNumber(a); ok {
+		y, ok := toNumber(b)
+		return ok && x == y
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compare applies a comparison operator; ordering is defined for numbers and strings
+func compare(op string, a, b interface{}) (interface{}, error) {
+	switch op {
+	case "==":
+		return equal(a, b), nil
+	case "!=":
+		return !equal(a, b), nil
+	}
+
+	var cmp int
+	if x, ok := toNumber(a); ok {
+		y, ok := toNumber(b)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %T with %T", a, b)
+		}
+		switch {
+		case x < y:
+			cmp = -1
+		case x > y:
+			cmp = 1
+		}
+	} else if x, ok := a.(string); ok {
+		y, ok := b.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare %T with %T", a, b)
+		}
+		cmp = strings.Compare(x, y)
+	} else {
+		return nil, fmt.Errorf("cannot order %T", a)
+	}
+
+	switch op {
+	case "<":
+		return cmp < 0, nil
+	case "<=":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	default:
+		return cmp >= 0, nil
+	}
+}
+
+// contains implements the in operator for lists, object keys and substrings
+func contains(container, item interface{}) (interface{}, error) {
+	switch c := container.(type) {
+	case []interface{}:
+		for _, element := range c {
+			if equal(element, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case []string:
+		for _, element := range c {
+			if equal(element, item) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case map[string]interface{}:
+		key, ok := item.(string)
+		if !ok {
+			return false, nil
+		}
+		_, exists := c[key]
+		return exists, nil
+	case string:
+		s, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot search %T in a string", item)
+		}
+		return strings.Contains(c, s), nil
+	case nil:
+		return false, nil
+	}
+	return nil, fmt.Errorf("in needs a list, object or string, got %T", container)
+}
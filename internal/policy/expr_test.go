@@ -0,0 +1,73 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpression_Eval(t *testing.T) {
+	vars := map[string]interface{}{
+		"tool":   "create_payment",
+		"client": map[string]interface{}{"id": "ops", "roles": []interface{}{"reader", "payments"}},
+		"arguments": map[string]interface{}{
+			"amount": float64(250),
+			"body":   map[string]interface{}{"items": []interface{}{map[string]interface{}{"sku": "A-1"}}},
+		},
+	}
+
+	tests := []struct {
+		expression string
+		expected   interface{}
+	}{
+		{"arguments.amount < 1000", true},
+		{"arguments.amount * 4 >= 1000", true},
+		{"arguments.missing == null", true},
+		{"has(arguments.missing.deeper)", false},
+		{"'payments' in client.roles && !('admin' in client.roles)", true},
+		{"arguments.body.items[0].sku == 'A-1'", true},
+		{"arguments['amount'] == 250", true},
+		{"startsWith(tool, 'create_') || false", true},
+		{"matches(client.id, '^o.s$')", true},
+		{"size(arguments.body.items) + 1", float64(2)},
+		{"'tenant-' + client.id", "tenant-ops"},
+		{"tool in ['delete_pet', \"create_payment\"]", true},
+		{"-arguments.amount < -(100)", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			expr, err := Compile(tt.expression)
+			require.NoError(t, err)
+			value, err := expr.Eval(vars)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestExpression_ShortCircuit(t *testing.T) {
+	expr, err := Compile("has(arguments.amount) && arguments.amount > 10")
+	require.NoError(t, err)
+
+	result, err := expr.EvalBool(map[string]interface{}{"arguments": map[string]interface{}{}})
+	require.NoError(t, err)
+	assert.False(t, result)
+}
+
+func TestExpression_EvalErrors(t *testing.T) {
+	for _, source := range []string{"arguments.amount > 10", "tool && true", "1 / 0", "tool"} {
+		expr, err := Compile(source)
+		require.NoError(t, err)
+		_, err = expr.EvalBool(map[string]interface{}{"tool": "x", "arguments": map[string]interface{}{}})
+		assert.Error(t, err, source)
+	}
+}
+
+func TestCompile_Invalid(t *testing.T) {
+	for _, source := range []string{"", "a ==", "(a", "'open", "a $ b", "unknown(1)", "has(1, 2)", "a.1"} {
+		_, err := Compile(source)
+		assert.Error(t, err, source)
+	}
+}
@@ -0,0 +1,188 @@
+package policy
+
+import (
+	"fmt"
+	"path"
+
+	"api-to-mcp/internal/config"
+)
+
+// Input describes a tool call awaiting a policy decision
+type Input struct {
+	Namespace   string
+	Tool        string
+	Method      string
+	Path        string
+	ClientID    string
+	ClientRoles []string
+	Arguments   map[string]interface{}
+}
+
+// Decision is the outcome of evaluating the policy for a call
+type Decision struct {
+	Allowed bool
+	// Rule names the rule that denied the call
+	Rule   string
+	Reason string
+	// Arguments are the call's arguments after every applicable rewrite
+	Arguments map[string]interface{}
+	// Rewritten lists the arguments changed by rewrites
+	Rewritten []string
+}
+
+// Engine evaluates compiled policy rules
+type Engine struct {
+	rules []rule
+}
+
+// rule is a compiled policy rule
+type rule struct {
+	name    string
+	tools   []string
+	when    *Expression
+	deny    *Expression
+	message string
+	set     []assignment
+}
+
+// assignment is a compiled argument rewrite
+type assignment struct {
+	argument string
+	value    *Expression
+}
+
+// NewEngine compiles the configured rules
+func NewEngine(cfg config.PolicyConfig) (*Engine, error) {
+	e := &Engine{}
+	for i, rc := range cfg.Rules {
+		name := rc.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+		}
+		if rc.Deny == "" && len(rc.Set) == 0 {
+			return nil, fmt.Errorf("policy %s has neither deny nor set", name)
+		}
+		for _, pattern := range rc.Tools {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("policy %s has invalid tool pattern %q: %w", name, pattern, err)
+			}
+		}
+
+		r := rule{name: name, tools: rc.Tools, message: rc.Message}
+		var err error
+		if r.when, err = compileOptional(rc.When); err != nil {
+			return nil, fmt.Errorf("policy %s: %w", name, err)
+		}
+		if r.deny, err = compileOptional(rc.Deny); err != nil {
+			return nil, fmt.Errorf("policy %s: %w", name, err)
+		}
+		for _, set := range rc.Set {
+			if set.Argument == "" {
+				return nil, fmt.Errorf("policy %s has an assignment without an argument", name)
+			}
+			value, err := Compile(set.Value)
+			if err != nil {
+				return nil, fmt.Errorf("policy %s: %w", name, err)
+			}
+			r.set = append(r.set, assignment{argument: set.Argument, value: value})
+		}
+		e.rules = append(e.rules, r)
+	}
+	return e, nil
+}
+
+// compileOptional compiles an expression unless it is empty
+func compileOptional(source string) (*Expression, error) {
+	if source == "" {
+		return nil, nil
+	}
+	return Compile(source)
+}
+
+// Evaluate applies the rules in order. A rule's rewrites are visible to later rules, the first
+// deny stops evaluation, and an expression that fails to evaluate denies the call
+func (e *Engine) Evaluate(input Input) Decision {
+	arguments := make(map[string]interface{}, len(input.Arguments))
+	for key, value := range input.Arguments {
+		arguments[key] = value
+	}
+	decision := Decision{Allowed: true, Arguments: arguments}
+
+	for _, r := range e.rules {
+		if !r.appliesTo(input.Tool) {
+			continue
+		}
+		vars := variables(input, arguments)
+
+		if r.when != nil {
+			applies, err := r.when.EvalBool(vars)
+			if err != nil {
+				return denied(decision, r.name, fmt.Sprintf("policy %s could not be evaluated: %v", r.name, err))
+			}
+			if !applies {
+				continue
+			}
+		}
+
+		if r.deny != nil {
+			deny, err := r.deny.EvalBool(vars)
+			if err != nil {
+				return denied(decision, r.name, fmt.Sprintf("policy %s could not be evaluated: %v", r.name, err))
+			}
+			if deny {
+				reason := r.message
+				if reason == "" {
+					reason = fmt.Sprintf("denied by policy %s", r.name)
+				}
+				return denied(decision, r.name, reason)
+			}
+		}
+
+		for _, set := range r.set {
+			value, err := set.value.Eval(vars)
+			if err != nil {
+				return denied(decision, r.name, fmt.Sprintf("policy %s could not be evaluated: %v", r.name, err))
+			}
+			arguments[set.argument] = value
+			decision.Rewritten = append(decision.Rewritten, set.argument)
+		}
+	}
+	return decision
+}
+
+// appliesTo reports whether a rule covers a tool
+func (r rule) appliesTo(tool string) bool {
+	if len(r.tools) == 0 {
+		return true
+	}
+	for _, pattern := range r.tools {
+		if matched, _ := path.Match(pattern, tool); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// denied marks a decision as a denial by a rule
+func denied(decision Decision, rule, reason string) Decision {
+	decision.Allowed = false
+	decision.Rule = rule
+	decision.Reason = reason
+	return decision
+}
+
+// variables exposes a call to expressions
+func variables(input Input, arguments map[string]interface{}) map[string]interface{} {
+	roles := make([]interface{}, len(input.ClientRoles))
+	for i, role := range input.ClientRoles {
+		roles[i] = role
+	}
+	return map[string]interface{}{
+		"tool":      input.Tool,
+		"namespace": input.Namespace,
+		"method":    input.Method,
+		"path":      input.Path,
+		"client":    map[string]interface{}{"id": input.ClientID, "roles": roles},
+		"arguments": arguments,
+	}
+}
@@ -0,0 +1,80 @@
+package policy
+
+import (
+	"testing"
+
+	"api-to-mcp/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_Evaluate(t *testing.T) {
+	engine, err := NewEngine(config.PolicyConfig{Enabled: true, Rules: []config.PolicyRuleConfig{
+		{
+			Name:  "scope-tenant",
+			Tools: []string{"*"},
+			When:  "client.id != ''",
+			Set:   []config.PolicyAssignment{{Argument: "tenantId", Value: "client.id"}},
+		},
+		{
+			Name:    "cap-amount",
+			Tools:   []string{"create_*"},
+			Deny:    "has(arguments.amount) && arguments.amount >= 1000",
+			Message: "amount must be below 1000",
+		},
+	}})
+	require.NoError(t, err)
+
+	decision := engine.Evaluate(Input{Tool: "create_payment", ClientID: "acme", Arguments: map[string]interface{}{
+		"amount":   float64(10),
+		"tenantId": "other",
+	}})
+	assert.True(t, decision.Allowed)
+	assert.Equal(t, "acme", decision.Arguments["tenantId"])
+	assert.Equal(t, []string{"tenantId"}, decision.Rewritten)
+
+	decision = engine.Evaluate(Input{Tool: "create_payment", Arguments: map[string]interface{}{"amount": float64(5000)}})
+	assert.False(t, decision.Allowed)
+	assert.Equal(t, "cap-amount", decision.Rule)
+	assert.Equal(t, "amount must be below 1000", decision.Reason)
+
+	decision = engine.Evaluate(Input{Tool: "list_payments", Arguments: map[string]interface{}{"amount": float64(5000)}})
+	assert.True(t, decision.Allowed)
+}
+
+func TestEngine_FailsClosed(t *testing.T) {
+	engine, err := NewEngine(config.PolicyConfig{Rules: []config.PolicyRuleConfig{
+		{Name: "cap", Deny: "arguments.amount >= 1000"},
+	}})
+	require.NoError(t, err)
+
+	decision := engine.Evaluate(Input{Tool: "create_payment", Arguments: map[string]interface{}{"amount": "lots"}})
+	assert.False(t, decision.Allowed)
+	assert.Contains(t, decision.Reason, "could not be evaluated")
+}
+
+func TestEngine_DoesNotModifyInput(t *testing.T) {
+	engine, err := NewEngine(config.PolicyConfig{Rules: []config.PolicyRuleConfig{
+		{Set: []config.PolicyAssignment{{Argument: "limit", Value: "10"}}},
+	}})
+	require.NoError(t, err)
+
+	arguments := map[string]interface{}{"limit": float64(500)}
+	decision := engine.Evaluate(Input{Tool: "list", Arguments: arguments})
+	assert.Equal(t, float64(10), decision.Arguments["limit"])
+	assert.Equal(t, float64(500), arguments["limit"])
+}
+
+func TestNewEngine_Invalid(t *testing.T) {
+	invalid := []config.PolicyRuleConfig{
+		{Name: "empty"},
+		{Name: "bad-deny", Deny: "a =="},
+		{Name: "bad-tools", Tools: []string{"["}, Deny: "true"},
+		{Name: "bad-set", Set: []config.PolicyAssignment{{Value: "1"}}},
+	}
+	for _, rule := range invalid {
+		_, err := NewEngine(config.PolicyConfig{Rules: []config.PolicyRuleConfig{rule}})
+		assert.Error(t, err, rule.Name)
+	}
+}
@@ -0,0 +1,204 @@
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMask replaces redacted values
+const DefaultMask = "[REDACTED]"
+
+// Rules configures what a Redactor masks
+type Rules struct {
+	// Fields are case-insensitive regular expressions matched against object keys at any depth
+	Fields []string
+	// Paths are JSONPath-style locations such as $.arguments.body.card.number or $.items[*].token
+	Paths []string
+	// Patterns are regular expressions masked inside string values and log messages
+	Patterns []string
+	Mask     string
+}
+
+// Redactor masks sensitive values in log entries and payloads
+type Redactor struct {
+	fields   []*regexp.Regexp
+	paths    [][]string
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+// New compiles redaction rules
+func New(rules Rules) (*Redactor, error) {
+	r := &Redactor{mask: rules.Mask}
+	if r.mask == "" {
+		r.mask = DefaultMask
+	}
+
+	for _, field := range rules.Fields {
+		re, err := regexp.Compile("(?i)" + field)
+		if err != nil {
+			return nil, fmt.Errorf("invalid field pattern %q: %w", field, err)
+		}
+		r.fields = append(r.fields, re)
+	}
+
+	for _, path := range rules.Paths {
+		segments, err := parsePath(path)
+		if err != nil {
+			return nil, err
+		}
+		r.paths = append(r.paths, segments)
+	}
+
+	for _, pattern := range rules.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid mask pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+// parsePath splits a JSONPath-style expression into segments; array indexes become their own segment
+func parsePath(path string) ([]string, error) {
+	trimmed := strings.TrimPrefix(path, "$")
+	trimmed = strings.ReplaceAll(trimmed, "[", ".")
+	trimmed = strings.ReplaceAll(trimmed, "]", "")
+	trimmed = strings.TrimPrefix(trimmed, ".")
+	if trimmed == "" {
+		return nil, fmt.Errorf("invalid redaction path %q", path)
+	}
+
+	segments := strings.Split(trimmed, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("invalid redaction path %q", path)
+		}
+	}
+	return segments, nil
+}
+
+// Fields returns a redacted copy of log fields; the originals are left untouched
+func (r *Redactor) Fields(data logrus.Fields) logrus.Fields {
+	redacted := make(logrus.Fields, len(data))
+	for key, value := range data {
+		redacted[key] = r.redact([]string{key}, value)
+	}
+	return redacted
+}
+
+// String masks every configured pattern inside s
+func (r *Redactor) String(s string) string {
+	for _, re := range r.patterns {
+		s = re.ReplaceAllString(s, r.mask)
+	}
+	return s
+}
+
+// Value returns a redacted copy of an arbitrary value, such as an upstream response; paths are
+// relative to the value itself, e.g. $.user.ssn or $[*].ssn
+func (r *Redactor) Value(value interface{}) interface{} {
+	return r.redact(nil, value)
+}
+
+// redact masks a value found at path
+func (r *Redactor) redact(path []string, value interface{}) interface{} {
+	if len(path) > 0 && (r.matchesField(path[len(path)-1]) || r.matchesPath(path)) {
+		return r.mask
+	}
+
+	switch v := value.(type) {
+	case nil, bool, int, int64, float64:
+		return v
+	case string:
+		return r.String(v)
+	case error:
+		return r.String(v.Error())
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			redacted[key] = r.redact(appendPath(path, key), child)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, child := range v {
+			redacted[i] = r.redact(appendPath(path, strconv.Itoa(i)), child)
+		}
+		return redacted
+	default:
+		// Normalize other composite values through JSON so nested keys can be inspected
+		data, err := json.Marshal(v)
+		if err != nil {
+			return v
+		}
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return v
+		}
+		switch generic.(type) {
+		case map[string]interface{}, []interface{}:
+			return r.redact(path, generic)
+		}
+		return v
+	}
+}
+
+// matchesField reports whether a key matches a field pattern
+func (r *Redactor) matchesField(key string) bool {
+	for _, re := range r.fields {
+		if re.MatchString(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesPath reports whether a location matches a path rule; * matches any segment
+func (r *Redactor) matchesPath(path []string) bool {
+	for _, rule := range r.paths {
+		if len(rule) != len(path) {
+			continue
+		}
+		matched := true
+		for i, segment := range rule {
+			if segment != "*" && segment != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// appendPath returns a new path with segment appended, never sharing the parent's backing array
+func appendPath(path []string, segment string) []string {
+	child := make([]string, len(path)+1)
+	copy(child, path)
+	child[len(path)] = segment
+	return child
+}
+
+// Formatter applies a Redactor to every entry before delegating to another formatter
+type Formatter struct {
+	Inner    logrus.Formatter
+	Redactor *Redactor
+}
+
+// Format redacts the entry's message and fields, then formats it with the inner formatter
+func (f *Formatter) Format(entry *logrus.Entry) ([]byte, error) {
+	redacted := *entry
+	redacted.Message = f.Redactor.String(entry.Message)
+	redacted.Data = f.Redactor.Fields(entry.Data)
+	return f.Inner.Format(&redacted)
+}
@@ -0,0 +1,102 @@
+package redact
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFields_FieldPatterns(t *testing.T) {
+	redactor, err := New(Rules{Fields: []string{"^password$", "^api[-_]?key$"}})
+	require.NoError(t, err)
+
+	arguments := map[string]interface{}{
+		"username": "alice",
+		"body": map[string]interface{}{
+			"Password": "hunter2",
+			"items":    []interface{}{map[string]interface{}{"API_KEY": "abc"}},
+		},
+	}
+	redacted := redactor.Fields(logrus.Fields{"arguments": arguments})
+
+	body := redacted["arguments"].(map[string]interface{})["body"].(map[string]interface{})
+	assert.Equal(t, DefaultMask, body["Password"])
+	assert.Equal(t, DefaultMask, body["items"].([]interface{})[0].(map[string]interface{})["API_KEY"])
+	assert.Equal(t, "alice", redacted["arguments"].(map[string]interface{})["username"])
+
+	// The original values are not modified
+	assert.Equal(t, "hunter2", arguments["body"].(map[string]interface{})["Password"])
+}
+
+func TestFields_Paths(t *testing.T) {
+	redactor, err := New(Rules{Paths: []string{"$.arguments.card.number", "$.arguments.people[*].ssn"}, Mask: "***"})
+	require.NoError(t, err)
+
+	redacted := redactor.Fields(logrus.Fields{
+		"arguments": map[string]interface{}{
+			"card":   map[string]interface{}{"number": "4111111111111111", "holder": "alice"},
+			"people": []interface{}{map[string]interface{}{"ssn": "123-45-6789"}},
+			"number": "not redacted",
+		},
+	})
+
+	arguments := redacted["arguments"].(map[string]interface{})
+	assert.Equal(t, "***", arguments["card"].(map[string]interface{})["number"])
+	assert.Equal(t, "alice", arguments["card"].(map[string]interface{})["holder"])
+	assert.Equal(t, "***", arguments["people"].([]interface{})[0].(map[string]interface{})["ssn"])
+	assert.Equal(t, "not redacted", arguments["number"])
+}
+
+func TestString_Patterns(t *testing.T) {
+	redactor, err := New(Rules{Patterns: []string{`\b\d{13,16}\b`}})
+	require.NoError(t, err)
+
+	assert.Equal(t, "card [REDACTED] declined", redactor.String("card 4111111111111111 declined"))
+	assert.Equal(t, "boom [REDACTED]", redactor.Value(errors.New("boom 4111111111111111")))
+}
+
+func TestNew_InvalidRules(t *testing.T) {
+	_, err := New(Rules{Fields: []string{"("}})
+	assert.Error(t, err)
+
+	_, err = New(Rules{Paths: []string{"$."}})
+	assert.Error(t, err)
+}
+
+func TestFormatter(t *testing.T) {
+	redactor, err := New(Rules{Fields: []string{"^token$"}, Patterns: []string{`secret-\w+`}})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&Formatter{Inner: &logrus.JSONFormatter{}, Redactor: redactor})
+
+	logger.WithField("token", "abc").WithField("headers", map[string]string{"token": "xyz"}).Info("using secret-value")
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, DefaultMask, line["token"])
+	assert.Equal(t, DefaultMask, line["headers"].(map[string]interface{})["token"])
+	assert.Equal(t, "using [REDACTED]", line["msg"])
+}
+
+func TestValue_ResponsePaths(t *testing.T) {
+	redactor, err := New(Rules{Fields: []string{"^password_hash$"}, Paths: []string{"$[*].ssn", "$[*].card.number"}})
+	require.NoError(t, err)
+
+	response := []interface{}{
+		map[string]interface{}{"name": "alice", "ssn": "123-45-6789", "password_hash": "x", "card": map[string]interface{}{"number": "4111"}},
+	}
+	redacted := redactor.Value(response).([]interface{})[0].(map[string]interface{})
+
+	assert.Equal(t, "alice", redacted["name"])
+	assert.Equal(t, DefaultMask, redacted["ssn"])
+	assert.Equal(t, DefaultMask, redacted["password_hash"])
+	assert.Equal(t, DefaultMask, redacted["card"].(map[string]interface{})["number"])
+}
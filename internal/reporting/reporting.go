@@ -0,0 +1,283 @@
+package reporting
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// queueSize bounds the number of events waiting to be sent
+const queueSize = 100
+
+// Options configures a Reporter
+type Options struct {
+	DSN         string
+	Environment string
+	Release     string
+	ServerName  string
+	// BurstThreshold is the number of upstream 5xx responses of one tool within BurstWindow that triggers a report
+	BurstThreshold int
+	BurstWindow    time.Duration
+}
+
+// DSN is a parsed Sentry data source name
+type DSN struct {
+	PublicKey string
+	StoreURL  string
+}
+
+// ParseDSN parses a DSN of the form scheme://<key>@<host>[/<path>]/<project>
+func ParseDSN(raw string) (*DSN, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid DSN: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid DSN: unsupported scheme %q", u.Scheme)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("invalid DSN: missing public key")
+	}
+
+	path := strings.Trim(u.Path, "/")
+	idx := strings.LastIndex(path, "/")
+	project := path[idx+1:]
+	if project == "" {
+		return nil, fmt.Errorf("invalid DSN: missing project ID")
+	}
+	prefix := ""
+	if idx >= 0 {
+		prefix = "/" + path[:idx]
+	}
+
+	return &DSN{
+		PublicKey: u.User.Username(),
+		StoreURL:  fmt.Sprintf("%s://%s%s/api/%s/store/", u.Scheme, u.Host, prefix, project),
+	}, nil
+}
+
+// Event is a Sentry store API event
+type Event struct {
+	EventID     string            `json:"event_id"`
+	Timestamp   string            `json:"timestamp"`
+	Level       string            `json:"level"`
+	Platform    string            `json:"platform"`
+	Logger      string            `json:"logger"`
+	Message     string            `json:"message"`
+	Fingerprint []string          `json:"fingerprint,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	Extra       map[string]string `json:"extra,omitempty"`
+	Environment string            `json:"environment,omitempty"`
+	Release     string            `json:"release,omitempty"`
+	ServerName  string            `json:"server_name,omitempty"`
+}
+
+// burst tracks recent upstream 5xx responses of one tool
+type burst struct {
+	start    time.Time
+	count    int
+	reported bool
+}
+
+// Reporter sends errors to a Sentry-compatible endpoint; a nil Reporter discards everything
+type Reporter struct {
+	dsn    *DSN
+	opts   Options
+	client *http.Client
+	logger *logrus.Logger
+
+	events chan Event
+	wg     sync.WaitGroup
+
+	mu     sync.Mutex
+	closed bool
+	bursts map[string]*burst
+}
+
+// NewReporter creates a reporter and starts its background sender
+func NewReporter(opts Options, logger *logrus.Logger) (*Reporter, error) {
+	dsn, err := ParseDSN(opts.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Reporter{
+		dsn:    dsn,
+		opts:   opts,
+		client: &http.Client{Timeout: 10 * time.Second},
+		logger: logger,
+		events: make(chan Event, queueSize),
+		bursts: make(map[string]*burst),
+	}
+
+	r.wg.Add(1)
+	go r.run()
+	return r, nil
+}
+
+// CapturePanic reports a recovered panic with its stack trace
+func (r *Reporter) CapturePanic(value interface{}, stack []byte, tags map[string]string) {
+	if r == nil {
+		return
+	}
+	event := r.newEvent("fatal", fmt.Sprintf("panic: %v", value), tags)
+	event.Fingerprint = []string{"panic", fmt.Sprintf("%v", value)}
+	event.Extra = map[string]string{"stacktrace": string(stack)}
+	r.enqueue(event)
+}
+
+// CaptureGenerationFailure reports a tool that could not be generated, fingerprinted by tool name
+func (r *Reporter) CaptureGenerationFailure(tool string, err error, tags map[string]string) {
+	if r == nil {
+		return
+	}
+	event := r.newEvent("error", err.Error(), withTag(tags, "tool_name", tool))
+	event.Fingerprint = []string{"generation-failure", tool}
+	r.enqueue(event)
+}
+
+// ObserveUpstreamStatus counts upstream 5xx responses per tool and reports once per burst window
+// when the threshold is reached
+func (r *Reporter) ObserveUpstreamStatus(tool string, status int, tags map[string]string) {
+	if r == nil || status < http.StatusInternalServerError || r.opts.BurstThreshold <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	now := time.Now()
+	b, exists := r.bursts[tool]
+	if !exists || now.Sub(b.start) > r.opts.BurstWindow {
+		b = &burst{start: now}
+		r.bursts[tool] = b
+	}
+	b.count++
+	trigger := !b.reported && b.count >= r.opts.BurstThreshold
+	if trigger {
+		b.reported = true
+	}
+	count := b.count
+	r.mu.Unlock()
+
+	if !trigger {
+		return
+	}
+	message := fmt.Sprintf("upstream returned %d server errors within %s for tool %s (last HTTP %d)", count, r.opts.BurstWindow, tool, status)
+	event := r.newEvent("error", message, withTag(tags, "tool_name", tool))
+	event.Fingerprint = []string{"upstream-5xx-burst", tool}
+	r.enqueue(event)
+}
+
+// Flush waits up to timeout for queued events to be sent and stops the reporter
+func (r *Reporter) Flush(timeout time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	if !r.closed {
+		r.closed = true
+		close(r.events)
+	}
+	r.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		r.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		r.logger.Warn("Timed out flushing error reports")
+	}
+}
+
+// newEvent builds an event with the reporter's common attributes
+func (r *Reporter) newEvent(level, message string, tags map[string]string) Event {
+	return Event{
+		EventID:     newEventID(),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Level:       level,
+		Platform:    "go",
+		Logger:      "api-to-mcp",
+		Message:     message,
+		Tags:        tags,
+		Environment: r.opts.Environment,
+		Release:     r.opts.Release,
+		ServerName:  r.opts.ServerName,
+	}
+}
+
+// enqueue queues an event without blocking, dropping it when the queue is full or flushed
+func (r *Reporter) enqueue(event Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return
+	}
+	select {
+	case r.events <- event:
+	default:
+		r.logger.WithField("event_id", event.EventID).Warn("Error report queue full, dropping event")
+	}
+}
+
+// run sends queued events until the queue is closed
+func (r *Reporter) run() {
+	defer r.wg.Done()
+	for event := range r.events {
+		if err := r.send(event); err != nil {
+			r.logger.WithError(err).WithField("event_id", event.EventID).Warn("Failed to send error report")
+		}
+	}
+}
+
+// send posts an event to the store endpoint
+func (r *Reporter) send(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.dsn.StoreURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=api-to-mcp/%s, sentry_key=%s", r.opts.Release, r.dsn.PublicKey))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("error reporting endpoint returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// withTag returns a copy of tags with an extra key set
+func withTag(tags map[string]string, key, value string) map[string]string {
+	merged := make(map[string]string, len(tags)+1)
+	for k, v := range tags {
+		merged[k] = v
+	}
+	merged[key] = value
+	return merged
+}
+
+// newEventID returns a random 32 character hex event ID
+func newEventID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
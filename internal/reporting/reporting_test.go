@@ -0,0 +1,98 @@
+package reporting
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDSN(t *testing.T) {
+	dsn, err := ParseDSN("https://abc123@sentry.example.com/42")
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", dsn.PublicKey)
+	assert.Equal(t, "https://sentry.example.com/api/42/store/", dsn.StoreURL)
+
+	dsn, err = ParseDSN("http://key@localhost:9000/sentry/7")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:9000/sentry/api/7/store/", dsn.StoreURL)
+
+	for _, raw := range []string{"https://sentry.example.com/42", "https://key@sentry.example.com/", "ftp://key@host/1"} {
+		_, err := ParseDSN(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+// collector is a fake store endpoint recording received events
+type collector struct {
+	mu     sync.Mutex
+	events []Event
+	auth   string
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var event Event
+	json.NewDecoder(r.Body).Decode(&event)
+	c.mu.Lock()
+	c.events = append(c.events, event)
+	c.auth = r.Header.Get("X-Sentry-Auth")
+	c.mu.Unlock()
+	w.WriteHeader(http.StatusOK)
+}
+
+func newTestReporter(t *testing.T, opts Options) (*Reporter, *collector) {
+	c := &collector{}
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+
+	opts.DSN = strings.Replace(srv.URL, "://", "://public@", 1) + "/1"
+	reporter, err := NewReporter(opts, logrus.New())
+	require.NoError(t, err)
+	return reporter, c
+}
+
+func TestCaptureGenerationFailure(t *testing.T) {
+	reporter, c := newTestReporter(t, Options{Environment: "test", Release: "v1"})
+
+	reporter.CaptureGenerationFailure("getpet", errors.New("bad schema"), map[string]string{"namespace": "default"})
+	reporter.Flush(time.Second)
+
+	require.Len(t, c.events, 1)
+	event := c.events[0]
+	assert.Equal(t, []string{"generation-failure", "getpet"}, event.Fingerprint)
+	assert.Equal(t, "getpet", event.Tags["tool_name"])
+	assert.Equal(t, "default", event.Tags["namespace"])
+	assert.Equal(t, "test", event.Environment)
+	assert.Len(t, event.EventID, 32)
+	assert.Contains(t, c.auth, "sentry_key=public")
+}
+
+func TestObserveUpstreamStatus_ReportsOncePerBurst(t *testing.T) {
+	reporter, c := newTestReporter(t, Options{BurstThreshold: 3, BurstWindow: time.Minute})
+
+	reporter.ObserveUpstreamStatus("getpet", 404, nil)
+	for i := 0; i < 5; i++ {
+		reporter.ObserveUpstreamStatus("getpet", 502, nil)
+	}
+	reporter.ObserveUpstreamStatus("addpet", 500, nil)
+	reporter.Flush(time.Second)
+
+	require.Len(t, c.events, 1)
+	assert.Equal(t, []string{"upstream-5xx-burst", "getpet"}, c.events[0].Fingerprint)
+}
+
+func TestNilReporter(t *testing.T) {
+	var reporter *Reporter
+	reporter.CapturePanic("boom", nil, nil)
+	reporter.CaptureGenerationFailure("getpet", errors.New("x"), nil)
+	reporter.ObserveUpstreamStatus("getpet", 500, nil)
+	reporter.Flush(time.Second)
+}
@@ -0,0 +1,155 @@
+package safety
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"api-to-mcp/internal/config"
+)
+
+// ConfirmArgument is the tool argument carrying a confirmation token
+const ConfirmArgument = "_confirm"
+
+// RequiresConfirmation reports whether an operation is gated by the confirmation policy
+func RequiresConfirmation(cfg config.ConfirmationConfig, method, path string) bool {
+	if !cfg.Enabled {
+		return false
+	}
+	for _, gated := range cfg.Methods {
+		if strings.EqualFold(gated, method) {
+			return true
+		}
+	}
+	for _, pattern := range cfg.Paths {
+		if MatchPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPath matches a path against a pattern where * matches one segment and ** any number of segments
+func MatchPath(pattern, path string) bool {
+	return matchSegments(splitPath(pattern), splitPath(path))
+}
+
+// splitPath splits a path into its non-empty segments
+func splitPath(path string) []string {
+	return strings.FieldsFunc(path, func(r rune) bool { return r == '/' })
+}
+
+// matchSegments matches path segments against pattern segments
+func matchSegments(pattern, path []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(path); i++ {
+				if matchSegments(pattern[1:], path[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(path) == 0 || (pattern[0] != "*" && pattern[0] != path[0]) {
+			return false
+		}
+		pattern, path = pattern[1:], path[1:]
+	}
+	return len(path) == 0
+}
+
+// Challenge is returned instead of executing a gated tool until the call is confirmed
+type Challenge struct {
+	ConfirmationRequired bool      `json:"confirmationRequired"`
+	Action               string    `json:"action"`
+	Token                string    `json:"confirmationToken"`
+	ExpiresAt            time.Time `json:"expiresAt"`
+	Instructions         string    `json:"instructions"`
+}
+
+// pendingAction is an issued, not yet used confirmation token
+type pendingAction struct {
+	fingerprint string
+	expiresAt   time.Time
+}
+
+// Confirmer issues single-use confirmation tokens bound to a tool call's exact arguments
+type Confirmer struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	pending map[string]pendingAction
+}
+
+// NewConfirmer creates a confirmer whose tokens expire after ttl
+func NewConfirmer(ttl time.Duration) *Confirmer {
+	return &Confirmer{
+		ttl:     ttl,
+		pending: make(map[string]pendingAction),
+	}
+}
+
+// Challenge issues a token for the given call and describes the action awaiting confirmation
+func (c *Confirmer) Challenge(namespace, tool, action string, arguments map[string]interface{}) Challenge {
+	token := newToken()
+	expiresAt := time.Now().Add(c.ttl)
+
+	c.mu.Lock()
+	c.prune()
+	c.pending[token] = pendingAction{
+		fingerprint: fingerprint(namespace, tool, arguments),
+		expiresAt:   expiresAt,
+	}
+	c.mu.Unlock()
+
+	return Challenge{
+		ConfirmationRequired: true,
+		Action:               action,
+		Token:                token,
+		ExpiresAt:            expiresAt.UTC(),
+		Instructions:         fmt.Sprintf("This action requires confirmation. To proceed, repeat the call with the same arguments and %s set to the confirmation token.", ConfirmArgument),
+	}
+}
+
+// Confirm consumes a token, reporting whether it was issued for exactly this call and has not expired
+func (c *Confirmer) Confirm(token, namespace, tool string, arguments map[string]interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	action, exists := c.pending[token]
+	if !exists {
+		return false
+	}
+	delete(c.pending, token)
+
+	return time.Now().Before(action.expiresAt) && action.fingerprint == fingerprint(namespace, tool, arguments)
+}
+
+// prune drops expired tokens; callers hold the lock
+func (c *Confirmer) prune() {
+	now := time.Now()
+	for token, action := range c.pending {
+		if now.After(action.expiresAt) {
+			delete(c.pending, token)
+		}
+	}
+}
+
+// fingerprint hashes a call's identity; encoding/json sorts map keys so equal arguments hash equally
+func fingerprint(namespace, tool string, arguments map[string]interface{}) string {
+	data, _ := json.Marshal(arguments)
+	sum := sha256.Sum256([]byte(namespace + "\x00" + tool + "\x00" + string(data)))
+	return hex.EncodeToString(sum[:])
+}
+
+// newToken returns a random confirmation token
+func newToken() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
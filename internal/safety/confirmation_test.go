@@ -0,0 +1,77 @@
+package safety
+
+import (
+	"testing"
+	"time"
+
+	"api-to-mcp/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/admin/**", "/admin", true},
+		{"/admin/**", "/admin/users/1", true},
+		{"/admin/**", "/administrator", false},
+		{"/users/*", "/users/{id}", true},
+		{"/users/*", "/users/{id}/roles", false},
+		{"/**/delete", "/a/b/delete", true},
+		{"/pet", "/pet/", true},
+		{"/pet", "/pets", false},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchPath(tt.pattern, tt.path), "%s vs %s", tt.pattern, tt.path)
+	}
+}
+
+func TestRequiresConfirmation(t *testing.T) {
+	cfg := config.ConfirmationConfig{
+		Enabled: true,
+		Methods: []string{"DELETE"},
+		Paths:   []string{"/admin/**"},
+	}
+
+	assert.True(t, RequiresConfirmation(cfg, "delete", "/pet/{petId}"))
+	assert.True(t, RequiresConfirmation(cfg, "GET", "/admin/users"))
+	assert.False(t, RequiresConfirmation(cfg, "GET", "/pet/{petId}"))
+
+	cfg.Enabled = false
+	assert.False(t, RequiresConfirmation(cfg, "DELETE", "/pet/{petId}"))
+}
+
+func TestConfirmer(t *testing.T) {
+	confirmer := NewConfirmer(time.Minute)
+	args := map[string]interface{}{"petId": 1}
+
+	challenge := confirmer.Challenge("default", "deletepet", "DELETE /pet/{petId}", args)
+	assert.True(t, challenge.ConfirmationRequired)
+	assert.NotEmpty(t, challenge.Token)
+
+	// Tokens are bound to the exact call
+	assert.False(t, confirmer.Confirm(challenge.Token, "default", "deletepet", map[string]interface{}{"petId": 2}))
+
+	// A rejected token is consumed
+	assert.False(t, confirmer.Confirm(challenge.Token, "default", "deletepet", args))
+
+	challenge = confirmer.Challenge("default", "deletepet", "DELETE /pet/{petId}", args)
+	assert.False(t, confirmer.Confirm(challenge.Token, "tenant", "deletepet", args))
+
+	challenge = confirmer.Challenge("default", "deletepet", "DELETE /pet/{petId}", args)
+	assert.True(t, confirmer.Confirm(challenge.Token, "default", "deletepet", map[string]interface{}{"petId": 1}))
+	assert.False(t, confirmer.Confirm(challenge.Token, "default", "deletepet", args), "tokens are single use")
+}
+
+func TestConfirmer_Expiry(t *testing.T) {
+	confirmer := NewConfirmer(time.Millisecond)
+	args := map[string]interface{}{"petId": 1}
+
+	challenge := confirmer.Challenge("default", "deletepet", "DELETE /pet/{petId}", args)
+	time.Sleep(5 * time.Millisecond)
+	assert.False(t, confirmer.Confirm(challenge.Token, "default", "deletepet", args))
+}
@@ -0,0 +1,115 @@
+package safety
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"path"
+	"strings"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/secrets"
+)
+
+// Client is an authenticated MCP client and the roles it was granted
+type Client struct {
+	ID    string
+	Roles []string
+}
+
+// Authorizer authenticates inbound clients and decides which tools their roles grant
+type Authorizer struct {
+	clients []inboundClient
+	roles   map[string]config.RoleConfig
+}
+
+// inboundClient is a configured client with its token digest
+type inboundClient struct {
+	client Client
+	digest [sha256.Size]byte
+}
+
+// NewAuthorizer creates an authorizer from the inbound auth and RBAC configuration; only token
+// digests are kept
+func NewAuthorizer(inbound config.InboundConfig, rbac config.RBACConfig) (*Authorizer, error) {
+	a := &Authorizer{roles: make(map[string]config.RoleConfig, len(rbac.Roles))}
+	for _, client := range inbound.Clients {
+		token, err := client.Token.Reveal()
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve token of client %s: %w", client.ID, err)
+		}
+		a.clients = append(a.clients, inboundClient{
+			client: Client{ID: client.ID, Roles: client.Roles},
+			digest: sha256.Sum256(token),
+		})
+		secrets.Wipe(token)
+	}
+	for _, role := range rbac.Roles {
+		a.roles[role.Name] = role
+	}
+	return a, nil
+}
+
+// Authenticate returns the client owning a bearer token; tokens are compared by digest in
+// constant time so neither their content nor length leaks through timing
+func (a *Authorizer) Authenticate(token string) (*Client, bool) {
+	if token == "" {
+		return nil, false
+	}
+	digest := sha256.Sum256([]byte(token))
+
+	var found *Client
+	for i := range a.clients {
+		if subtle.ConstantTimeCompare(digest[:], a.clients[i].digest[:]) == 1 && found == nil {
+			found = &a.clients[i].client
+		}
+	}
+	return found, found != nil
+}
+
+// Allowed reports whether a client may see and call a tool; without configured roles every
+// authenticated client may use every tool
+func (a *Authorizer) Allowed(client *Client, namespace, tool, method string) bool {
+	if len(a.roles) == 0 {
+		return true
+	}
+	if client == nil {
+		return false
+	}
+	for _, name := range client.Roles {
+		role, exists := a.roles[name]
+		if exists && roleGrants(role, namespace, tool, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// roleGrants reports whether a tool satisfies every constraint set on a role
+func roleGrants(role config.RoleConfig, namespace, tool, method string) bool {
+	if len(role.Namespaces) > 0 && !containsFold(role.Namespaces, namespace) {
+		return false
+	}
+	if len(role.Methods) > 0 && !containsFold(role.Methods, method) {
+		return false
+	}
+	if len(role.Tools) > 0 {
+		for _, pattern := range role.Tools {
+			if matched, _ := path.Match(pattern, tool); matched {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// containsFold reports whether values contains s, ignoring case
+func containsFold(values []string, s string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package safety
+
+import (
+	"testing"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/secrets"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAuthorizer(t *testing.T, roles ...config.RoleConfig) *Authorizer {
+	authorizer, err := NewAuthorizer(config.InboundConfig{
+		Enabled: true,
+		Clients: []config.ClientConfig{
+			{ID: "analyst", Token: secrets.New("analyst-token"), Roles: []string{"reader"}},
+			{ID: "ops", Token: secrets.New("ops-token"), Roles: []string{"reader", "pet-admin"}},
+			{ID: "nobody", Token: secrets.New("nobody-token")},
+		},
+	}, config.RBACConfig{Roles: roles})
+	require.NoError(t, err)
+	return authorizer
+}
+
+func TestAuthenticate(t *testing.T) {
+	authorizer := newTestAuthorizer(t)
+
+	client, ok := authorizer.Authenticate("ops-token")
+	require.True(t, ok)
+	assert.Equal(t, "ops", client.ID)
+
+	_, ok = authorizer.Authenticate("ops-token-2")
+	assert.False(t, ok)
+	_, ok = authorizer.Authenticate("")
+	assert.False(t, ok)
+}
+
+func TestAllowed(t *testing.T) {
+	authorizer := newTestAuthorizer(t,
+		config.RoleConfig{Name: "reader", Methods: []string{"GET", "HEAD"}},
+		config.RoleConfig{Name: "pet-admin", Namespaces: []string{"default"}, Tools: []string{"*pet*"}},
+	)
+	analyst, _ := authorizer.Authenticate("analyst-token")
+	ops, _ := authorizer.Authenticate("ops-token")
+	nobody, _ := authorizer.Authenticate("nobody-token")
+
+	assert.True(t, authorizer.Allowed(analyst, "default", "get_pet", "GET"))
+	assert.False(t, authorizer.Allowed(analyst, "default", "delete_pet", "DELETE"))
+
+	assert.True(t, authorizer.Allowed(ops, "default", "delete_pet", "DELETE"))
+	assert.False(t, authorizer.Allowed(ops, "billing", "delete_pet", "DELETE"))
+	assert.False(t, authorizer.Allowed(ops, "default", "delete_order", "DELETE"))
+
+	assert.False(t, authorizer.Allowed(nobody, "default", "get_pet", "GET"))
+	assert.False(t, authorizer.Allowed(nil, "default", "get_pet", "GET"))
+}
+
+func TestAllowed_NoRoles(t *testing.T) {
+	authorizer := newTestAuthorizer(t)
+	nobody, _ := authorizer.Authenticate("nobody-token")
+
+	assert.True(t, authorizer.Allowed(nobody, "default", "delete_pet", "DELETE"))
+}
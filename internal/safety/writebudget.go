@@ -0,0 +1,79 @@
+package safety
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"api-to-mcp/internal/config"
+)
+
+// WriteBudget limits how many mutating calls each session may make within a sliding window
+type WriteBudget struct {
+	max     int
+	window  time.Duration
+	methods []string
+
+	mu       sync.Mutex
+	sessions map[string][]time.Time
+	now      func() time.Time
+}
+
+// NewWriteBudget creates a write budget from configuration
+func NewWriteBudget(cfg config.WriteBudgetConfig) *WriteBudget {
+	return &WriteBudget{
+		max:      cfg.MaxWrites,
+		window:   cfg.Window,
+		methods:  cfg.Methods,
+		sessions: make(map[string][]time.Time),
+		now:      time.Now,
+	}
+}
+
+// Applies reports whether calls with the given method count against the budget
+func (b *WriteBudget) Applies(method string) bool {
+	for _, m := range b.methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// Allow records a write for a session if its budget permits it; otherwise it reports how long
+// until the oldest write in the window expires
+func (b *WriteBudget) Allow(session string) (bool, time.Duration) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.prune(now)
+
+	writes := b.sessions[session]
+	if len(writes) >= b.max {
+		return false, writes[0].Add(b.window).Sub(now)
+	}
+	b.sessions[session] = append(writes, now)
+	return true, 0
+}
+
+// Limit describes the budget
+func (b *WriteBudget) Limit() (int, time.Duration) {
+	return b.max, b.window
+}
+
+// prune drops writes that left the window and sessions without writes; callers hold the lock
+func (b *WriteBudget) prune(now time.Time) {
+	cutoff := now.Add(-b.window)
+	for session, writes := range b.sessions {
+		i := 0
+		for i < len(writes) && !writes[i].After(cutoff) {
+			i++
+		}
+		if i == len(writes) {
+			delete(b.sessions, session)
+		} else if i > 0 {
+			b.sessions[session] = writes[i:]
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package safety
+
+import (
+	"testing"
+	"time"
+
+	"api-to-mcp/internal/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteBudget(t *testing.T) {
+	budget := NewWriteBudget(config.WriteBudgetConfig{
+		MaxWrites: 2,
+		Window:    time.Minute,
+		Methods:   []string{"POST", "PUT", "PATCH", "DELETE"},
+	})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	budget.now = func() time.Time { return now }
+
+	assert.True(t, budget.Applies("delete"))
+	assert.False(t, budget.Applies("GET"))
+
+	allowed, _ := budget.Allow("alice")
+	assert.True(t, allowed)
+	now = now.Add(10 * time.Second)
+	allowed, _ = budget.Allow("alice")
+	assert.True(t, allowed)
+
+	allowed, retryAfter := budget.Allow("alice")
+	assert.False(t, allowed)
+	assert.Equal(t, 50*time.Second, retryAfter)
+
+	// Sessions have independent budgets
+	allowed, _ = budget.Allow("bob")
+	assert.True(t, allowed)
+
+	// The oldest write leaves the window
+	now = now.Add(51 * time.Second)
+	allowed, _ = budget.Allow("alice")
+	assert.True(t, allowed)
+	allowed, _ = budget.Allow("alice")
+	assert.False(t, allowed)
+}
@@ -0,0 +1,86 @@
+package secrets
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// sensitiveParam matches query parameter names that commonly carry credentials
+var sensitiveParam = regexp.MustCompile(`(?i)(key|token|secret|password|passwd|signature|sig|auth|credential|session)`)
+
+// urlPattern finds URLs embedded in error messages
+var urlPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+
+// unescapeMask restores masks percent-encoded by URL serialization
+var unescapeMask = strings.NewReplacer(url.QueryEscape(Masked), Masked)
+
+// ScrubURL masks the userinfo password and credential-like query parameters of a URL
+func ScrubURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	changed := false
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), Masked)
+			changed = true
+		}
+	}
+
+	query := u.Query()
+	maskedQuery := false
+	for name, values := range query {
+		if !sensitiveParam.MatchString(name) {
+			continue
+		}
+		for i := range values {
+			values[i] = Masked
+		}
+		maskedQuery = true
+	}
+	if maskedQuery {
+		u.RawQuery = query.Encode()
+	}
+	if !changed && !maskedQuery {
+		return raw
+	}
+
+	// Keep the mask readable instead of percent-encoded
+	return unescapeMask.Replace(u.String())
+}
+
+// ScrubString masks credentials in every URL found in s
+func ScrubString(s string) string {
+	return urlPattern.ReplaceAllStringFunc(s, ScrubURL)
+}
+
+// scrubbedError reports a scrubbed message while still unwrapping to the original error
+type scrubbedError struct {
+	message string
+	err     error
+}
+
+func (e *scrubbedError) Error() string {
+	return e.message
+}
+
+func (e *scrubbedError) Unwrap() error {
+	return e.err
+}
+
+// ScrubError masks credentials in URLs embedded in an error's message, such as the request URL
+// included in transport errors; errors.Is and errors.As still see the original error
+func ScrubError(err error) error {
+	if err == nil {
+		return nil
+	}
+	message := err.Error()
+	scrubbed := ScrubString(message)
+	if scrubbed == message {
+		return err
+	}
+	return &scrubbedError{message: scrubbed, err: err}
+}
@@ -0,0 +1,125 @@
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/mitchellh/mapstructure"
+)
+
+// Masked is what a Secret prints as
+const Masked = "[REDACTED]"
+
+// Secret is a credential reference resolved only when it is used. The reference is one of
+// env:NAME or ${NAME} (an environment variable), file:/path (a file's trimmed contents) or
+// the literal credential. Printing, logging or marshaling a Secret never shows its value
+type Secret struct {
+	ref string
+}
+
+// New creates a secret from a reference
+func New(ref string) Secret {
+	return Secret{ref: ref}
+}
+
+// IsSet reports whether a reference was configured
+func (s Secret) IsSet() bool {
+	return s.ref != ""
+}
+
+// Reveal resolves the secret into a fresh buffer; callers should Wipe it once the value has
+// been handed on
+func (s Secret) Reveal() ([]byte, error) {
+	switch {
+	case s.ref == "":
+		return nil, nil
+	case strings.HasPrefix(s.ref, "env:"):
+		return fromEnv(strings.TrimPrefix(s.ref, "env:"))
+	case strings.HasPrefix(s.ref, "${") && strings.HasSuffix(s.ref, "}"):
+		return fromEnv(s.ref[2 : len(s.ref)-1])
+	case strings.HasPrefix(s.ref, "file:"):
+		path := strings.TrimPrefix(s.ref, "file:")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret file %s: %w", path, err)
+		}
+		value := bytes.TrimSpace(data)
+		out := make([]byte, len(value))
+		copy(out, value)
+		Wipe(data)
+		return out, nil
+	default:
+		return []byte(s.ref), nil
+	}
+}
+
+// fromEnv reads a secret from an environment variable
+func fromEnv(name string) ([]byte, error) {
+	value, exists := os.LookupEnv(name)
+	if !exists {
+		return nil, fmt.Errorf("secret environment variable %s is not set", name)
+	}
+	return []byte(value), nil
+}
+
+// Source describes where the secret comes from without revealing it
+func (s Secret) Source() string {
+	switch {
+	case s.ref == "":
+		return "unset"
+	case strings.HasPrefix(s.ref, "env:"), strings.HasPrefix(s.ref, "${"):
+		return "environment"
+	case strings.HasPrefix(s.ref, "file:"):
+		return "file"
+	default:
+		return "literal"
+	}
+}
+
+// String masks the secret
+func (s Secret) String() string {
+	if s.ref == "" {
+		return ""
+	}
+	return Masked
+}
+
+// GoString masks the secret for %#v
+func (s Secret) GoString() string {
+	return fmt.Sprintf("secrets.Secret(%q)", s.String())
+}
+
+// Format masks the secret for every formatting verb, including %+v on enclosing structs
+func (s Secret) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, s.GoString())
+		return
+	}
+	fmt.Fprint(f, s.String())
+}
+
+// MarshalText masks the secret in JSON, YAML and other text encodings
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// Wipe overwrites a revealed value
+func Wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
+
+// DecodeHook decodes configuration strings into Secrets
+func DecodeHook() mapstructure.DecodeHookFuncType {
+	secretType := reflect.TypeOf(Secret{})
+	return func(from, to reflect.Type, data interface{}) (interface{}, error) {
+		if to != secretType || from.Kind() != reflect.String {
+			return data, nil
+		}
+		return New(data.(string)), nil
+	}
+}
@@ -0,0 +1,77 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret_Reveal(t *testing.T) {
+	t.Setenv("TEST_SECRET_TOKEN", "from-env")
+	path := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+
+	tests := []struct {
+		ref      string
+		expected string
+		source   string
+	}{
+		{"env:TEST_SECRET_TOKEN", "from-env", "environment"},
+		{"${TEST_SECRET_TOKEN}", "from-env", "environment"},
+		{"file:" + path, "from-file", "file"},
+		{"literal-token", "literal-token", "literal"},
+	}
+	for _, tt := range tests {
+		value, err := New(tt.ref).Reveal()
+		require.NoError(t, err, tt.ref)
+		assert.Equal(t, tt.expected, string(value))
+		assert.Equal(t, tt.source, New(tt.ref).Source())
+	}
+
+	_, err := New("env:TEST_SECRET_MISSING").Reveal()
+	assert.Error(t, err)
+	_, err = New("file:" + filepath.Join(t.TempDir(), "missing")).Reveal()
+	assert.Error(t, err)
+}
+
+func TestSecret_NeverPrinted(t *testing.T) {
+	config := struct {
+		Type  string
+		Token Secret
+	}{"bearer", New("super-secret")}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		assert.NotContains(t, fmt.Sprintf(format, config), "super-secret", format)
+	}
+	data, err := json.Marshal(config)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "super-secret")
+	assert.Equal(t, "", New("").String())
+}
+
+func TestWipe(t *testing.T) {
+	value := []byte("token")
+	Wipe(value)
+	assert.Equal(t, make([]byte, 5), value)
+}
+
+func TestScrubError(t *testing.T) {
+	original := errors.New(`Get "https://user:pw@api.example.com/items?api_key=abc123&limit=5": dial tcp: connection refused`)
+	scrubbed := ScrubError(fmt.Errorf("request failed: %w", original))
+
+	assert.NotContains(t, scrubbed.Error(), "abc123")
+	assert.NotContains(t, scrubbed.Error(), ":pw@")
+	assert.Contains(t, scrubbed.Error(), "api_key=[REDACTED]")
+	assert.Contains(t, scrubbed.Error(), "limit=5")
+	assert.ErrorIs(t, scrubbed, original)
+
+	plain := errors.New("HTTP error 404: not found")
+	assert.Same(t, plain, ScrubError(plain))
+	assert.Nil(t, ScrubError(nil))
+}
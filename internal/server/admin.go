@@ -0,0 +1,64 @@
+package server
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"api-to-mcp/internal/metrics"
+	"api-to-mcp/internal/secrets"
+)
+
+// AdminPathPrefix is the URL prefix of the admin API
+const AdminPathPrefix = "/admin/"
+
+// newAdminHandler creates the admin API handler, requiring the configured bearer token if set
+func newAdminHandler(s *MCPServer) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(AdminPathPrefix+"metrics", func(w http.ResponseWriter, r *http.Request) {
+		summary := make([]metrics.ToolSummary, 0)
+		if s.metrics != nil {
+			summary = s.metrics.Summary()
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"tools": summary})
+	})
+	mux.HandleFunc(AdminPathPrefix+"health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{"upstreams": s.health.Statuses()})
+	})
+	mux.HandleFunc(AdminPathPrefix+"aggregate", func(w http.ResponseWriter, r *http.Request) {
+		if s.aggregate == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "no aggregated upstreams configured"})
+			return
+		}
+		writeJSON(w, http.StatusOK, s.aggregate)
+	})
+
+	token := s.config.Admin.Token
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if token.IsSet() {
+			// The token is resolved per request so rotated files and variables take effect
+			expected, err := token.Reveal()
+			if err != nil {
+				s.logger.WithError(err).Error("Failed to resolve admin token")
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "admin token unavailable"})
+				return
+			}
+			header := append([]byte("Bearer "), expected...)
+			authorized := subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), header) == 1
+			secrets.Wipe(expected)
+			secrets.Wipe(header)
+			if !authorized {
+				writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+				return
+			}
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// writeJSON writes a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,331 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"strings"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/safety"
+	"api-to-mcp/pkg/mcp"
+)
+
+// DocsPath serves the generated tool documentation of the default namespace; tenants are served
+// under DocsPath/<namespace>
+const DocsPath = "/docs"
+
+// isDocsPath reports whether a request path belongs to the tool documentation
+func isDocsPath(path string) bool {
+	return path == DocsPath || strings.HasPrefix(path, DocsPath+"/")
+}
+
+// docsNamespace returns the namespace named by a documentation path, or "" for the default one
+func docsNamespace(path string) string {
+	return strings.Trim(strings.TrimPrefix(path, DocsPath), "/")
+}
+
+// docsPage is the template view of a namespace's documentation
+type docsPage struct {
+	Title      string
+	Version    string
+	Namespace  string
+	Namespaces []docsLink
+	Endpoint   string
+	Tools      []docsTool
+}
+
+// docsLink links to another namespace's documentation
+type docsLink struct {
+	Name    string
+	URL     string
+	Current bool
+}
+
+// docsTool is the template view of a tool
+type docsTool struct {
+	Name         string
+	Description  string
+	Method       string
+	Path         string
+	BaseURL      string
+	ReadOnly     bool
+	Confirmation bool
+	Arguments    []docsArgument
+	Example      string
+}
+
+// docsArgument is a row of a tool's argument table
+type docsArgument struct {
+	Name        string
+	Type        string
+	Required    bool
+	Description string
+	Constraints []string
+}
+
+// newDocsHandler renders the tools a client may see as an HTML page
+func newDocsHandler(service *MCPService, namespaces []string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		page := docsPage{
+			Title:     service.config.MCP.ServerName,
+			Version:   service.config.MCP.Version,
+			Namespace: service.namespace,
+			Endpoint:  "/",
+		}
+		if service.namespace != DefaultNamespace {
+			page.Endpoint = TenantPathPrefix + service.namespace
+		}
+		for _, namespace := range namespaces {
+			url := DocsPath
+			if namespace != DefaultNamespace {
+				url += "/" + namespace
+			}
+			page.Namespaces = append(page.Namespaces, docsLink{Name: namespace, URL: url, Current: namespace == service.namespace})
+		}
+		for _, tool := range service.visibleTools(clientFrom(r.Context())) {
+			page.Tools = append(page.Tools, newDocsTool(tool, service.config))
+		}
+		sort.Slice(page.Tools, func(i, j int) bool {
+			return page.Tools[i].Name < page.Tools[j].Name
+		})
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := docsTemplate.Execute(w, page); err != nil {
+			service.logger.WithError(err).Error("Failed to render tool documentation")
+		}
+	})
+}
+
+// newDocsTool describes a tool for the documentation page
+func newDocsTool(tool mcp.Tool, cfg *config.Config) docsTool {
+	view := docsTool{
+		Name:        tool.Name,
+		Description: tool.Description,
+		ReadOnly:    true,
+	}
+	if tool.Operation != nil {
+		view.Method = tool.Operation.Method
+		view.Path = tool.Operation.Path
+		view.BaseURL = tool.Operation.BaseURL
+		view.ReadOnly = config.IsReadOnlyMethod(tool.Operation.Method)
+		view.Confirmation = cfg.Safety.Confirmation.Enabled &&
+			safety.RequiresConfirmation(cfg.Safety.Confirmation, tool.Operation.Method, tool.Operation.Path)
+	}
+
+	required := make(map[string]bool)
+	arguments := make(map[string]interface{})
+	if tool.InputSchema != nil {
+		for _, name := range tool.InputSchema.Required {
+			required[name] = true
+		}
+		for name, property := range tool.InputSchema.Properties {
+			view.Arguments = append(view.Arguments, newDocsArgument(name, property, required[name]))
+			if required[name] {
+				arguments[name] = exampleValue(property)
+			}
+		}
+	}
+	// Required arguments first, then by name
+	sort.Slice(view.Arguments, func(i, j int) bool {
+		if view.Arguments[i].Required != view.Arguments[j].Required {
+			return view.Arguments[i].Required
+		}
+		return view.Arguments[i].Name < view.Arguments[j].Name
+	})
+
+	example, _ := json.MarshalIndent(mcp.CallToolRequest{
+		JSONRPC: "2.0",
+		Method:  mcp.MethodCallTool,
+		Params:  mcp.CallToolParams{Name: tool.Name, Arguments: arguments},
+		ID:      "1",
+	}, "", "  ")
+	view.Example = string(example)
+	return view
+}
+
+// newDocsArgument describes a property and its constraints
+func newDocsArgument(name string, property mcp.Property, required bool) docsArgument {
+	argument := docsArgument{
+		Name:        name,
+		Type:        property.Type,
+		Required:    required,
+		Description: strings.TrimSpace(property.Description),
+	}
+	if property.Format != "" {
+		argument.Type += " (" + property.Format + ")"
+	}
+	if len(property.Enum) > 0 {
+		argument.Constraints = append(argument.Constraints, "one of: "+strings.Join(property.Enum, ", "))
+	}
+	if property.Default != nil {
+		argument.Constraints = append(argument.Constraints, fmt.Sprintf("default: %v", property.Default))
+	}
+	if property.Minimum != nil {
+		argument.Constraints = append(argument.Constraints, fmt.Sprintf("minimum: %v", *property.Minimum))
+	}
+	if property.Maximum != nil {
+		argument.Constraints = append(argument.Constraints, fmt.Sprintf("maximum: %v", *property.Maximum))
+	}
+	if property.MinLength != nil {
+		argument.Constraints = append(argument.Constraints, fmt.Sprintf("min length: %d", *property.MinLength))
+	}
+	if property.MaxLength != nil {
+		argument.Constraints = append(argument.Constraints, fmt.Sprintf("max length: %d", *property.MaxLength))
+	}
+	if property.Pattern != "" {
+		argument.Constraints = append(argument.Constraints, "pattern: "+property.Pattern)
+	}
+	return argument
+}
+
+// exampleValue returns a plausible value for a property in example calls
+func exampleValue(property mcp.Property) interface{} {
+	switch {
+	case property.Default != nil:
+		return property.Default
+	case len(property.Enum) > 0:
+		return property.Enum[0]
+	}
+
+	switch property.Type {
+	case "integer":
+		if property.Minimum != nil {
+			return int64(*property.Minimum)
+		}
+		return 1
+	case "number":
+		if property.Minimum != nil {
+			return *property.Minimum
+		}
+		return 1.5
+	case "boolean":
+		return true
+	case "array":
+		return []interface{}{}
+	case "object":
+		return map[string]interface{}{}
+	}
+
+	switch property.Format {
+	case "date-time":
+		return "2024-01-01T00:00:00Z"
+	case "date":
+		return "2024-01-01"
+	case "email":
+		return "user@example.com"
+	case "uri", "url":
+		return "https://example.com"
+	case "uuid":
+		return "123e4567-e89b-12d3-a456-426614174000"
+	}
+	return "string"
+}
+
+var docsTemplate = template.Must(template.New("docs").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}} · {{.Namespace}} tools</title>
+<style>
+body { margin: 0; font: 14px/1.5 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1f2328; background: #f6f8fa; }
+header { padding: 16px 24px; background: #1f2328; color: #fff; }
+header h1 { margin: 0; font-size: 20px; }
+header span { color: #9198a1; margin-left: 8px; }
+nav.namespaces { margin-top: 8px; }
+nav.namespaces a { color: #9198a1; margin-right: 12px; text-decoration: none; }
+nav.namespaces a.current { color: #fff; font-weight: 600; }
+.layout { display: flex; align-items: flex-start; }
+aside { position: sticky; top: 0; width: 260px; max-height: 100vh; overflow-y: auto; padding: 16px; box-sizing: border-box; }
+aside input { width: 100%; padding: 6px 8px; box-sizing: border-box; border: 1px solid #d1d9e0; border-radius: 6px; }
+aside ul { list-style: none; padding: 0; margin: 12px 0; }
+aside li a { display: block; padding: 2px 0; color: #0969da; text-decoration: none; overflow-wrap: anywhere; }
+main { flex: 1; min-width: 0; padding: 16px 24px; }
+section.tool { background: #fff; border: 1px solid #d1d9e0; border-radius: 6px; margin-bottom: 16px; padding: 16px; }
+section.tool h2 { margin: 0 0 8px; font-size: 16px; font-family: ui-monospace, SFMono-Regular, Menlo, monospace; overflow-wrap: anywhere; }
+.operation { font-family: ui-monospace, SFMono-Regular, Menlo, monospace; margin-bottom: 8px; overflow-wrap: anywhere; }
+.method { display: inline-block; min-width: 56px; padding: 1px 6px; border-radius: 4px; color: #fff; background: #59636e; text-align: center; font-weight: 600; }
+.method.GET, .method.HEAD { background: #1a7f37; }
+.method.POST { background: #0969da; }
+.method.PUT, .method.PATCH { background: #9a6700; }
+.method.DELETE { background: #cf222e; }
+.badge { display: inline-block; padding: 0 6px; margin-left: 4px; border-radius: 10px; font-size: 12px; border: 1px solid #d1d9e0; }
+.badge.write { border-color: #d4a72c; color: #9a6700; }
+.badge.confirm { border-color: #cf222e; color: #cf222e; }
+.description { white-space: pre-wrap; }
+table { width: 100%; border-collapse: collapse; margin: 8px 0; }
+th, td { text-align: left; vertical-align: top; padding: 6px 8px; border-top: 1px solid #d1d9e0; }
+th { font-weight: 600; }
+td code { overflow-wrap: anywhere; }
+.required { color: #cf222e; font-size: 12px; margin-left: 4px; }
+.constraints { color: #59636e; font-size: 12px; }
+pre { background: #f6f8fa; padding: 12px; border-radius: 6px; overflow-x: auto; margin: 8px 0 0; }
+details summary { cursor: pointer; color: #0969da; }
+.empty { color: #59636e; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}<span>{{.Version}} · {{len .Tools}} tools</span></h1>
+{{- if gt (len .Namespaces) 1}}
+<nav class="namespaces">{{range .Namespaces}}<a href="{{.URL}}"{{if .Current}} class="current"{{end}}>{{.Name}}</a>{{end}}</nav>
+{{- end}}
+</header>
+<div class="layout">
+<aside>
+<input id="filter" type="search" placeholder="Filter tools" aria-label="Filter tools">
+<ul>{{range .Tools}}<li data-tool="{{.Name}}"><a href="#{{.Name}}">{{.Name}}</a></li>{{end}}</ul>
+</aside>
+<main>
+{{- if not .Tools}}
+<p class="empty">No tools are available in this namespace.</p>
+{{- end}}
+{{- range .Tools}}
+<section class="tool" id="{{.Name}}" data-tool="{{.Name}}">
+<h2>{{.Name}}{{if not .ReadOnly}}<span class="badge write">mutating</span>{{end}}{{if .Confirmation}}<span class="badge confirm">requires confirmation</span>{{end}}</h2>
+{{- if .Method}}
+<div class="operation"><span class="method {{.Method}}">{{.Method}}</span> {{.Path}}{{if .BaseURL}} <span class="constraints">on {{.BaseURL}}</span>{{end}}</div>
+{{- end}}
+{{- if .Description}}
+<div class="description">{{.Description}}</div>
+{{- end}}
+{{- if .Arguments}}
+<table>
+<thead><tr><th>Argument</th><th>Type</th><th>Description</th></tr></thead>
+<tbody>
+{{- range .Arguments}}
+<tr><td><code>{{.Name}}</code>{{if .Required}}<span class="required">required</span>{{end}}</td><td>{{.Type}}</td><td>{{.Description}}{{range .Constraints}}<div class="constraints">{{.}}</div>{{end}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- else}}
+<p class="empty">No arguments.</p>
+{{- end}}
+<details>
+<summary>Example call to <code>{{$.Endpoint}}</code></summary>
+<pre>{{.Example}}</pre>
+</details>
+</section>
+{{- end}}
+</main>
+</div>
+<script>
+document.getElementById("filter").addEventListener("input", function (event) {
+  var query = event.target.value.toLowerCase();
+  document.querySelectorAll("[data-tool]").forEach(function (element) {
+    element.style.display = element.dataset.tool.toLowerCase().indexOf(query) === -1 ? "none" : "";
+  });
+});
+</script>
+</body>
+</html>
+`))
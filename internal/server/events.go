@@ -0,0 +1,25 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"api-to-mcp/internal/asyncapi"
+)
+
+// EventsPathPrefix is the URL prefix receiving webhook deliveries for AsyncAPI namespaces, as
+// /events/<namespace>/<channel>
+const EventsPathPrefix = "/events/"
+
+// newEventsHandler routes webhook deliveries to the bridge of their namespace
+func newEventsHandler(bridges map[string]*asyncapi.Bridge) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		namespace, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, EventsPathPrefix), "/")
+		bridge, exists := bridges[namespace]
+		if !exists {
+			http.Error(w, "unknown namespace: "+namespace, http.StatusNotFound)
+			return
+		}
+		http.StripPrefix(EventsPathPrefix+namespace, bridge).ServeHTTP(w, r)
+	})
+}
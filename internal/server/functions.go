@@ -0,0 +1,85 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"api-to-mcp/internal/functions"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FunctionsPathPrefix is the URL prefix of the function-calling shim, as
+// /functions/<format>[/<namespace>]
+const FunctionsPathPrefix = "/functions/"
+
+// maxFunctionCallBytes bounds the size of a tool call request
+const maxFunctionCallBytes = 1 << 20
+
+// functionsPath splits a function-calling shim path into its format and namespace
+func functionsPath(path string) (format, namespace string) {
+	format, namespace, _ = strings.Cut(strings.Trim(strings.TrimPrefix(path, FunctionsPathPrefix), "/"), "/")
+	return format, namespace
+}
+
+// newFunctionsHandler lists tool definitions on GET and executes tool calls on POST in the
+// format named by the path, so agents that do not speak MCP can use the same toolset. Calls go
+// through CallTool and its safety checks
+func newFunctionsHandler(service *MCPService) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, _ := functionsPath(r.URL.Path)
+		format, err := functions.Lookup(name)
+		if err != nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": err.Error()})
+			return
+		}
+
+		tools := service.visibleTools(clientFrom(r.Context()))
+		switch r.Method {
+		case http.MethodGet:
+			definitions, err := format.Tools(tools)
+			if err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+			writeJSON(w, http.StatusOK, map[string]interface{}{"tools": definitions})
+		case http.MethodPost:
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxFunctionCallBytes))
+			if err != nil {
+				writeJSON(w, http.StatusRequestEntityTooLarge, map[string]string{"error": "request too large"})
+				return
+			}
+			calls, err := format.DecodeCalls(body)
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+				return
+			}
+
+			results := make([]functions.Result, 0, len(calls))
+			for _, call := range calls {
+				results = append(results, service.callFunction(r, tools, call))
+			}
+			writeJSON(w, http.StatusOK, format.EncodeResults(results))
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		}
+	})
+}
+
+// callFunction executes a function call as the tool it names
+func (s *MCPService) callFunction(r *http.Request, tools []mcp.Tool, call functions.Call) functions.Result {
+	name := call.Name
+	if tool, found := functions.Resolve(tools, call.Name); found {
+		name = tool.Name
+	}
+
+	var reply mcp.CallToolResponse
+	if err := s.CallTool(r, &mcp.CallToolParams{Name: name, Arguments: call.Arguments}, &reply); err != nil {
+		s.logger.WithError(err).WithFields(logrus.Fields{"function": call.Name}).Error("Function call failed")
+		return functions.NewResult(call, mcp.NewError(mcp.InternalError, err.Error(), nil))
+	}
+	return functions.NewResult(call, reply.Result)
+}
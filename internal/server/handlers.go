@@ -1,10 +1,21 @@
 package server
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
 	"net/http"
+	"time"
 
 	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/metrics"
+	"api-to-mcp/internal/policy"
+	"api-to-mcp/internal/reporting"
+	"api-to-mcp/internal/safety"
+	"api-to-mcp/internal/utils"
+	"api-to-mcp/internal/version"
 	"api-to-mcp/pkg/mcp"
 
 	"github.com/sirupsen/logrus"
@@ -12,39 +23,94 @@ import (
 
 // MCPService handles MCP protocol requests
 type MCPService struct {
-	tools  []mcp.Tool
-	config *config.Config
-	logger *logrus.Logger
+	namespace  string
+	tools      []mcp.Tool
+	resources  mcp.ResourceProvider
+	config     *config.Config
+	logger     *logrus.Logger
+	audit      *logrus.Logger
+	metrics    *metrics.Registry
+	health     *healthMonitor
+	reporter   *reporting.Reporter
+	confirmer  *safety.Confirmer
+	authorizer *safety.Authorizer
+	policy     *policy.Engine
+	writes     *safety.WriteBudget
 }
 
-// NewMCPService creates a new MCP service
-func NewMCPService(tools []mcp.Tool, cfg *config.Config, logger *logrus.Logger) *MCPService {
+// newMCPService creates a new MCP service for a namespace
+func newMCPService(namespace string, set *toolset, cfg *config.Config, svc *services) *MCPService {
 	return &MCPService{
-		tools:  tools,
-		config: cfg,
-		logger: logger,
+		namespace:  namespace,
+		tools:      set.tools,
+		resources:  set.resources(),
+		config:     cfg,
+		logger:     svc.logger,
+		audit:      svc.audit,
+		metrics:    svc.metrics,
+		health:     svc.health,
+		reporter:   svc.reporter,
+		confirmer:  svc.confirmer,
+		authorizer: svc.authorizer,
+		policy:     svc.policy,
+		writes:     svc.writes,
 	}
 }
 
+// Initialize handles the initialize request
+func (s *MCPService) Initialize(r *http.Request, args *mcp.InitializeParams, reply *mcp.InitializeResponse) error {
+	s.logger.WithField("protocol_version", args.ProtocolVersion).Debug("Handling initialize request")
+
+	capabilities := map[string]interface{}{
+		"tools": map[string]interface{}{},
+	}
+	if s.resources != nil {
+		capabilities["resources"] = map[string]interface{}{"subscribe": true}
+	}
+
+	reply.JSONRPC = "2.0"
+	reply.Result = mcp.InitializeResult{
+		ProtocolVersion: mcp.ProtocolVersion,
+		Capabilities:    capabilities,
+		ServerInfo:      serverInfo(s.config),
+	}
+	reply.ID = "1" // TODO: Extract ID from request
+
+	return nil
+}
+
 // ListTools handles the tools/list request
 func (s *MCPService) ListTools(r *http.Request, args *struct{}, reply *mcp.ListToolsResponse) error {
 	s.logger.Debug("Handling tools/list request")
 
 	// Create response
 	reply.JSONRPC = "2.0"
-	reply.Result.Tools = s.tools
+	reply.Result.Tools = s.visibleTools(clientFrom(r.Context()))
+	if s.config.Health.DegradedDescriptions && !s.health.Healthy(s.namespace) {
+		reply.Result.Tools = degradedTools(reply.Result.Tools)
+	}
 	reply.ID = "1" // TODO: Extract ID from request
 
-	s.logger.WithField("tool_count", len(s.tools)).Info("Listed available tools")
+	s.logger.WithField("tool_count", len(reply.Result.Tools)).Info("Listed available tools")
 	return nil
 }
 
 // CallTool handles the tools/call request
 func (s *MCPService) CallTool(r *http.Request, args *mcp.CallToolParams, reply *mcp.CallToolResponse) error {
-	s.logger.WithFields(logrus.Fields{
-		"tool_name": args.Name,
-		"arguments": args.Arguments,
-	}).Debug("Handling tools/call request")
+	requestID := s.correlationID(r, args)
+	client := clientFrom(r.Context())
+	logger := s.logger.WithFields(logrus.Fields{
+		"tool_name":      args.Name,
+		"correlation_id": requestID,
+	})
+	if client != nil {
+		logger = logger.WithField("client", client.ID)
+	}
+	logger.WithField("arguments", args.Arguments).Debug("Handling tools/call request")
+
+	reply.JSONRPC = "2.0"
+	reply.ID = "1" // TODO: Extract ID from request
+	reply.Meta = map[string]interface{}{mcp.MetaCorrelationID: requestID}
 
 	// Find the tool
 	var tool *mcp.Tool
@@ -56,27 +122,336 @@ func (s *MCPService) CallTool(r *http.Request, args *mcp.CallToolParams, reply *
 	}
 
 	if tool == nil {
-		reply.JSONRPC = "2.0"
 		reply.Result = mcp.NewError(mcp.MethodNotFound, fmt.Sprintf("Tool not found: %s", args.Name), nil)
-		reply.ID = "1" // TODO: Extract ID from request
 		return nil
 	}
 
+	// Roles decide which tools an authenticated client may call
+	if !s.authorized(client, tool) {
+		logger.Warn("Refused tool call not granted to client")
+		reply.Result = mcp.NewError(mcp.PolicyDenied, fmt.Sprintf("Tool %s is not authorized for client %s", tool.Name, client.ID), nil)
+		return nil
+	}
+
+	// Read-only mode refuses mutating tools even if they were registered
+	if s.config.Safety.ReadOnly && tool.Operation != nil && !config.IsReadOnlyMethod(tool.Operation.Method) {
+		logger.Warn("Refused mutating tool in read-only mode")
+		reply.Result = mcp.NewError(mcp.PolicyDenied, fmt.Sprintf("Tool %s is not allowed in read-only mode", tool.Name), nil)
+		return nil
+	}
+
+	// Policy rules may deny the call or rewrite its arguments
+	if s.policy != nil {
+		decision := s.policy.Evaluate(s.policyInput(client, tool, args.Arguments))
+		if !decision.Allowed {
+			logger.WithFields(logrus.Fields{"policy": decision.Rule, "reason": decision.Reason}).Warn("Tool call denied by policy")
+			reply.Result = mcp.NewError(mcp.PolicyDenied, decision.Reason, map[string]interface{}{"policy": decision.Rule})
+			return nil
+		}
+		if len(decision.Rewritten) > 0 {
+			logger.WithField("rewritten", decision.Rewritten).Info("Policy rewrote tool arguments")
+		}
+		args.Arguments = decision.Arguments
+	}
+
+	// Gated tools only run when repeated with the token issued by a challenge
+	if s.confirmer != nil && tool.Operation != nil &&
+		safety.RequiresConfirmation(s.config.Safety.Confirmation, tool.Operation.Method, tool.Operation.Path) {
+		token, _ := args.Arguments[safety.ConfirmArgument].(string)
+		delete(args.Arguments, safety.ConfirmArgument)
+		if token == "" || !s.confirmer.Confirm(token, s.namespace, tool.Name, args.Arguments) {
+			if token != "" {
+				logger.Warn("Rejected invalid or expired confirmation token")
+			}
+			reply.Result = s.confirmer.Challenge(s.namespace, tool.Name, describeAction(tool, args.Arguments), args.Arguments)
+			logger.Info("Issued confirmation challenge")
+			return nil
+		}
+		logger.Info("Confirmed gated tool call")
+	}
+
+	// Mutating calls draw on the session's write budget
+	if s.writes != nil && tool.Operation != nil && s.writes.Applies(tool.Operation.Method) {
+		if allowed, retryAfter := s.writes.Allow(sessionKey(r, client, s.config.Safety.WriteBudget.SessionHeader)); !allowed {
+			maxWrites, window := s.writes.Limit()
+			logger.WithField("retry_after_ms", retryAfter.Milliseconds()).Warn("Write budget exhausted")
+			reply.Result = mcp.NewError(mcp.PolicyDenied,
+				fmt.Sprintf("Write budget exhausted: at most %d writes per %s, retry in %s", maxWrites, window, retryAfter.Round(time.Second)),
+				map[string]interface{}{
+					"maxWrites":         maxWrites,
+					"window":            window.String(),
+					"retryAfterSeconds": int(math.Ceil(retryAfter.Seconds())),
+				})
+			return nil
+		}
+	}
+
+	// Handlers may consume their arguments, so the audit log keeps a copy
+	var arguments map[string]interface{}
+	if s.audit != nil {
+		arguments = make(map[string]interface{}, len(args.Arguments))
+		for key, value := range args.Arguments {
+			arguments[key] = value
+		}
+	}
+
 	// Execute the tool
-	result, err := tool.Handler(args.Arguments)
+	ctx := utils.WithToolName(utils.WithRequestID(r.Context(), requestID), tool.Name)
+	start := time.Now()
+	result, err := tool.Handler(ctx, args.Arguments)
+	elapsed := time.Since(start)
+	s.metrics.ObserveCall(s.namespace, tool.Name, elapsed, err)
+	s.auditCall(ctx, tool.Name, requestID, arguments, elapsed, err)
 	if err != nil {
-		s.logger.WithError(err).Error("Tool execution failed")
-		reply.JSONRPC = "2.0"
+		var httpErr *utils.HTTPError
+		if errors.As(err, &httpErr) {
+			s.reporter.ObserveUpstreamStatus(tool.Name, httpErr.StatusCode, map[string]string{
+				"namespace":      s.namespace,
+				"correlation_id": requestID,
+			})
+		}
+		logger.WithError(err).Error("Tool execution failed")
 		reply.Result = mcp.NewError(mcp.InternalError, fmt.Sprintf("Tool execution failed: %v", err), nil)
-		reply.ID = "1" // TODO: Extract ID from request
 		return nil
 	}
 
 	// Return success response
-	reply.JSONRPC = "2.0"
 	reply.Result = result
+
+	logger.Info("Tool executed successfully")
+	return nil
+}
+
+// ListResources handles the resources/list request
+func (s *MCPService) ListResources(r *http.Request, args *struct{}, reply *mcp.ListResourcesResponse) error {
+	s.logger.Debug("Handling resources/list request")
+
+	reply.JSONRPC = "2.0"
+	reply.Result.Resources = make([]mcp.Resource, 0)
+	if s.resources != nil {
+		client := clientFrom(r.Context())
+		for _, resource := range s.resources.Resources() {
+			if s.resourceAuthorized(client, resource) {
+				reply.Result.Resources = append(reply.Result.Resources, resource)
+			}
+		}
+	}
+	reply.ID = "1" // TODO: Extract ID from request
+
+	s.logger.WithField("resource_count", len(reply.Result.Resources)).Info("Listed available resources")
+	return nil
+}
+
+// ReadResource handles the resources/read request
+func (s *MCPService) ReadResource(r *http.Request, args *mcp.ResourceParams, reply *mcp.ReadResourceResponse) error {
+	s.logger.WithField("uri", args.URI).Debug("Handling resources/read request")
+
+	if _, err := s.resource(r, args.URI); err != nil {
+		return err
+	}
+	contents, err := s.resources.ReadResource(r.Context(), args.URI)
+	if err != nil {
+		s.logger.WithError(err).WithField("uri", args.URI).Error("Resource read failed")
+		return err
+	}
+
+	reply.JSONRPC = "2.0"
+	reply.Result.Contents = []mcp.ResourceContents{*contents}
+	reply.ID = "1" // TODO: Extract ID from request
+	return nil
+}
+
+// SubscribeResource handles the resources/subscribe request
+func (s *MCPService) SubscribeResource(r *http.Request, args *mcp.ResourceParams, reply *mcp.EmptyResponse) error {
+	s.logger.WithField("uri", args.URI).Debug("Handling resources/subscribe request")
+
+	if _, err := s.resource(r, args.URI); err != nil {
+		return err
+	}
+	if err := s.resources.Subscribe(r.Context(), args.URI); err != nil {
+		s.logger.WithError(err).WithField("uri", args.URI).Error("Resource subscription failed")
+		return err
+	}
+
+	reply.JSONRPC = "2.0"
 	reply.ID = "1" // TODO: Extract ID from request
+	return nil
+}
+
+// UnsubscribeResource handles the resources/unsubscribe request
+func (s *MCPService) UnsubscribeResource(r *http.Request, args *mcp.ResourceParams, reply *mcp.EmptyResponse) error {
+	s.logger.WithField("uri", args.URI).Debug("Handling resources/unsubscribe request")
+
+	if _, err := s.resource(r, args.URI); err != nil {
+		return err
+	}
+	if err := s.resources.Unsubscribe(args.URI); err != nil {
+		return err
+	}
 
-	s.logger.WithField("tool_name", args.Name).Info("Tool executed successfully")
+	reply.JSONRPC = "2.0"
+	reply.ID = "1" // TODO: Extract ID from request
 	return nil
 }
+
+// resource finds a resource the calling client may access
+func (s *MCPService) resource(r *http.Request, uri string) (*mcp.Resource, error) {
+	if s.resources != nil {
+		for _, resource := range s.resources.Resources() {
+			if resource.URI != uri {
+				continue
+			}
+			if !s.resourceAuthorized(clientFrom(r.Context()), resource) {
+				break
+			}
+			return &resource, nil
+		}
+	}
+	return nil, fmt.Errorf("resource not found: %s", uri)
+}
+
+// resourceAuthorized reports whether a client's roles grant a resource, which is matched by name
+// like a read-only tool
+func (s *MCPService) resourceAuthorized(client *safety.Client, resource mcp.Resource) bool {
+	if s.authorizer == nil {
+		return true
+	}
+	return s.authorizer.Allowed(client, s.namespace, resource.Name, http.MethodGet)
+}
+
+// visibleTools returns the tools a client may see; everything is visible without inbound auth
+func (s *MCPService) visibleTools(client *safety.Client) []mcp.Tool {
+	if s.authorizer == nil {
+		return s.tools
+	}
+	visible := make([]mcp.Tool, 0, len(s.tools))
+	for i := range s.tools {
+		if s.authorized(client, &s.tools[i]) {
+			visible = append(visible, s.tools[i])
+		}
+	}
+	return visible
+}
+
+// authorized reports whether a client's roles grant a tool
+func (s *MCPService) authorized(client *safety.Client, tool *mcp.Tool) bool {
+	if s.authorizer == nil {
+		return true
+	}
+	method := ""
+	if tool.Operation != nil {
+		method = tool.Operation.Method
+	}
+	return s.authorizer.Allowed(client, s.namespace, tool.Name, method)
+}
+
+// policyInput describes a call to the policy engine
+func (s *MCPService) policyInput(client *safety.Client, tool *mcp.Tool, arguments map[string]interface{}) policy.Input {
+	input := policy.Input{
+		Namespace: s.namespace,
+		Tool:      tool.Name,
+		Arguments: arguments,
+	}
+	if tool.Operation != nil {
+		input.Method = tool.Operation.Method
+		input.Path = tool.Operation.Path
+	}
+	if client != nil {
+		input.ClientID = client.ID
+		input.ClientRoles = client.Roles
+	}
+	return input
+}
+
+// describeAction summarizes the upstream operation a tool call would perform
+func describeAction(tool *mcp.Tool, arguments map[string]interface{}) string {
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		data = []byte("{}")
+	}
+	return fmt.Sprintf("%s %s (tool %s) with arguments %s", tool.Operation.Method, tool.Operation.Path, tool.Name, data)
+}
+
+// auditCall records a tool call in the audit log, if configured
+func (s *MCPService) auditCall(ctx context.Context, tool, requestID string, arguments map[string]interface{}, elapsed time.Duration, err error) {
+	if s.audit == nil {
+		return
+	}
+
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	entry := s.audit.WithFields(logrus.Fields{
+		"namespace":      s.namespace,
+		"tool_name":      tool,
+		"correlation_id": requestID,
+		"arguments":      arguments,
+		"outcome":        outcome,
+		"duration_ms":    elapsed.Milliseconds(),
+	})
+	if id := clientID(ctx); id != "" {
+		entry = entry.WithField("client", id)
+	}
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	entry.Info("Tool call")
+}
+
+// degradedTools returns copies of the tools with their descriptions flagged as unavailable
+func degradedTools(tools []mcp.Tool) []mcp.Tool {
+	degraded := make([]mcp.Tool, len(tools))
+	for i, tool := range tools {
+		tool.Description = DegradedNotice + tool.Description
+		degraded[i] = tool
+	}
+	return degraded
+}
+
+// correlationID returns the caller-supplied correlation ID from the configured header or
+// the request _meta, generating one when absent
+func (s *MCPService) correlationID(r *http.Request, args *mcp.CallToolParams) string {
+	if header := s.config.Observability.CorrelationHeader; header != "" {
+		if id := r.Header.Get(header); id != "" {
+			return id
+		}
+	}
+	if id, ok := args.Meta[mcp.MetaCorrelationID].(string); ok && id != "" {
+		return id
+	}
+	return utils.NewRequestID()
+}
+
+// serverInfo describes the server and its build identity
+func serverInfo(cfg *config.Config) mcp.ServerInfo {
+	build := version.Get()
+	return mcp.ServerInfo{
+		Name:    cfg.MCP.ServerName,
+		Version: cfg.MCP.Version,
+		Build: &mcp.BuildInfo{
+			Version:   build.Version,
+			Commit:    build.Commit,
+			BuildDate: build.BuildDate,
+			GoVersion: build.GoVersion,
+		},
+	}
+}
+
+// versionHandler serves the build information as JSON
+func versionHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(version.Get()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// metricsHandler serves the tool call metrics in the Prometheus text format
+func metricsHandler(registry *metrics.Registry) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := registry.WritePrometheus(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/generator"
+	"api-to-mcp/internal/utils"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DegradedNotice prefixes tool descriptions while their upstream API is unavailable
+const DegradedNotice = "[Upstream unavailable] "
+
+// UpstreamHealth is the last probe result for a namespace's upstream API
+type UpstreamHealth struct {
+	Namespace  string    `json:"namespace"`
+	BaseURL    string    `json:"base_url"`
+	Healthy    bool      `json:"healthy"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// healthTarget is an upstream API probed on behalf of a namespace
+type healthTarget struct {
+	namespace string
+	baseURL   string
+	method    string
+	path      string
+	client    *utils.HTTPClient
+}
+
+// healthMonitor periodically probes upstream APIs and keeps their latest status
+type healthMonitor struct {
+	interval time.Duration
+	targets  []healthTarget
+	logger   *logrus.Logger
+
+	mu     sync.RWMutex
+	status map[string]UpstreamHealth
+}
+
+// defaultHealthInterval is used when no positive probe interval is configured
+const defaultHealthInterval = 30 * time.Second
+
+// newHealthMonitor creates an empty health monitor
+func newHealthMonitor(cfg config.HealthConfig, logger *logrus.Logger) *healthMonitor {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	return &healthMonitor{
+		interval: interval,
+		logger:   logger,
+		status:   make(map[string]UpstreamHealth),
+	}
+}
+
+// addTarget registers the upstream API of a namespace, probing probe_path when set and HEAD / otherwise
+func (m *healthMonitor) addTarget(namespace string, cfg *config.Config, tools []mcp.Tool) {
+	baseURL := cfg.OpenAPI.BaseURL
+	if baseURL == "" && len(tools) > 0 && tools[0].Operation != nil {
+		baseURL = tools[0].Operation.BaseURL
+	}
+	if baseURL == "" {
+		m.logger.WithField("namespace", namespace).Warn("No base URL to probe, skipping health checks")
+		return
+	}
+
+	outbound, err := generator.NewOutboundPolicy(cfg)
+	if err != nil {
+		m.logger.WithError(err).WithField("namespace", namespace).Warn("Invalid outbound policy, skipping health checks")
+		return
+	}
+
+	client := utils.NewHTTPClient(baseURL, m.logger)
+	client.SetTimeout(cfg.Health.Timeout)
+	client.SetOutboundPolicy(outbound)
+
+	target := healthTarget{
+		namespace: namespace,
+		baseURL:   baseURL,
+		method:    http.MethodHead,
+		path:      "/",
+		client:    client,
+	}
+	if cfg.OpenAPI.ProbePath != "" {
+		target.method = http.MethodGet
+		target.path = cfg.OpenAPI.ProbePath
+		if cfg.Auth.Type != "" {
+			client.SetAuth(cfg.Auth.Type, cfg.Auth.Token)
+		}
+	}
+
+	m.targets = append(m.targets, target)
+	m.status[namespace] = UpstreamHealth{Namespace: namespace, BaseURL: baseURL}
+}
+
+// Run probes all targets immediately and then every interval until the context is cancelled
+func (m *healthMonitor) Run(ctx context.Context) {
+	m.probeAll()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.probeAll()
+		}
+	}
+}
+
+// probeAll probes every target concurrently
+func (m *healthMonitor) probeAll() {
+	var wg sync.WaitGroup
+	for _, target := range m.targets {
+		wg.Add(1)
+		go func(target healthTarget) {
+			defer wg.Done()
+			m.probe(target)
+		}(target)
+	}
+	wg.Wait()
+}
+
+// probe checks a single target and records the result, logging status transitions
+func (m *healthMonitor) probe(target healthTarget) {
+	result := UpstreamHealth{
+		Namespace: target.namespace,
+		BaseURL:   target.baseURL,
+		CheckedAt: time.Now().UTC(),
+	}
+
+	status, err := target.client.Probe(target.method, target.path)
+	result.StatusCode = status
+	switch {
+	case err != nil:
+		result.Error = err.Error()
+	case status >= http.StatusInternalServerError:
+		result.Error = fmt.Sprintf("%s %s returned HTTP %d", target.method, target.path, status)
+	case target.method == http.MethodGet && status >= http.StatusBadRequest:
+		result.Error = fmt.Sprintf("%s %s returned HTTP %d", target.method, target.path, status)
+	default:
+		// Any non-5xx answer to HEAD / proves the upstream is reachable
+		result.Healthy = true
+	}
+
+	m.mu.Lock()
+	previous := m.status[target.namespace]
+	m.status[target.namespace] = result
+	m.mu.Unlock()
+
+	if previous.CheckedAt.IsZero() || previous.Healthy != result.Healthy {
+		entry := m.logger.WithFields(logrus.Fields{
+			"namespace":   target.namespace,
+			"base_url":    target.baseURL,
+			"status_code": status,
+		})
+		if result.Healthy {
+			entry.Info("Upstream API is healthy")
+		} else {
+			entry.WithField("error", result.Error).Warn("Upstream API is unavailable")
+		}
+	}
+}
+
+// Healthy reports whether a namespace's upstream passed its last probe; unmonitored and
+// not yet probed namespaces are considered healthy
+func (m *healthMonitor) Healthy(namespace string) bool {
+	if m == nil {
+		return true
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	status, exists := m.status[namespace]
+	return !exists || status.CheckedAt.IsZero() || status.Healthy
+}
+
+// Statuses returns the latest probe result of every target, sorted by namespace
+func (m *healthMonitor) Statuses() []UpstreamHealth {
+	statuses := make([]UpstreamHealth, 0)
+	if m == nil {
+		return statuses
+	}
+	m.mu.RLock()
+	for _, status := range m.status {
+		statuses = append(statuses, status)
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Namespace < statuses[j].Namespace
+	})
+	return statuses
+}
+
+// readyHandler reports 200 when every monitored upstream is healthy and 503 otherwise
+func readyHandler(monitor *healthMonitor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statuses := monitor.Statuses()
+		ready := true
+		for _, status := range statuses {
+			if !status.Healthy {
+				ready = false
+				break
+			}
+		}
+
+		code, state := http.StatusOK, "ready"
+		if !ready {
+			code, state = http.StatusServiceUnavailable, "degraded"
+		}
+		writeJSON(w, code, map[string]interface{}{
+			"status":    state,
+			"upstreams": statuses,
+		})
+	})
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"api-to-mcp/internal/safety"
+
+	"github.com/sirupsen/logrus"
+)
+
+// clientKey stores the authenticated client in request contexts
+type clientKey struct{}
+
+// withClient returns a context carrying the authenticated client
+func withClient(ctx context.Context, client *safety.Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// clientFrom returns the authenticated client carried by a context, if any
+func clientFrom(ctx context.Context) *safety.Client {
+	client, _ := ctx.Value(clientKey{}).(*safety.Client)
+	return client
+}
+
+// clientID returns the ID of the authenticated client, or an empty string without inbound auth
+func clientID(ctx context.Context) string {
+	if client := clientFrom(ctx); client != nil {
+		return client.ID
+	}
+	return ""
+}
+
+// sessionKey identifies the session a request belongs to: the authenticated client, else the
+// session header, else the remote host
+func sessionKey(r *http.Request, client *safety.Client, header string) string {
+	if client != nil {
+		return "client:" + client.ID
+	}
+	if header != "" {
+		if id := r.Header.Get(header); id != "" {
+			return "session:" + id
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "addr:" + host
+}
+
+// newInboundAuthHandler requires a known bearer token on every request and records its client
+func newInboundAuthHandler(next http.Handler, authorizer *safety.Authorizer, logger *logrus.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+		client, ok := authorizer.Authenticate(token)
+		if !ok {
+			logger.WithFields(logrus.Fields{
+				"path":        r.URL.Path,
+				"remote_addr": r.RemoteAddr,
+			}).Warn("Rejected unauthenticated MCP request")
+			w.Header().Set("WWW-Authenticate", `Bearer realm="api-to-mcp"`)
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "missing or invalid bearer token"})
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withClient(r.Context(), client)))
+	})
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"api-to-mcp/internal/config"
+)
+
+// rateLimiter is a token bucket limiter
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newRateLimiter creates a token bucket from the rate limit configuration
+func newRateLimiter(cfg config.RateLimitConfig) *rateLimiter {
+	burst := float64(cfg.Burst)
+	if burst < 1 {
+		burst = cfg.RequestsPerSecond
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   cfg.RequestsPerSecond,
+		burst:  burst,
+		tokens: burst,
+		last:   time.Now(),
+	}
+}
+
+// Allow reports whether a request may proceed, consuming a token if so
+func (l *rateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// newRateLimitedHandler wraps a handler with a token bucket limiter
+func newRateLimitedHandler(next http.Handler, cfg config.RateLimitConfig) http.Handler {
+	limiter := newRateLimiter(cfg)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.Allow() {
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
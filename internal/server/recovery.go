@@ -0,0 +1,34 @@
+package server
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"api-to-mcp/internal/reporting"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newRecoveryHandler recovers from handler panics, reporting them and answering 500
+func newRecoveryHandler(next http.Handler, reporter *reporting.Reporter, logger *logrus.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			value := recover()
+			if value == nil {
+				return
+			}
+			if value == http.ErrAbortHandler {
+				panic(value)
+			}
+
+			stack := debug.Stack()
+			logger.WithFields(logrus.Fields{
+				"panic": value,
+				"path":  r.URL.Path,
+			}).Error("Recovered from handler panic")
+			reporter.CapturePanic(value, stack, map[string]string{"path": r.URL.Path})
+			http.Error(w, "internal server error", http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -3,12 +3,26 @@ package server
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 
+	"api-to-mcp/internal/aggregate"
+	"api-to-mcp/internal/asyncapi"
 	"api-to-mcp/internal/config"
 	"api-to-mcp/internal/generator"
+	"api-to-mcp/internal/graphql"
+	"api-to-mcp/internal/grpcapi"
+	"api-to-mcp/internal/logging"
+	"api-to-mcp/internal/manifest"
+	"api-to-mcp/internal/metrics"
 	"api-to-mcp/internal/parser"
+	"api-to-mcp/internal/policy"
+	"api-to-mcp/internal/reporting"
+	"api-to-mcp/internal/safety"
+	"api-to-mcp/internal/soap"
+	"api-to-mcp/internal/version"
 	"api-to-mcp/pkg/mcp"
 
 	"github.com/gorilla/rpc"
@@ -18,66 +32,445 @@ import (
 
 // MCPServer represents the MCP server
 type MCPServer struct {
-	config *config.Config
-	tools  []mcp.Tool
-	server *http.Server
-	logger *logrus.Logger
+	config   *config.Config
+	tools    []mcp.Tool
+	tenants  map[string]*Tenant
+	metrics  *metrics.Registry
+	health   *healthMonitor
+	reporter *reporting.Reporter
+	loggers  *logging.Loggers
+	server   *http.Server
+	logger   *logrus.Logger
+	// events holds the AsyncAPI bridges of namespaces with event-driven upstreams
+	events map[string]*asyncapi.Bridge
+	// aggregate describes the upstreams merged into the default namespace, when configured
+	aggregate *aggregate.Report
 }
 
+// toolset is what a namespace serves
+type toolset struct {
+	tools []mcp.Tool
+	// events is set for AsyncAPI upstreams, whose subscribe channels are served as resources
+	events *asyncapi.Bridge
+	// upstreams are the toolsets merged into an aggregated namespace, by upstream name
+	upstreams map[string]*toolset
+	// merged serves the resources of an aggregated namespace's upstreams
+	merged mcp.ResourceProvider
+	// report describes the merge of an aggregated namespace
+	report *aggregate.Report
+}
+
+// resources returns the toolset's resource provider, or nil when it serves none
+func (t *toolset) resources() mcp.ResourceProvider {
+	if t.merged != nil {
+		return t.merged
+	}
+	if t.events == nil {
+		return nil
+	}
+	return t.events
+}
+
+// services are the shared components handed to every namespace's MCP service
+type services struct {
+	logger     *logrus.Logger
+	audit      *logrus.Logger
+	metrics    *metrics.Registry
+	health     *healthMonitor
+	reporter   *reporting.Reporter
+	confirmer  *safety.Confirmer
+	authorizer *safety.Authorizer
+	policy     *policy.Engine
+	writes     *safety.WriteBudget
+	// namespaces lists the default and tenant namespaces, for documentation links
+	namespaces []string
+}
+
+// reportFlushTimeout bounds how long pending error reports may delay exit
+const reportFlushTimeout = 5 * time.Second
+
+// DefaultNamespace labels the toolset built from the top-level openapi configuration
+const DefaultNamespace = "default"
+
 // NewMCPServer creates a new MCP server
 func NewMCPServer(cfg *config.Config) (*MCPServer, error) {
-	logger := logrus.New()
-	logger.SetLevel(logrus.InfoLevel)
-	if cfg.Logging.Format == "json" {
-		logger.SetFormatter(&logrus.JSONFormatter{})
+	loggers, err := logging.NewLoggers(cfg.Logging)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize logging: %w", err)
+	}
+	logger := loggers.App
+
+	// Per-tool call metrics
+	var registry *metrics.Registry
+	if cfg.Metrics.Enabled {
+		registry = metrics.NewRegistry(cfg.Metrics.MaxTools)
+	}
+
+	// Error reporting
+	reporter, err := newReporter(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	// Upstream availability probes
+	var monitor *healthMonitor
+	if cfg.Health.Enabled {
+		monitor = newHealthMonitor(cfg.Health, logger)
+	}
+
+	svc := &services{
+		logger:     logger,
+		audit:      loggers.Audit,
+		metrics:    registry,
+		health:     monitor,
+		reporter:   reporter,
+		namespaces: []string{DefaultNamespace},
+	}
+	for _, tenant := range cfg.Tenants {
+		svc.namespaces = append(svc.namespaces, tenant.Name)
+	}
+	if cfg.Safety.Confirmation.Enabled {
+		svc.confirmer = safety.NewConfirmer(cfg.Safety.Confirmation.TTL)
+	}
+	if cfg.Inbound.Enabled {
+		svc.authorizer, err = safety.NewAuthorizer(cfg.Inbound, cfg.RBAC)
+		if err != nil {
+			loggers.Close()
+			return nil, fmt.Errorf("failed to initialize inbound auth: %w", err)
+		}
+	}
+	if cfg.Safety.WriteBudget.Enabled {
+		svc.writes = safety.NewWriteBudget(cfg.Safety.WriteBudget)
+	}
+	if cfg.Safety.Policy.Enabled {
+		svc.policy, err = policy.NewEngine(cfg.Safety.Policy)
+		if err != nil {
+			loggers.Close()
+			return nil, fmt.Errorf("failed to compile policy: %w", err)
+		}
+	}
+
+	// Build the default toolset
+	set, err := buildToolset(DefaultNamespace, cfg, logger, reporter)
+	if err != nil {
+		reporter.Flush(reportFlushTimeout)
+		loggers.Close()
+		return nil, err
+	}
+	tools := set.tools
+	if err := manifest.VerifyToolset(cfg.OpenAPI.Manifest, tools); err != nil {
+		reporter.Flush(reportFlushTimeout)
+		loggers.Close()
+		return nil, fmt.Errorf("refusing to serve unverified toolset: %w", err)
+	}
+	if cfg.OpenAPI.Manifest.Path != "" {
+		logger.WithField("manifest", cfg.OpenAPI.Manifest.Path).Info("Verified toolset against signed manifest")
+	}
+	if monitor != nil && set.report == nil {
+		monitor.addTarget(DefaultNamespace, cfg, tools)
+	}
+
+	events := make(map[string]*asyncapi.Bridge)
+	if set.events != nil {
+		events[DefaultNamespace] = set.events
+	}
+
+	// Aggregated upstreams are probed and receive webhooks under their own names
+	for _, upstream := range cfg.Aggregate {
+		merged := set.upstreams[upstream.Name]
+		if monitor != nil {
+			monitor.addTarget(upstream.Name, cfg.ForUpstream(upstream), merged.tools)
+		}
+		if merged.events != nil {
+			events[upstream.Name] = merged.events
+		}
+	}
+
+	// Build tenant namespaces
+	tenants := make(map[string]*Tenant, len(cfg.Tenants))
+	for _, tenantCfg := range cfg.Tenants {
+		tenant, err := newTenant(tenantCfg, cfg, svc)
+		if err != nil {
+			reporter.Flush(reportFlushTimeout)
+			loggers.Close()
+			return nil, fmt.Errorf("failed to initialize tenant %s: %w", tenantCfg.Name, err)
+		}
+		tenants[tenant.Name] = tenant
+		if tenant.events != nil {
+			events[tenant.Name] = tenant.events
+		}
+	}
+
+	s := &MCPServer{
+		config:    cfg,
+		tools:     tools,
+		tenants:   tenants,
+		metrics:   registry,
+		health:    monitor,
+		reporter:  reporter,
+		loggers:   loggers,
+		logger:    logger,
+		events:    events,
+		aggregate: set.report,
+	}
+
+	// Route default and namespaced requests
+	mux := http.NewServeMux()
+	mux.HandleFunc("/version", versionHandler)
+	mux.Handle("/readyz", readyHandler(monitor))
+	if registry != nil {
+		mux.Handle("/metrics", metricsHandler(registry))
+	}
+	if cfg.Admin.Enabled {
+		mux.Handle(AdminPathPrefix, newAdminHandler(s))
+	}
+	if len(events) > 0 {
+		mux.Handle(EventsPathPrefix, newEventsHandler(events))
+	}
+	var rpc http.Handler = newNamespaceRouter(newNamespaceHandler(DefaultNamespace, set, cfg, svc), tenants)
+	if svc.authorizer != nil {
+		rpc = newInboundAuthHandler(rpc, svc.authorizer, logger)
+	}
+	mux.Handle("/", rpc)
+
+	// Create HTTP server
+	s.server = &http.Server{
+		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
+		Handler:      newAccessLogHandler(newRecoveryHandler(mux, reporter, logger), loggers.Access),
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	}
+
+	return s, nil
+}
+
+// BuildTools parses the configured OpenAPI specification or AsyncAPI document, or loads the
+// GraphQL schema or gRPC descriptors, and generates its MCP tools
+func BuildTools(cfg *config.Config, logger *logrus.Logger) ([]mcp.Tool, error) {
+	set, err := buildToolset(DefaultNamespace, cfg, logger, nil)
+	if err != nil {
+		return nil, err
+	}
+	return set.tools, nil
+}
+
+// BuildAggregateReport generates the configured aggregated upstreams and reports how their
+// tools and resources merge
+func BuildAggregateReport(cfg *config.Config, logger *logrus.Logger) (*aggregate.Report, error) {
+	set, err := buildAggregate(DefaultNamespace, cfg, logger, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer set.close(logger)
+	return set.report, nil
+}
+
+// buildToolset generates the toolset of a namespace, reporting generation failures
+func buildToolset(namespace string, cfg *config.Config, logger *logrus.Logger, reporter *reporting.Reporter) (*toolset, error) {
+	// Aggregated upstreams replace the namespace's own specification
+	if len(cfg.Aggregate) > 0 {
+		return buildAggregate(namespace, cfg, logger, reporter)
+	}
+
+	tags := map[string]string{"namespace": namespace}
+
+	// AsyncAPI documents publish through a broker and serve subscribe channels as resources
+	if !cfg.OpenAPI.GraphQL.Enabled && !cfg.OpenAPI.GRPC.Enabled && asyncapi.IsDocument(cfg.OpenAPI.SpecPath) {
+		bridge, err := asyncapi.NewBridge(cfg, logger)
+		if err != nil {
+			err = fmt.Errorf("failed to generate AsyncAPI tools: %w", err)
+			reporter.CaptureGenerationFailure("", err, tags)
+			return nil, err
+		}
+		return &toolset{tools: bridge.Tools(), events: bridge}, nil
+	}
+
+	// WSDL documents generate a tool per operation of a SOAP service port
+	if !cfg.OpenAPI.GraphQL.Enabled && !cfg.OpenAPI.GRPC.Enabled && soap.IsWSDL(cfg.OpenAPI.SpecPath) {
+		tools, err := soap.BuildTools(cfg, logger)
+		if err != nil {
+			err = fmt.Errorf("failed to generate SOAP tools: %w", err)
+			reporter.CaptureGenerationFailure("", err, tags)
+			return nil, err
+		}
+		return &toolset{tools: tools}, nil
+	}
+
+	// GraphQL upstreams generate tools from their schema instead of a specification
+	if cfg.OpenAPI.GraphQL.Enabled {
+		tools, err := graphql.BuildTools(context.Background(), cfg, logger)
+		if err != nil {
+			err = fmt.Errorf("failed to generate GraphQL tools: %w", err)
+			reporter.CaptureGenerationFailure("", err, tags)
+			return nil, err
+		}
+		return &toolset{tools: tools}, nil
+	}
+
+	// gRPC upstreams generate tools from their service descriptors
+	if cfg.OpenAPI.GRPC.Enabled {
+		tools, err := grpcapi.BuildTools(context.Background(), cfg, logger)
+		if err != nil {
+			err = fmt.Errorf("failed to generate gRPC tools: %w", err)
+			reporter.CaptureGenerationFailure("", err, tags)
+			return nil, err
+		}
+		return &toolset{tools: tools}, nil
 	}
 
 	// Parse OpenAPI specification
 	openAPIParser := parser.NewOpenAPIParser(cfg.OpenAPI.SpecPath, logger)
 	spec, err := openAPIParser.ParseSpec()
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+		err = fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+		reporter.CaptureGenerationFailure("", err, tags)
+		return nil, err
 	}
 
 	// Generate MCP tools
 	toolGenerator := generator.NewMCPToolGenerator(spec, cfg, logger)
 	tools, err := toolGenerator.GenerateTools()
+	for _, failure := range toolGenerator.Failures() {
+		reporter.CaptureGenerationFailure(failure.Tool, failure.Err, tags)
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to generate MCP tools: %w", err)
+		reporter.CaptureGenerationFailure("", err, tags)
+		return nil, err
+	}
+
+	return &toolset{tools: tools}, nil
+}
+
+// buildAggregate generates the toolset of each aggregated upstream and merges them under
+// their prefixes, logging the conflicts the merge resolved
+func buildAggregate(namespace string, cfg *config.Config, logger *logrus.Logger, reporter *reporting.Reporter) (*toolset, error) {
+	set := &toolset{upstreams: make(map[string]*toolset, len(cfg.Aggregate))}
+	upstreams := make([]aggregate.Upstream, 0, len(cfg.Aggregate))
+	for _, upstreamCfg := range cfg.Aggregate {
+		upstream, err := buildToolset(namespace+"/"+upstreamCfg.Name, cfg.ForUpstream(upstreamCfg), logger, reporter)
+		if err != nil {
+			set.close(logger)
+			return nil, fmt.Errorf("failed to build upstream %s: %w", upstreamCfg.Name, err)
+		}
+		set.upstreams[upstreamCfg.Name] = upstream
+		upstreams = append(upstreams, aggregate.Upstream{
+			Name:      upstreamCfg.Name,
+			Prefix:    upstreamCfg.ToolPrefix(),
+			Tools:     upstream.tools,
+			Resources: upstream.resources(),
+		})
+	}
+
+	merged := aggregate.Merge(upstreams)
+	for _, conflict := range merged.Report.Conflicts {
+		logger.WithFields(logrus.Fields{
+			"namespace": namespace,
+			"kind":      conflict.Kind,
+			"name":      conflict.Name,
+			"upstream":  conflict.Upstream,
+			"kept_from": conflict.KeptFrom,
+		}).Warn("Aggregated upstream conflict")
+	}
+	logger.WithFields(logrus.Fields{
+		"namespace":  namespace,
+		"upstreams":  len(upstreams),
+		"tool_count": len(merged.Tools),
+		"conflicts":  len(merged.Report.Conflicts),
+	}).Info("Aggregated upstream specifications")
+
+	set.tools = merged.Tools
+	set.merged = merged.Resources
+	set.report = &merged.Report
+	return set, nil
+}
+
+// close releases the AsyncAPI brokers of a toolset and its upstreams
+func (t *toolset) close(logger *logrus.Logger) {
+	if t.events != nil {
+		if err := t.events.Close(); err != nil {
+			logger.WithError(err).Warn("Failed to close AsyncAPI broker")
+		}
+	}
+	for _, upstream := range t.upstreams {
+		upstream.close(logger)
+	}
+}
+
+// newReporter creates the error reporter when a DSN is configured
+func newReporter(cfg *config.Config, logger *logrus.Logger) (*reporting.Reporter, error) {
+	sentry := cfg.Observability.Sentry
+	if sentry.DSN == "" {
+		return nil, nil
+	}
+
+	reporter, err := reporting.NewReporter(reporting.Options{
+		DSN:            sentry.DSN,
+		Environment:    sentry.Environment,
+		Release:        version.Get().Version,
+		ServerName:     cfg.MCP.ServerName,
+		BurstThreshold: sentry.BurstThreshold,
+		BurstWindow:    sentry.BurstWindow,
+	}, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate MCP tools: %w", err)
+		return nil, fmt.Errorf("failed to initialize error reporting: %w", err)
 	}
+	return reporter, nil
+}
 
-	// Create JSON-RPC server
+// newRPCHandler creates a JSON-RPC handler exposing the given toolset
+func newRPCHandler(namespace string, set *toolset, cfg *config.Config, svc *services) http.Handler {
 	rpcServer := rpc.NewServer()
 	rpcServer.RegisterCodec(json.NewCodec(), "application/json")
 
 	// Register MCP service
-	mcpService := NewMCPService(tools, cfg, logger)
+	mcpService := newMCPService(namespace, set, cfg, svc)
 	rpcServer.RegisterService(mcpService, "")
 
-	// Create HTTP server
-	httpServer := &http.Server{
-		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
-		Handler:      rpcServer,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	return rpcServer
+}
+
+// newNamespaceHandler serves a namespace's JSON-RPC endpoint and, when enabled, its tool
+// documentation and function-calling shim
+func newNamespaceHandler(namespace string, set *toolset, cfg *config.Config, svc *services) http.Handler {
+	rpc := newRPCHandler(namespace, set, cfg, svc)
+	if !cfg.Docs.Enabled && !cfg.Functions.Enabled {
+		return rpc
 	}
 
-	return &MCPServer{
-		config: cfg,
-		tools:  tools,
-		server: httpServer,
-		logger: logger,
-	}, nil
+	var docs, shim http.Handler
+	if cfg.Docs.Enabled {
+		docs = newDocsHandler(newMCPService(namespace, set, cfg, svc), svc.namespaces)
+	}
+	if cfg.Functions.Enabled {
+		shim = newFunctionsHandler(newMCPService(namespace, set, cfg, svc))
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case docs != nil && isDocsPath(r.URL.Path):
+			docs.ServeHTTP(w, r)
+		case shim != nil && strings.HasPrefix(r.URL.Path, FunctionsPathPrefix):
+			shim.ServeHTTP(w, r)
+		default:
+			rpc.ServeHTTP(w, r)
+		}
+	})
 }
 
 // Start starts the MCP server
 func (s *MCPServer) Start(ctx context.Context) error {
 	s.logger.WithFields(logrus.Fields{
-		"host": s.config.Server.Host,
-		"port": s.config.Server.Port,
+		"host":    s.config.Server.Host,
+		"port":    s.config.Server.Port,
+		"tenants": len(s.tenants),
 	}).Info("Starting MCP server")
 
+	// Probe upstream APIs until shutdown
+	if s.health != nil {
+		go s.health.Run(ctx)
+	}
+
 	// Start server in a goroutine
 	go func() {
 		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -98,10 +491,27 @@ func (s *MCPServer) Start(ctx context.Context) error {
 		return err
 	}
 
+	for namespace, bridge := range s.events {
+		if err := bridge.Close(); err != nil {
+			s.logger.WithError(err).WithField("namespace", namespace).Warn("Failed to close AsyncAPI broker")
+		}
+	}
+
+	s.reporter.Flush(reportFlushTimeout)
 	s.logger.Info("Server shutdown complete")
+	s.loggers.Close()
 	return nil
 }
 
+// Check verifies that the server address can be bound, then releases it
+func (s *MCPServer) Check() error {
+	listener, err := net.Listen("tcp", s.server.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to bind %s: %w", s.server.Addr, err)
+	}
+	return listener.Close()
+}
+
 // GetTools returns the list of available tools
 func (s *MCPServer) GetTools() []mcp.Tool {
 	return s.tools
@@ -116,3 +526,12 @@ func (s *MCPServer) GetToolByName(name string) (*mcp.Tool, error) {
 	}
 	return nil, fmt.Errorf("tool not found: %s", name)
 }
+
+// GetTenant returns a tenant namespace by name
+func (s *MCPServer) GetTenant(name string) (*Tenant, error) {
+	tenant, exists := s.tenants[name]
+	if !exists {
+		return nil, fmt.Errorf("tenant not found: %s", name)
+	}
+	return tenant, nil
+}
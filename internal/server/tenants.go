@@ -0,0 +1,117 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"api-to-mcp/internal/asyncapi"
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/manifest"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TenantPathPrefix is the URL prefix under which tenant namespaces are served
+const TenantPathPrefix = "/mcp/"
+
+// NamespaceHeader selects a tenant namespace for requests sent to the default endpoint
+const NamespaceHeader = "Mcp-Namespace"
+
+// Tenant is an isolated toolset with its own spec, auth and rate limit
+type Tenant struct {
+	Name    string
+	Tools   []mcp.Tool
+	handler http.Handler
+	events  *asyncapi.Bridge
+}
+
+// newTenant builds the tools and JSON-RPC handler for a tenant namespace
+func newTenant(tenantCfg config.TenantConfig, cfg *config.Config, svc *services) (*Tenant, error) {
+	scoped := cfg.ForTenant(tenantCfg)
+
+	set, err := buildToolset(tenantCfg.Name, scoped, svc.logger, svc.reporter)
+	if err != nil {
+		return nil, err
+	}
+	tools := set.tools
+	if err := manifest.VerifyToolset(scoped.OpenAPI.Manifest, tools); err != nil {
+		return nil, fmt.Errorf("refusing to serve unverified toolset: %w", err)
+	}
+
+	if svc.health != nil {
+		svc.health.addTarget(tenantCfg.Name, scoped, tools)
+	}
+
+	handler := newNamespaceHandler(tenantCfg.Name, set, scoped, svc)
+	if tenantCfg.RateLimit.RequestsPerSecond > 0 {
+		handler = newRateLimitedHandler(handler, tenantCfg.RateLimit)
+	}
+
+	svc.logger.WithFields(logrus.Fields{
+		"tenant":     tenantCfg.Name,
+		"tool_count": len(tools),
+	}).Info("Initialized tenant namespace")
+
+	return &Tenant{
+		Name:    tenantCfg.Name,
+		Tools:   tools,
+		handler: handler,
+		events:  set.events,
+	}, nil
+}
+
+// namespaceRouter dispatches requests to the default toolset or a tenant namespace
+type namespaceRouter struct {
+	defaultHandler http.Handler
+	tenants        map[string]*Tenant
+}
+
+// newNamespaceRouter creates a router over the default handler and tenants
+func newNamespaceRouter(defaultHandler http.Handler, tenants map[string]*Tenant) *namespaceRouter {
+	return &namespaceRouter{
+		defaultHandler: defaultHandler,
+		tenants:        tenants,
+	}
+}
+
+// ServeHTTP routes /mcp/<name>, /docs/<name> and /functions/<format>/<name> paths and
+// namespace-tagged requests to their tenant
+func (r *namespaceRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if isDocsPath(req.URL.Path) {
+		if name := docsNamespace(req.URL.Path); name != "" {
+			r.serveTenant(w, req, name)
+			return
+		}
+	}
+	if strings.HasPrefix(req.URL.Path, FunctionsPathPrefix) {
+		if _, name := functionsPath(req.URL.Path); name != "" {
+			r.serveTenant(w, req, name)
+			return
+		}
+	}
+
+	if strings.HasPrefix(req.URL.Path, TenantPathPrefix) {
+		name := strings.Trim(strings.TrimPrefix(req.URL.Path, TenantPathPrefix), "/")
+		r.serveTenant(w, req, name)
+		return
+	}
+
+	if name := req.Header.Get(NamespaceHeader); name != "" {
+		r.serveTenant(w, req, name)
+		return
+	}
+
+	r.defaultHandler.ServeHTTP(w, req)
+}
+
+// serveTenant forwards a request to the named tenant
+func (r *namespaceRouter) serveTenant(w http.ResponseWriter, req *http.Request, name string) {
+	tenant, exists := r.tenants[name]
+	if !exists {
+		http.Error(w, "unknown namespace: "+name, http.StatusNotFound)
+		return
+	}
+	tenant.handler.ServeHTTP(w, req)
+}
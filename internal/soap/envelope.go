@@ -0,0 +1,363 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Fault is a SOAP fault returned by the service
+type Fault struct {
+	Code   string
+	Reason string
+	Detail interface{}
+}
+
+func (f *Fault) Error() string {
+	if f.Detail != nil {
+		if detail, err := json.Marshal(f.Detail); err == nil {
+			return fmt.Sprintf("SOAP fault %s: %s (detail: %s)", f.Code, f.Reason, detail)
+		}
+	}
+	return fmt.Sprintf("SOAP fault %s: %s", f.Code, f.Reason)
+}
+
+// envelopeNamespace returns the envelope namespace of a SOAP version
+func envelopeNamespace(version string) string {
+	if version == Version12 {
+		return soap12Envelope
+	}
+	return soap11Envelope
+}
+
+// requestHeaders returns the content type and action headers of a SOAP request
+func requestHeaders(version, action string) map[string]string {
+	if version == Version12 {
+		contentType := "application/soap+xml; charset=utf-8"
+		if action != "" {
+			contentType += fmt.Sprintf("; action=%q", action)
+		}
+		return map[string]string{"Content-Type": contentType, "Accept": "application/soap+xml"}
+	}
+	return map[string]string{
+		"Content-Type": "text/xml; charset=utf-8",
+		"Accept":       "text/xml",
+		"SOAPAction":   fmt.Sprintf("%q", action),
+	}
+}
+
+// envelopeEncoder writes a request envelope, declaring a prefix per namespace on the root
+type envelopeEncoder struct {
+	buf      bytes.Buffer
+	prefixes map[string]string
+}
+
+// encodeEnvelope builds the request envelope carrying the input element, filled from JSON
+// arguments in schema order
+func encodeEnvelope(version string, input *Element, args map[string]interface{}) ([]byte, error) {
+	e := &envelopeEncoder{prefixes: make(map[string]string)}
+	namespaces := make([]string, 0)
+	collectNamespaces(input, e.prefixes, &namespaces)
+
+	e.buf.WriteString(xml.Header)
+	fmt.Fprintf(&e.buf, `<soap:Envelope xmlns:soap=%q xmlns:xsi=%q`, envelopeNamespace(version), xsiNamespace)
+	for _, namespace := range namespaces {
+		fmt.Fprintf(&e.buf, ` xmlns:%s=%q`, e.prefixes[namespace], namespace)
+	}
+	e.buf.WriteString("><soap:Body>")
+
+	var err error
+	switch {
+	case input.Name.Local == "":
+		// Document operations with several parts place each part directly in the body
+		err = e.content(input, args)
+	case input.Type != "object":
+		err = e.element(input, args[input.Name.Local])
+	default:
+		err = e.element(input, args)
+	}
+	if err != nil {
+		return nil, err
+	}
+	e.buf.WriteString("</soap:Body></soap:Envelope>")
+	return e.buf.Bytes(), nil
+}
+
+// collectNamespaces assigns a prefix to every namespace used by qualified elements
+func collectNamespaces(el *Element, prefixes map[string]string, order *[]string) {
+	if el.Name.Space != "" && prefixes[el.Name.Space] == "" {
+		prefixes[el.Name.Space] = fmt.Sprintf("ns%d", len(prefixes)+1)
+		*order = append(*order, el.Name.Space)
+	}
+	for _, child := range el.Children {
+		collectNamespaces(child, prefixes, order)
+	}
+}
+
+// tag returns the prefixed name of an element
+func (e *envelopeEncoder) tag(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+	return e.prefixes[name.Space] + ":" + name.Local
+}
+
+// element writes an element, once per item for repeated elements; nil values are omitted
+// unless the element is nillable
+func (e *envelopeEncoder) element(el *Element, value interface{}) error {
+	if items, ok := value.([]interface{}); ok && el.Repeated {
+		for _, item := range items {
+			if err := e.single(el, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return e.single(el, value)
+}
+
+// single writes one occurrence of an element
+func (e *envelopeEncoder) single(el *Element, value interface{}) error {
+	tag := e.tag(el.Name)
+	if value == nil {
+		if el.Nillable {
+			fmt.Fprintf(&e.buf, `<%s xsi:nil="true"/>`, tag)
+		}
+		return nil
+	}
+
+	if el.Type != "object" {
+		text, err := scalarText(el, value)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&e.buf, "<%s>", tag)
+		xml.EscapeText(&e.buf, []byte(text))
+		fmt.Fprintf(&e.buf, "</%s>", tag)
+		return nil
+	}
+
+	fields, ok := value.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%s must be an object", el.Name.Local)
+	}
+	e.buf.WriteString("<" + tag)
+	for _, attribute := range el.Children {
+		if !attribute.Attribute || fields[attribute.Name.Local] == nil {
+			continue
+		}
+		text, err := scalarText(attribute, fields[attribute.Name.Local])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&e.buf, ` %s="`, attribute.Name.Local)
+		xml.EscapeText(&e.buf, []byte(text))
+		e.buf.WriteString(`"`)
+	}
+	e.buf.WriteString(">")
+	if err := e.content(el, fields); err != nil {
+		return err
+	}
+	fmt.Fprintf(&e.buf, "</%s>", tag)
+	return nil
+}
+
+// content writes the child elements of an object; objects without a declared structure, such
+// as xsd:anyType, are written field by field in key order
+func (e *envelopeEncoder) content(el *Element, fields map[string]interface{}) error {
+	if len(el.Children) == 0 {
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := e.element(untypedElement(key, fields[key]), fields[key]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, child := range el.Children {
+		if child.Attribute {
+			continue
+		}
+		if value, ok := fields[child.Name.Local]; ok {
+			if err := e.element(child, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// untypedElement describes an undeclared field from its JSON value
+func untypedElement(name string, value interface{}) *Element {
+	el := &Element{Name: xml.Name{Local: name}, Type: "string", Repeated: true}
+	if items, ok := value.([]interface{}); ok && len(items) > 0 {
+		value = items[0]
+	}
+	if _, ok := value.(map[string]interface{}); ok {
+		el.Type = "object"
+	}
+	return el
+}
+
+// scalarText formats a JSON scalar as XML text
+func scalarText(el *Element, value interface{}) (string, error) {
+	switch v := value.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case json.Number:
+		return v.String(), nil
+	case int, int64:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("%s must be a %s", el.Name.Local, el.Type)
+	}
+}
+
+// decodeEnvelope converts the body of a response envelope to JSON, typing values from the
+// output element; faults are returned as *Fault errors
+func decodeEnvelope(data []byte, output *Element) (interface{}, error) {
+	root, err := parseXML(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SOAP response: %w", err)
+	}
+	if root.name.Local != "Envelope" || (root.name.Space != soap11Envelope && root.name.Space != soap12Envelope) {
+		return nil, fmt.Errorf("invalid SOAP response: root element is %s, not a SOAP Envelope", root.name.Local)
+	}
+	body := root.child(root.name.Space, "Body")
+	if body == nil {
+		return nil, fmt.Errorf("invalid SOAP response: envelope has no Body")
+	}
+	if fault := body.child(root.name.Space, "Fault"); fault != nil {
+		return nil, decodeFault(fault, root.name.Space)
+	}
+
+	if len(body.children) == 0 {
+		return map[string]interface{}{}, nil
+	}
+	if output != nil && output.Name.Local == "" {
+		return convert(body, output), nil
+	}
+	return convert(body.children[0], output), nil
+}
+
+// decodeFault reads a SOAP 1.1 or 1.2 fault
+func decodeFault(fault *node, namespace string) *Fault {
+	f := &Fault{}
+	if namespace == soap12Envelope {
+		if code := fault.child(namespace, "Code"); code != nil {
+			if value := code.child(namespace, "Value"); value != nil {
+				f.Code = strings.TrimSpace(value.text)
+			}
+		}
+		if reason := fault.child(namespace, "Reason"); reason != nil {
+			if text := reason.child(namespace, "Text"); text != nil {
+				f.Reason = strings.TrimSpace(text.text)
+			}
+		}
+		if detail := fault.child(namespace, "Detail"); detail != nil && len(detail.children) > 0 {
+			f.Detail = convert(detail, nil)
+		}
+		return f
+	}
+
+	// SOAP 1.1 fault children are unqualified
+	for _, c := range fault.children {
+		switch c.name.Local {
+		case "faultcode":
+			f.Code = strings.TrimSpace(c.text)
+		case "faultstring":
+			f.Reason = strings.TrimSpace(c.text)
+		case "detail":
+			if len(c.children) > 0 {
+				f.Detail = convert(c, nil)
+			}
+		}
+	}
+	return f
+}
+
+// convert turns an element into JSON: elements with children or attributes become objects,
+// repeated children become arrays and leaf values are typed by the schema when it describes them
+func convert(n *node, el *Element) interface{} {
+	attributes := make([]xml.Attr, 0, len(n.attrs))
+	for _, attr := range n.attrs {
+		if attr.Name.Space == xsiNamespace {
+			if attr.Name.Local == "nil" && attr.Value == "true" {
+				return nil
+			}
+			continue
+		}
+		attributes = append(attributes, attr)
+	}
+
+	if len(n.children) == 0 && len(attributes) == 0 {
+		if el != nil && el.Type == "object" && strings.TrimSpace(n.text) == "" {
+			return map[string]interface{}{}
+		}
+		return scalar(n.text, el)
+	}
+
+	result := make(map[string]interface{})
+	for _, attr := range attributes {
+		result[attr.Name.Local] = scalar(attr.Value, el.child(attr.Name.Local))
+	}
+	counts := make(map[string]int)
+	for _, c := range n.children {
+		counts[c.name.Local]++
+	}
+	for _, c := range n.children {
+		field := el.child(c.name.Local)
+		value := convert(c, field)
+		if (field != nil && field.Repeated) || counts[c.name.Local] > 1 {
+			items, _ := result[c.name.Local].([]interface{})
+			result[c.name.Local] = append(items, value)
+		} else {
+			result[c.name.Local] = value
+		}
+	}
+	if len(n.children) == 0 {
+		if text := strings.TrimSpace(n.text); text != "" {
+			result["value"] = scalar(text, el)
+		}
+	}
+	return result
+}
+
+// scalar types leaf text by its schema type, keeping the text when it does not parse
+func scalar(text string, el *Element) interface{} {
+	if el == nil {
+		return text
+	}
+	trimmed := strings.TrimSpace(text)
+	switch el.Type {
+	case "integer":
+		if v, err := strconv.ParseInt(trimmed, 10, 64); err == nil {
+			return v
+		}
+	case "number":
+		if v, err := strconv.ParseFloat(trimmed, 64); err == nil {
+			return v
+		}
+	case "boolean":
+		switch trimmed {
+		case "true", "1":
+			return true
+		case "false", "0":
+			return false
+		}
+	}
+	return text
+}
@@ -0,0 +1,313 @@
+package soap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/internal/generator"
+	"api-to-mcp/internal/redact"
+	"api-to-mcp/internal/safety"
+	"api-to-mcp/internal/utils"
+	"api-to-mcp/pkg/mcp"
+	"api-to-mcp/pkg/openapi"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Operations are classified like REST verbs so read-only mode, filters, confirmation, write
+// budgets and role method rules apply: read_operations matches are GET, everything else is POST
+const (
+	readMethod  = "GET"
+	writeMethod = "POST"
+)
+
+// Patterns splitting CamelCase operation names into snake_case tool names
+var (
+	acronymBoundary = regexp.MustCompile(`([A-Z]+)([A-Z][a-z])`)
+	camelBoundary   = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+)
+
+// Generator generates MCP tools from the operations of a WSDL service port
+type Generator struct {
+	defs     *Definitions
+	config   *config.Config
+	logger   *logrus.Logger
+	client   *utils.HTTPClient
+	filter   *generator.MCPToolGenerator
+	redactor *redact.Redactor
+	names    map[string]bool
+	// path is the endpoint path, relative to the client's base URL
+	path string
+}
+
+// BuildTools loads the WSDL document at spec_path and generates a tool per operation of the
+// selected port
+func BuildTools(cfg *config.Config, logger *logrus.Logger) ([]mcp.Tool, error) {
+	defs, err := Load(cfg.OpenAPI.SpecPath)
+	if err != nil {
+		return nil, err
+	}
+	return NewGenerator(defs, cfg, logger).GenerateTools()
+}
+
+// NewGenerator creates a generator for parsed definitions
+func NewGenerator(defs *Definitions, cfg *config.Config, logger *logrus.Logger) *Generator {
+	return &Generator{
+		defs:   defs,
+		config: cfg,
+		logger: logger,
+		filter: generator.NewMCPToolGenerator(nil, cfg, logger),
+	}
+}
+
+// GenerateTools generates a tool per operation of the configured service port
+func (g *Generator) GenerateTools() ([]mcp.Tool, error) {
+	g.logger.Info("Generating MCP tools from WSDL document")
+
+	service, port, err := g.selectPort()
+	if err != nil {
+		return nil, err
+	}
+	endpoint := g.config.OpenAPI.SOAP.Endpoint
+	if endpoint == "" {
+		endpoint = port.Address
+	}
+	if err := g.connect(endpoint); err != nil {
+		return nil, err
+	}
+
+	redactor, err := generator.NewResponseRedactor(g.config)
+	if err != nil {
+		return nil, err
+	}
+	g.redactor = redactor
+	g.names = make(map[string]bool)
+
+	tools := make([]mcp.Tool, 0, len(port.Operations))
+	for _, operation := range port.Operations {
+		route := openapi.Endpoint{
+			Method: g.classify(operation.Name),
+			Path:   fmt.Sprintf("/%s/%s", service.Name, operation.Name),
+		}
+		if !g.filter.IsEndpointIncluded(route) {
+			g.logger.WithFields(logrus.Fields{
+				"service":   service.Name,
+				"operation": operation.Name,
+			}).Debug("Skipping filtered SOAP operation")
+			continue
+		}
+		tools = append(tools, g.operationTool(service, port, operation, route, endpoint))
+	}
+
+	g.logger.WithField("tool_count", len(tools)).Info("Generated MCP tools")
+	if len(tools) == 0 {
+		return nil, fmt.Errorf("no tools could be generated: all SOAP operations were filtered out")
+	}
+	return tools, nil
+}
+
+// selectPort returns the configured service and port, defaulting to the first of each
+func (g *Generator) selectPort() (Service, Port, error) {
+	settings := g.config.OpenAPI.SOAP
+	var service *Service
+	for i := range g.defs.Services {
+		if settings.Service == "" || g.defs.Services[i].Name == settings.Service {
+			service = &g.defs.Services[i]
+			break
+		}
+	}
+	if service == nil {
+		return Service{}, Port{}, fmt.Errorf("WSDL service %q not found", settings.Service)
+	}
+	for _, port := range service.Ports {
+		if settings.Port == "" || port.Name == settings.Port {
+			return *service, port, nil
+		}
+	}
+	return Service{}, Port{}, fmt.Errorf("SOAP port %q not found in service %s", settings.Port, service.Name)
+}
+
+// connect creates the upstream client for the endpoint URL
+func (g *Generator) connect(endpoint string) error {
+	if endpoint == "" {
+		return fmt.Errorf("SOAP port has no address; set openapi.soap.endpoint")
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid SOAP endpoint: %s", endpoint)
+	}
+	outbound, err := generator.NewOutboundPolicy(g.config)
+	if err != nil {
+		return err
+	}
+	scoped := *g.config
+	scoped.OpenAPI.BaseURL = u.Scheme + "://" + u.Host
+	g.client = generator.NewUpstreamClient(&scoped, g.logger, outbound)
+	g.path = u.RequestURI()
+	return nil
+}
+
+// classify maps an operation to the HTTP method its safety semantics follow
+func (g *Generator) classify(operation string) string {
+	for _, pattern := range g.config.OpenAPI.SOAP.ReadOperations {
+		if matched, _ := path.Match(pattern, operation); matched {
+			return readMethod
+		}
+	}
+	return writeMethod
+}
+
+// operationTool generates the tool for an operation
+func (g *Generator) operationTool(service Service, port Port, operation Operation, route openapi.Endpoint, endpoint string) mcp.Tool {
+	name := toolName(operation.Name)
+	for candidate, i := name, 2; ; i++ {
+		if !g.names[candidate] {
+			name = candidate
+			break
+		}
+		candidate = fmt.Sprintf("%s_%d", name, i)
+	}
+	g.names[name] = true
+
+	description := operation.Documentation
+	if description == "" {
+		description = fmt.Sprintf("SOAP %s.%s", service.Name, operation.Name)
+	}
+
+	inputSchema := inputSchema(operation.Input)
+	if safety.RequiresConfirmation(g.config.Safety.Confirmation, route.Method, route.Path) {
+		inputSchema.Properties[safety.ConfirmArgument] = mcp.Property{
+			Type:        "string",
+			Description: "Confirmation token returned by a previous call with the same arguments; omit on the first call",
+		}
+	}
+
+	g.logger.WithFields(logrus.Fields{
+		"tool_name": name,
+		"path":      route.Path,
+		"method":    route.Method,
+	}).Debug("Generated tool for SOAP operation")
+
+	return mcp.Tool{
+		Name:        name,
+		Description: description,
+		InputSchema: inputSchema,
+		Handler:     g.handler(port, operation),
+		Operation: &mcp.Operation{
+			Method:      route.Method,
+			Path:        route.Path,
+			BaseURL:     endpoint,
+			OperationID: operation.Name,
+		},
+	}
+}
+
+// toolName converts a CamelCase operation name to a snake_case tool name
+func toolName(operation string) string {
+	name := acronymBoundary.ReplaceAllString(operation, "${1}_${2}")
+	return strings.ToLower(camelBoundary.ReplaceAllString(name, "${1}_${2}"))
+}
+
+// inputSchema converts the children of the input element into arguments; a simple-typed input
+// element becomes a single argument named after it
+func inputSchema(input *Element) *mcp.InputSchema {
+	schema := &mcp.InputSchema{
+		Type:       "object",
+		Properties: make(map[string]mcp.Property),
+		Required:   make([]string, 0),
+	}
+	fields := input.Children
+	if input.Type != "object" {
+		fields = []*Element{input}
+	}
+	for _, field := range fields {
+		schema.Properties[field.Name.Local] = property(field)
+		if !field.Optional && !field.Nillable {
+			schema.Required = append(schema.Required, field.Name.Local)
+		}
+	}
+	return schema
+}
+
+// property converts an element into an MCP property
+func property(el *Element) mcp.Property {
+	p := mcp.Property{
+		Type:        el.Type,
+		Format:      el.Format,
+		Enum:        el.Enum,
+		Description: el.Documentation,
+	}
+	if el.Type == "object" && len(el.Children) > 0 {
+		names := make([]string, len(el.Children))
+		for i, child := range el.Children {
+			names[i] = child.Name.Local
+		}
+		p.Description = strings.TrimSpace(fmt.Sprintf("%s (object with %d properties) - properties: %s",
+			p.Description, len(names), strings.Join(names, ", ")))
+	}
+	if el.Repeated {
+		p.Description = strings.TrimSpace(fmt.Sprintf("%s (array of %s)", p.Description, p.Type))
+		p.Type = "array"
+		p.Format = ""
+		p.Enum = nil
+	}
+	return p
+}
+
+// handler builds the request envelope from the arguments, posts it to the endpoint and converts
+// the response body to JSON
+func (g *Generator) handler(port Port, operation Operation) mcp.ToolHandler {
+	return func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		body, err := encodeEnvelope(port.Version, operation.Input, params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid arguments for %s: %w", operation.Name, err)
+		}
+
+		data, err := g.client.MakeRawRequest(ctx, "POST", g.path, body, requestHeaders(port.Version, operation.Action))
+		if err != nil {
+			// SOAP 1.1 services report faults with an HTTP 500 status
+			var httpErr *utils.HTTPError
+			if errors.As(err, &httpErr) {
+				var fault *Fault
+				if _, decodeErr := decodeEnvelope(data, nil); errors.As(decodeErr, &fault) {
+					return nil, g.redactFault(fault)
+				}
+			}
+			return nil, err
+		}
+
+		result, err := decodeEnvelope(data, operation.Output)
+		if err != nil {
+			var fault *Fault
+			if errors.As(err, &fault) {
+				return nil, g.redactFault(fault)
+			}
+			return nil, err
+		}
+		if g.redactor != nil {
+			result = g.redactor.Value(result)
+		}
+		return result, nil
+	}
+}
+
+// redactFault applies response redaction to a fault's reason and detail
+func (g *Generator) redactFault(fault *Fault) *Fault {
+	if g.redactor == nil {
+		return fault
+	}
+	return &Fault{
+		Code:   fault.Code,
+		Reason: g.redactor.String(fault.Reason),
+		Detail: g.redactor.Value(fault.Detail),
+	}
+}
@@ -0,0 +1,263 @@
+package soap
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"api-to-mcp/internal/config"
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// soapRequest records a request received by the test service
+type soapRequest struct {
+	path        string
+	contentType string
+	action      string
+	body        string
+}
+
+// soapUpstream answers every request with the given status and envelope
+func soapUpstream(t *testing.T, status int, response string) (*httptest.Server, func() soapRequest) {
+	t.Helper()
+	var mu sync.Mutex
+	var last soapRequest
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		last = soapRequest{
+			path:        r.URL.Path,
+			contentType: r.Header.Get("Content-Type"),
+			action:      r.Header.Get("SOAPAction"),
+			body:        string(body),
+		}
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream, func() soapRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return last
+	}
+}
+
+func testConfig(endpoint string) *config.Config {
+	cfg := &config.Config{}
+	cfg.OpenAPI.SpecPath = exampleWSDL
+	cfg.OpenAPI.SOAP.Endpoint = endpoint
+	cfg.OpenAPI.SOAP.ReadOperations = []string{"Get*", "List*"}
+	cfg.Safety.Outbound.AllowPrivate = true
+	return cfg
+}
+
+func findTool(tools []mcp.Tool, name string) *mcp.Tool {
+	for i := range tools {
+		if tools[i].Name == name {
+			return &tools[i]
+		}
+	}
+	return nil
+}
+
+func TestBuildTools_Schema(t *testing.T) {
+	tools, err := BuildTools(testConfig(""), logrus.New())
+	require.NoError(t, err)
+	require.Len(t, tools, 4)
+
+	get := findTool(tools, "get_order")
+	require.NotNil(t, get)
+	assert.Equal(t, "Fetch an order by its identifier", get.Description)
+	assert.Equal(t, "GET", get.Operation.Method)
+	assert.Equal(t, "/OrderService/GetOrder", get.Operation.Path)
+	assert.Equal(t, "https://orders.example.com/soap/orders", get.Operation.BaseURL)
+	assert.Equal(t, []string{"orderId"}, get.InputSchema.Required)
+	assert.Equal(t, "integer", get.InputSchema.Properties["orderId"].Type)
+
+	create := findTool(tools, "create_order")
+	require.NotNil(t, create)
+	assert.Equal(t, "POST", create.Operation.Method)
+	assert.ElementsMatch(t, []string{"customer", "line"}, create.InputSchema.Required)
+	line := create.InputSchema.Properties["line"]
+	assert.Equal(t, "array", line.Type)
+	assert.Contains(t, line.Description, "properties: sku, quantity, price")
+
+	list := findTool(tools, "list_orders")
+	require.NotNil(t, list)
+	assert.Equal(t, []string{"pending", "shipped", "cancelled"}, list.InputSchema.Properties["status"].Enum)
+	assert.Empty(t, list.InputSchema.Required)
+
+	cancel := findTool(tools, "cancel_order")
+	require.NotNil(t, cancel)
+	assert.Equal(t, []string{"orderId"}, cancel.InputSchema.Required)
+}
+
+func TestHandler_DocumentLiteral(t *testing.T) {
+	upstream, last := soapUpstream(t, http.StatusOK, `<?xml version="1.0"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/" xmlns:o="http://example.com/orders">
+  <soap:Body>
+    <o:GetOrderResponse>
+      <o:order id="42">
+        <o:customer>ACME &amp; Co</o:customer>
+        <o:status>shipped</o:status>
+        <o:line><o:sku>A-1</o:sku><o:quantity>2</o:quantity><o:price>9.5</o:price></o:line>
+      </o:order>
+    </o:GetOrderResponse>
+  </soap:Body>
+</soap:Envelope>`)
+	tools, err := BuildTools(testConfig(upstream.URL+"/soap/orders"), logrus.New())
+	require.NoError(t, err)
+
+	result, err := findTool(tools, "get_order").Handler(context.Background(), map[string]interface{}{"orderId": float64(42)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"order": map[string]interface{}{
+			"id":       int64(42),
+			"customer": "ACME & Co",
+			"status":   "shipped",
+			// Repeated elements are arrays even with a single occurrence
+			"line": []interface{}{
+				map[string]interface{}{"sku": "A-1", "quantity": int64(2), "price": 9.5},
+			},
+		},
+	}, result)
+
+	request := last()
+	assert.Equal(t, "/soap/orders", request.path)
+	assert.Equal(t, "text/xml; charset=utf-8", request.contentType)
+	assert.Equal(t, `"http://example.com/orders/GetOrder"`, request.action)
+	assert.Contains(t, request.body, `xmlns:ns1="http://example.com/orders"`)
+	assert.Contains(t, request.body, "<soap:Body><ns1:GetOrder><ns1:orderId>42</ns1:orderId></ns1:GetOrder></soap:Body>")
+}
+
+func TestHandler_SchemaOrderAndEscaping(t *testing.T) {
+	upstream, last := soapUpstream(t, http.StatusOK, `<Envelope xmlns="http://schemas.xmlsoap.org/soap/envelope/"><Body>
+<CreateOrderResponse xmlns="http://example.com/orders"><orderId>7</orderId></CreateOrderResponse></Body></Envelope>`)
+	tools, err := BuildTools(testConfig(upstream.URL), logrus.New())
+	require.NoError(t, err)
+
+	result, err := findTool(tools, "create_order").Handler(context.Background(), map[string]interface{}{
+		"note":     "<fragile>",
+		"customer": "ACME",
+		"line": []interface{}{
+			map[string]interface{}{"price": 1.25, "sku": "A-1", "quantity": float64(1)},
+			map[string]interface{}{"sku": "B-2", "quantity": float64(3), "price": float64(4)},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"orderId": int64(7)}, result)
+
+	assert.Contains(t, last().body, "<ns1:CreateOrder><ns1:customer>ACME</ns1:customer>"+
+		"<ns1:line><ns1:sku>A-1</ns1:sku><ns1:quantity>1</ns1:quantity><ns1:price>1.25</ns1:price></ns1:line>"+
+		"<ns1:line><ns1:sku>B-2</ns1:sku><ns1:quantity>3</ns1:quantity><ns1:price>4</ns1:price></ns1:line>"+
+		"<ns1:note>&lt;fragile&gt;</ns1:note></ns1:CreateOrder>")
+}
+
+func TestHandler_Nillable(t *testing.T) {
+	upstream, last := soapUpstream(t, http.StatusOK, `<Envelope xmlns="http://schemas.xmlsoap.org/soap/envelope/"><Body>
+<CancelOrderResponse xmlns="http://example.com/orders"><cancelled>true</cancelled></CancelOrderResponse></Body></Envelope>`)
+	tools, err := BuildTools(testConfig(upstream.URL), logrus.New())
+	require.NoError(t, err)
+
+	result, err := findTool(tools, "cancel_order").Handler(context.Background(), map[string]interface{}{
+		"orderId": float64(7),
+		"reason":  nil,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"cancelled": true}, result)
+	assert.Contains(t, last().body, `<ns1:reason xsi:nil="true"/>`)
+}
+
+func TestHandler_InvalidArguments(t *testing.T) {
+	tools, err := BuildTools(testConfig("http://127.0.0.1:1/soap"), logrus.New())
+	require.NoError(t, err)
+
+	_, err = findTool(tools, "create_order").Handler(context.Background(), map[string]interface{}{
+		"customer": "ACME",
+		"line":     []interface{}{"not an object"},
+	})
+	assert.ErrorContains(t, err, "invalid arguments for CreateOrder: line must be an object")
+}
+
+func TestHandler_Fault(t *testing.T) {
+	upstream, _ := soapUpstream(t, http.StatusInternalServerError, `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <soap:Fault>
+      <faultcode>soap:Client</faultcode>
+      <faultstring>Order 9 not found</faultstring>
+      <detail><code>404</code></detail>
+    </soap:Fault>
+  </soap:Body>
+</soap:Envelope>`)
+	tools, err := BuildTools(testConfig(upstream.URL), logrus.New())
+	require.NoError(t, err)
+
+	_, err = findTool(tools, "get_order").Handler(context.Background(), map[string]interface{}{"orderId": float64(9)})
+	var fault *Fault
+	require.ErrorAs(t, err, &fault)
+	assert.Equal(t, "soap:Client", fault.Code)
+	assert.Equal(t, "Order 9 not found", fault.Reason)
+	assert.Equal(t, map[string]interface{}{"code": "404"}, fault.Detail)
+}
+
+func TestHandler_SOAP12(t *testing.T) {
+	upstream, last := soapUpstream(t, http.StatusOK, `<env:Envelope xmlns:env="http://www.w3.org/2003/05/soap-envelope">
+  <env:Body>
+    <env:Fault>
+      <env:Code><env:Value>env:Receiver</env:Value></env:Code>
+      <env:Reason><env:Text xml:lang="en">Backend unavailable</env:Text></env:Reason>
+    </env:Fault>
+  </env:Body>
+</env:Envelope>`)
+	cfg := testConfig(upstream.URL)
+	cfg.OpenAPI.SOAP.Port = "OrdersSoap12"
+	tools, err := BuildTools(cfg, logrus.New())
+	require.NoError(t, err)
+
+	_, err = findTool(tools, "get_order").Handler(context.Background(), map[string]interface{}{"orderId": float64(1)})
+	assert.EqualError(t, err, "SOAP fault env:Receiver: Backend unavailable")
+
+	request := last()
+	assert.Equal(t, `application/soap+xml; charset=utf-8; action="http://example.com/orders/GetOrder"`, request.contentType)
+	assert.Empty(t, request.action)
+	assert.Contains(t, request.body, `xmlns:soap="http://www.w3.org/2003/05/soap-envelope"`)
+}
+
+func TestBuildTools_Selection(t *testing.T) {
+	cfg := testConfig("")
+	cfg.OpenAPI.SOAP.Service = "Missing"
+	_, err := BuildTools(cfg, logrus.New())
+	assert.ErrorContains(t, err, `WSDL service "Missing" not found`)
+
+	cfg = testConfig("")
+	cfg.OpenAPI.SOAP.Port = "Missing"
+	_, err = BuildTools(cfg, logrus.New())
+	assert.ErrorContains(t, err, `SOAP port "Missing" not found in service OrderService`)
+}
+
+func TestBuildTools_Filters(t *testing.T) {
+	cfg := testConfig("")
+	cfg.Filters.IncludeMethods = []string{"GET"}
+	tools, err := BuildTools(cfg, logrus.New())
+	require.NoError(t, err)
+	names := make([]string, len(tools))
+	for i, tool := range tools {
+		names[i] = tool.Name
+	}
+	assert.Equal(t, []string{"get_order", "list_orders"}, names)
+}
+
+func TestToolName(t *testing.T) {
+	assert.Equal(t, "get_order", toolName("GetOrder"))
+	assert.Equal(t, "get_xml_report", toolName("GetXMLReport"))
+	assert.Equal(t, "ping", toolName("ping"))
+}
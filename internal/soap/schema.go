@@ -0,0 +1,323 @@
+package soap
+
+import (
+	"encoding/xml"
+	"strconv"
+)
+
+// maxDepth bounds how deeply complex types are expanded, so recursive types terminate
+const maxDepth = 8
+
+// Element is an XML element or attribute described by the schema, converted to the JSON types
+// its tool arguments and results use
+type Element struct {
+	Name xml.Name
+	// Type is a JSON schema type: string, integer, number, boolean or object
+	Type          string
+	Format        string
+	Enum          []string
+	Documentation string
+	Optional      bool
+	Repeated      bool
+	Nillable      bool
+	// Attribute is set for XML attributes of the parent element
+	Attribute bool
+	// Children are the child elements and attributes of object elements, in schema order
+	Children []*Element
+}
+
+// child returns the child element or attribute with the given local name
+func (e *Element) child(local string) *Element {
+	if e == nil {
+		return nil
+	}
+	for _, c := range e.Children {
+		if c.Name.Local == local {
+			return c
+		}
+	}
+	return nil
+}
+
+// schema holds the settings of one xsd:schema that affect the elements declared in it
+type schema struct {
+	targetNamespace string
+	qualified       bool
+}
+
+// schemaSet indexes the global declarations of every loaded schema
+type schemaSet struct {
+	elements        map[xml.Name]*node
+	types           map[xml.Name]*node
+	groups          map[xml.Name]*node
+	attributeGroups map[xml.Name]*node
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{
+		elements:        make(map[xml.Name]*node),
+		types:           make(map[xml.Name]*node),
+		groups:          make(map[xml.Name]*node),
+		attributeGroups: make(map[xml.Name]*node),
+	}
+}
+
+// add indexes the global declarations of a schema
+func (s *schemaSet) add(root *node) {
+	info := &schema{
+		targetNamespace: root.attr("targetNamespace"),
+		qualified:       root.attr("elementFormDefault") == "qualified",
+	}
+	mark(root, info)
+	for _, c := range root.children {
+		if c.name.Space != xsdNamespace {
+			continue
+		}
+		name := xml.Name{Space: info.targetNamespace, Local: c.attr("name")}
+		switch c.name.Local {
+		case "element":
+			s.elements[name] = c
+		case "complexType", "simpleType":
+			s.types[name] = c
+		case "group":
+			s.groups[name] = c
+		case "attributeGroup":
+			s.attributeGroups[name] = c
+		}
+	}
+}
+
+// mark records the declaring schema on every node of a schema
+func mark(n *node, info *schema) {
+	n.schema = info
+	for _, c := range n.children {
+		mark(c, info)
+	}
+}
+
+// globalElement converts a global element declaration, or returns nil when it is unknown
+func (s *schemaSet) globalElement(name xml.Name) *Element {
+	n := s.elements[name]
+	if n == nil {
+		return nil
+	}
+	return s.element(n, true, make(map[xml.Name]bool), 0)
+}
+
+// element converts an element declaration or reference
+func (s *schemaSet) element(n *node, global bool, visiting map[xml.Name]bool, depth int) *Element {
+	if ref := n.attr("ref"); ref != "" {
+		name := n.qname(ref)
+		el := &Element{Name: name, Type: "string"}
+		if target := s.elements[name]; target != nil {
+			el = s.element(target, true, visiting, depth)
+		}
+		occurs(el, n)
+		return el
+	}
+
+	el := &Element{
+		Name:          xml.Name{Local: n.attr("name")},
+		Documentation: n.documentation(xsdNamespace),
+		Nillable:      n.attr("nillable") == "true",
+	}
+	qualified := global || n.schema.qualified
+	if form := n.attr("form"); form != "" {
+		qualified = form == "qualified"
+	}
+	if qualified {
+		el.Name.Space = n.schema.targetNamespace
+	}
+
+	if typ := n.attr("type"); typ != "" {
+		s.applyType(el, n.qname(typ), visiting, depth)
+	} else if ct := n.child(xsdNamespace, "complexType"); ct != nil {
+		s.complex(el, ct, visiting, depth+1)
+	} else if st := n.child(xsdNamespace, "simpleType"); st != nil {
+		s.simple(el, st, visiting, depth)
+	} else {
+		el.Type = "string"
+	}
+	occurs(el, n)
+	return el
+}
+
+// occurs applies minOccurs and maxOccurs
+func occurs(el *Element, n *node) {
+	if n.attr("minOccurs") == "0" {
+		el.Optional = true
+	}
+	if max := n.attr("maxOccurs"); max == "unbounded" {
+		el.Repeated = true
+	} else if count, err := strconv.Atoi(max); err == nil && count > 1 {
+		el.Repeated = true
+	}
+}
+
+// applyType sets an element's type from a named built-in, simple or complex type
+func (s *schemaSet) applyType(el *Element, name xml.Name, visiting map[xml.Name]bool, depth int) {
+	t := s.types[name]
+	if t == nil {
+		// Built-in XSD types, and SOAP encoding types which share their local names
+		builtin(el, name.Local)
+		return
+	}
+	if t.name.Local == "simpleType" {
+		s.simple(el, t, visiting, depth)
+		return
+	}
+	if visiting[name] || depth >= maxDepth {
+		el.Type = "object"
+		return
+	}
+	visiting[name] = true
+	s.complex(el, t, visiting, depth+1)
+	delete(visiting, name)
+}
+
+// complex converts a complex type's content into child elements and attributes
+func (s *schemaSet) complex(el *Element, ct *node, visiting map[xml.Name]bool, depth int) {
+	el.Type = "object"
+	for _, c := range ct.children {
+		if c.name.Space != xsdNamespace {
+			continue
+		}
+		switch c.name.Local {
+		case "sequence", "all", "choice", "group":
+			s.particles(el, c, false, false, visiting, depth)
+		case "attribute":
+			s.attribute(el, c, visiting, depth)
+		case "attributeGroup":
+			if group := s.attributeGroups[c.qname(c.attr("ref"))]; group != nil {
+				for _, a := range group.all(xsdNamespace, "attribute") {
+					s.attribute(el, a, visiting, depth)
+				}
+			}
+		case "complexContent":
+			if ext := c.child(xsdNamespace, "extension"); ext != nil {
+				// Extensions append their content to the base type's
+				s.applyType(el, ext.qname(ext.attr("base")), visiting, depth)
+				s.complex(el, ext, visiting, depth)
+			} else if restriction := c.child(xsdNamespace, "restriction"); restriction != nil {
+				s.complex(el, restriction, visiting, depth)
+			}
+		case "simpleContent":
+			// Text content with attributes: the value keeps the base type and attributes are
+			// returned alongside it in results
+			for _, derivation := range c.children {
+				if base := derivation.attr("base"); base != "" {
+					s.applyType(el, derivation.qname(base), visiting, depth)
+				}
+			}
+		}
+	}
+}
+
+// particles converts the elements of a sequence, choice, all or group reference
+func (s *schemaSet) particles(el *Element, group *node, optional, repeated bool, visiting map[xml.Name]bool, depth int) {
+	if group.name.Local == "group" {
+		target := s.groups[group.qname(group.attr("ref"))]
+		if target == nil {
+			return
+		}
+		occurrence := &Element{}
+		occurs(occurrence, group)
+		for _, c := range target.children {
+			if c.name.Space == xsdNamespace && c.name.Local != "annotation" {
+				s.particles(el, c, optional || occurrence.Optional, repeated || occurrence.Repeated, visiting, depth)
+			}
+		}
+		return
+	}
+
+	occurrence := &Element{}
+	occurs(occurrence, group)
+	optional = optional || occurrence.Optional || group.name.Local == "choice"
+	repeated = repeated || occurrence.Repeated
+	for _, c := range group.children {
+		if c.name.Space != xsdNamespace {
+			continue
+		}
+		switch c.name.Local {
+		case "element":
+			child := s.element(c, false, visiting, depth)
+			child.Optional = child.Optional || optional
+			child.Repeated = child.Repeated || repeated
+			el.Children = append(el.Children, child)
+		case "sequence", "all", "choice", "group":
+			s.particles(el, c, optional, repeated, visiting, depth)
+		}
+	}
+}
+
+// attribute converts an attribute declaration; attributes are unqualified
+func (s *schemaSet) attribute(el *Element, a *node, visiting map[xml.Name]bool, depth int) {
+	if a.attr("name") == "" || a.attr("use") == "prohibited" {
+		return
+	}
+	attribute := &Element{
+		Name:          xml.Name{Local: a.attr("name")},
+		Documentation: a.documentation(xsdNamespace),
+		Optional:      a.attr("use") != "required",
+		Attribute:     true,
+		Type:          "string",
+	}
+	if typ := a.attr("type"); typ != "" {
+		s.applyType(attribute, a.qname(typ), visiting, depth)
+	} else if st := a.child(xsdNamespace, "simpleType"); st != nil {
+		s.simple(attribute, st, visiting, depth)
+	}
+	el.Children = append(el.Children, attribute)
+}
+
+// simple converts a simple type: restrictions keep their base type and enumerations, lists
+// and unions are strings
+func (s *schemaSet) simple(el *Element, st *node, visiting map[xml.Name]bool, depth int) {
+	el.Type = "string"
+	restriction := st.child(xsdNamespace, "restriction")
+	if restriction == nil {
+		return
+	}
+	if base := restriction.attr("base"); base != "" {
+		s.applyType(el, restriction.qname(base), visiting, depth)
+	} else if inline := restriction.child(xsdNamespace, "simpleType"); inline != nil {
+		s.simple(el, inline, visiting, depth)
+	}
+	for _, value := range restriction.all(xsdNamespace, "enumeration") {
+		el.Enum = append(el.Enum, value.attr("value"))
+	}
+}
+
+// builtin maps a built-in XSD type to its JSON type
+func builtin(el *Element, local string) {
+	switch local {
+	case "boolean":
+		el.Type = "boolean"
+	case "int", "short", "byte", "unsignedInt", "unsignedShort", "unsignedByte":
+		el.Type, el.Format = "integer", "int32"
+	case "long", "unsignedLong":
+		el.Type, el.Format = "integer", "int64"
+	case "integer", "nonNegativeInteger", "positiveInteger", "negativeInteger", "nonPositiveInteger":
+		el.Type = "integer"
+	case "float":
+		el.Type, el.Format = "number", "float"
+	case "double":
+		el.Type, el.Format = "number", "double"
+	case "decimal":
+		el.Type = "number"
+	case "dateTime":
+		el.Type, el.Format = "string", "date-time"
+	case "date":
+		el.Type, el.Format = "string", "date"
+	case "time":
+		el.Type, el.Format = "string", "time"
+	case "duration":
+		el.Type, el.Format = "string", "duration"
+	case "base64Binary":
+		el.Type, el.Format = "string", "byte"
+	case "anyURI":
+		el.Type, el.Format = "string", "uri"
+	default:
+		el.Type = "string"
+	}
+}
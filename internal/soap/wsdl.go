@@ -0,0 +1,413 @@
+package soap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Namespaces of the WSDL 1.1 vocabularies and SOAP envelopes
+const (
+	wsdlNamespace  = "http://schemas.xmlsoap.org/wsdl/"
+	soap11Binding  = "http://schemas.xmlsoap.org/wsdl/soap/"
+	soap12Binding  = "http://schemas.xmlsoap.org/wsdl/soap12/"
+	soap11Envelope = "http://schemas.xmlsoap.org/soap/envelope/"
+	soap12Envelope = "http://www.w3.org/2003/05/soap-envelope"
+	xsdNamespace   = "http://www.w3.org/2001/XMLSchema"
+	xsiNamespace   = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// SOAP versions a port binds to
+const (
+	Version11 = "1.1"
+	Version12 = "1.2"
+)
+
+// Definitions is a WSDL 1.1 document normalized to the operations its SOAP ports expose
+type Definitions struct {
+	Name            string
+	TargetNamespace string
+	Documentation   string
+	Services        []Service
+}
+
+// Service is a WSDL service and its SOAP ports, in declaration order
+type Service struct {
+	Name          string
+	Documentation string
+	Ports         []Port
+}
+
+// Port is a SOAP endpoint and the operations of its binding
+type Port struct {
+	Name    string
+	Version string
+	// Address is the port's soap:address location
+	Address    string
+	Operations []Operation
+}
+
+// Operation is a request-response operation with its body elements resolved from the schema
+type Operation struct {
+	Name          string
+	Documentation string
+	// Action is the SOAPAction sent with the request
+	Action string
+	// Style is document or rpc
+	Style string
+	// Input and Output describe the body element; an Input without a name places its children
+	// directly in the body, for document operations with several parts
+	Input  *Element
+	Output *Element
+}
+
+// IsWSDL reports whether a file is a WSDL 1.1 document, identified by its definitions root
+func IsWSDL(path string) bool {
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	decoder := xml.NewDecoder(file)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Space == wsdlNamespace && start.Name.Local == "definitions"
+		}
+	}
+}
+
+// Load reads a WSDL document, following wsdl:import and xsd:import or xsd:include locations
+// relative to it; remote locations are not fetched
+func Load(path string) (*Definitions, error) {
+	l := newLoader()
+	if err := l.loadFile(path); err != nil {
+		return nil, err
+	}
+	defs, err := l.resolve()
+	if err != nil {
+		return nil, fmt.Errorf("invalid WSDL document %s: %w", path, err)
+	}
+	return defs, nil
+}
+
+// Parse normalizes a self-contained WSDL document
+func Parse(data []byte) (*Definitions, error) {
+	l := newLoader()
+	if err := l.load(data, ""); err != nil {
+		return nil, err
+	}
+	return l.resolve()
+}
+
+// loader collects the definitions and schemas of a WSDL document and its imports
+type loader struct {
+	seen        map[string]bool
+	definitions []*node
+	schemas     *schemaSet
+}
+
+func newLoader() *loader {
+	return &loader{seen: make(map[string]bool), schemas: newSchemaSet()}
+}
+
+// loadFile loads a WSDL or XSD file once
+func (l *loader) loadFile(path string) error {
+	absolute, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if l.seen[absolute] {
+		return nil
+	}
+	l.seen[absolute] = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read WSDL document: %w", err)
+	}
+	if err := l.load(data, filepath.Dir(path)); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// load parses a WSDL or XSD document whose imports are relative to dir; dir is empty when the
+// document was not read from a file
+func (l *loader) load(data []byte, dir string) error {
+	root, err := parseXML(data)
+	if err != nil {
+		return err
+	}
+	switch {
+	case root.name.Space == wsdlNamespace && root.name.Local == "definitions":
+		l.definitions = append(l.definitions, root)
+		for _, imp := range root.all(wsdlNamespace, "import") {
+			if err := l.include(dir, imp.attr("location")); err != nil {
+				return err
+			}
+		}
+		if types := root.child(wsdlNamespace, "types"); types != nil {
+			for _, s := range types.all(xsdNamespace, "schema") {
+				if err := l.addSchema(s, dir); err != nil {
+					return err
+				}
+			}
+		}
+	case root.name.Space == xsdNamespace && root.name.Local == "schema":
+		return l.addSchema(root, dir)
+	default:
+		return fmt.Errorf("unexpected root element %s", root.name.Local)
+	}
+	return nil
+}
+
+// addSchema indexes a schema and loads the documents it imports or includes
+func (l *loader) addSchema(root *node, dir string) error {
+	l.schemas.add(root)
+	for _, local := range []string{"import", "include"} {
+		for _, imp := range root.all(xsdNamespace, local) {
+			if err := l.include(dir, imp.attr("schemaLocation")); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// include loads an imported document; imports without a location refer to inline schemas
+func (l *loader) include(dir, location string) error {
+	if location == "" {
+		return nil
+	}
+	if strings.Contains(location, "://") {
+		return fmt.Errorf("remote import %s is not supported; download it next to the WSDL and use a relative location", location)
+	}
+	if dir == "" {
+		return fmt.Errorf("import %s requires loading the WSDL from a file", location)
+	}
+	if !filepath.IsAbs(location) {
+		location = filepath.Join(dir, location)
+	}
+	return l.loadFile(location)
+}
+
+// resolve links services, bindings, port types and messages into Definitions
+func (l *loader) resolve() (*Definitions, error) {
+	if len(l.definitions) == 0 {
+		return nil, fmt.Errorf("no WSDL definitions found")
+	}
+
+	messages := make(map[xml.Name]*node)
+	portTypes := make(map[xml.Name]*node)
+	bindings := make(map[xml.Name]*node)
+	for _, defs := range l.definitions {
+		tns := defs.attr("targetNamespace")
+		for _, m := range defs.all(wsdlNamespace, "message") {
+			messages[xml.Name{Space: tns, Local: m.attr("name")}] = m
+		}
+		for _, p := range defs.all(wsdlNamespace, "portType") {
+			portTypes[xml.Name{Space: tns, Local: p.attr("name")}] = p
+		}
+		for _, b := range defs.all(wsdlNamespace, "binding") {
+			bindings[xml.Name{Space: tns, Local: b.attr("name")}] = b
+		}
+	}
+
+	root := l.definitions[0]
+	defs := &Definitions{
+		Name:            root.attr("name"),
+		TargetNamespace: root.attr("targetNamespace"),
+		Documentation:   root.documentation(wsdlNamespace),
+	}
+	r := &resolver{schemas: l.schemas, messages: messages}
+	for _, d := range l.definitions {
+		for _, s := range d.all(wsdlNamespace, "service") {
+			service := Service{Name: s.attr("name"), Documentation: s.documentation(wsdlNamespace)}
+			for _, p := range s.all(wsdlNamespace, "port") {
+				binding := bindings[p.qname(p.attr("binding"))]
+				if binding == nil {
+					return nil, fmt.Errorf("port %s references unknown binding %s", p.attr("name"), p.attr("binding"))
+				}
+				port, ok, err := r.port(p, binding, portTypes)
+				if err != nil {
+					return nil, fmt.Errorf("port %s: %w", p.attr("name"), err)
+				}
+				if ok {
+					service.Ports = append(service.Ports, port)
+				}
+			}
+			if len(service.Ports) > 0 {
+				defs.Services = append(defs.Services, service)
+			}
+		}
+	}
+	if len(defs.Services) == 0 {
+		return nil, fmt.Errorf("no service with a SOAP port found")
+	}
+	return defs, nil
+}
+
+// resolver converts bindings and messages into operations
+type resolver struct {
+	schemas  *schemaSet
+	messages map[xml.Name]*node
+}
+
+// port resolves a service port; ports with non-SOAP bindings, such as HTTP, are skipped
+func (r *resolver) port(p, binding *node, portTypes map[xml.Name]*node) (Port, bool, error) {
+	port := Port{Name: p.attr("name")}
+	bindingNamespace := soap11Binding
+	port.Version = Version11
+	soapBinding := binding.child(soap11Binding, "binding")
+	if soapBinding == nil {
+		bindingNamespace = soap12Binding
+		port.Version = Version12
+		soapBinding = binding.child(soap12Binding, "binding")
+	}
+	if soapBinding == nil {
+		return port, false, nil
+	}
+	if address := p.child(bindingNamespace, "address"); address != nil {
+		port.Address = address.attr("location")
+	}
+
+	portType := portTypes[binding.qname(binding.attr("type"))]
+	if portType == nil {
+		return port, false, fmt.Errorf("binding %s references unknown port type %s", binding.attr("name"), binding.attr("type"))
+	}
+	defaultStyle := soapBinding.attr("style")
+	if defaultStyle == "" {
+		defaultStyle = "document"
+	}
+
+	for _, bop := range binding.all(wsdlNamespace, "operation") {
+		name := bop.attr("name")
+		pop := findOperation(portType, name)
+		if pop == nil {
+			return port, false, fmt.Errorf("operation %s is not declared by port type %s", name, portType.attr("name"))
+		}
+		operation := Operation{
+			Name:          name,
+			Documentation: pop.documentation(wsdlNamespace),
+			Style:         defaultStyle,
+		}
+		if soapOperation := bop.child(bindingNamespace, "operation"); soapOperation != nil {
+			operation.Action = soapOperation.attr("soapAction")
+			if style := soapOperation.attr("style"); style != "" {
+				operation.Style = style
+			}
+		}
+
+		var err error
+		if operation.Input, err = r.body(operation, pop, bop, bindingNamespace, "input"); err != nil {
+			return port, false, fmt.Errorf("operation %s input: %w", name, err)
+		}
+		if operation.Output, err = r.body(operation, pop, bop, bindingNamespace, "output"); err != nil {
+			return port, false, fmt.Errorf("operation %s output: %w", name, err)
+		}
+		port.Operations = append(port.Operations, operation)
+	}
+	return port, true, nil
+}
+
+// findOperation returns the port type operation with the given name
+func findOperation(portType *node, name string) *node {
+	for _, op := range portType.all(wsdlNamespace, "operation") {
+		if op.attr("name") == name {
+			return op
+		}
+	}
+	return nil
+}
+
+// body describes the input or output body of an operation from its message parts
+func (r *resolver) body(operation Operation, pop, bop *node, bindingNamespace, direction string) (*Element, error) {
+	ref := pop.child(wsdlNamespace, direction)
+	if ref == nil {
+		// One-way operations have no output
+		return &Element{Type: "object"}, nil
+	}
+	msg := r.messages[ref.qname(ref.attr("message"))]
+	if msg == nil {
+		return nil, fmt.Errorf("unknown message %s", ref.attr("message"))
+	}
+
+	parts := msg.all(wsdlNamespace, "part")
+	namespace := ""
+	if binding := bop.child(wsdlNamespace, direction); binding != nil {
+		if soapBody := binding.child(bindingNamespace, "body"); soapBody != nil {
+			namespace = soapBody.attr("namespace")
+			if names := strings.Fields(soapBody.attr("parts")); len(names) > 0 {
+				parts = selectParts(parts, names)
+			}
+		}
+	}
+
+	if operation.Style == "rpc" {
+		// RPC bodies wrap the unqualified parts in an element named after the operation
+		name := operation.Name
+		if direction == "output" {
+			name += "Response"
+		}
+		wrapper := &Element{Name: xml.Name{Space: namespace, Local: name}, Type: "object"}
+		for _, part := range parts {
+			el, err := r.part(part)
+			if err != nil {
+				return nil, err
+			}
+			el.Name = xml.Name{Local: part.attr("name")}
+			wrapper.Children = append(wrapper.Children, el)
+		}
+		return wrapper, nil
+	}
+
+	if len(parts) == 1 && parts[0].attr("element") != "" {
+		return r.part(parts[0])
+	}
+	body := &Element{Type: "object"}
+	for _, part := range parts {
+		el, err := r.part(part)
+		if err != nil {
+			return nil, err
+		}
+		body.Children = append(body.Children, el)
+	}
+	return body, nil
+}
+
+// part resolves a message part declared by element or by type
+func (r *resolver) part(part *node) (*Element, error) {
+	if element := part.attr("element"); element != "" {
+		el := r.schemas.globalElement(part.qname(element))
+		if el == nil {
+			return nil, fmt.Errorf("part %s references unknown element %s", part.attr("name"), element)
+		}
+		return el, nil
+	}
+	if typ := part.attr("type"); typ != "" {
+		el := &Element{Name: xml.Name{Local: part.attr("name")}}
+		r.schemas.applyType(el, part.qname(typ), make(map[xml.Name]bool), 0)
+		return el, nil
+	}
+	return nil, fmt.Errorf("part %s declares neither element nor type", part.attr("name"))
+}
+
+// selectParts keeps the named parts, in the order soap:body lists them
+func selectParts(parts []*node, names []string) []*node {
+	selected := make([]*node, 0, len(names))
+	for _, name := range names {
+		for _, part := range parts {
+			if part.attr("name") == name {
+				selected = append(selected, part)
+			}
+		}
+	}
+	return selected
+}
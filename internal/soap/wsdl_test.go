@@ -0,0 +1,201 @@
+package soap
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const exampleWSDL = "../../examples/orders.wsdl"
+
+func operationNamed(t *testing.T, port Port, name string) Operation {
+	t.Helper()
+	for _, op := range port.Operations {
+		if op.Name == name {
+			return op
+		}
+	}
+	t.Fatalf("operation %s not found", name)
+	return Operation{}
+}
+
+func TestLoad_Example(t *testing.T) {
+	defs, err := Load(exampleWSDL)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Orders", defs.Name)
+	assert.Equal(t, "Order management service", defs.Documentation)
+	require.Len(t, defs.Services, 1)
+	service := defs.Services[0]
+	assert.Equal(t, "OrderService", service.Name)
+	require.Len(t, service.Ports, 2)
+	assert.Equal(t, Version11, service.Ports[0].Version)
+	assert.Equal(t, "https://orders.example.com/soap/orders", service.Ports[0].Address)
+	assert.Equal(t, Version12, service.Ports[1].Version)
+
+	port := service.Ports[0]
+	require.Len(t, port.Operations, 4)
+	get := operationNamed(t, port, "GetOrder")
+	assert.Equal(t, "http://example.com/orders/GetOrder", get.Action)
+	assert.Equal(t, "document", get.Style)
+	assert.Equal(t, "Fetch an order by its identifier", get.Documentation)
+	assert.Equal(t, xml.Name{Space: "http://example.com/orders", Local: "GetOrder"}, get.Input.Name)
+	require.Len(t, get.Input.Children, 1)
+	orderID := get.Input.Children[0]
+	assert.Equal(t, xml.Name{Space: "http://example.com/orders", Local: "orderId"}, orderID.Name)
+	assert.Equal(t, "integer", orderID.Type)
+	assert.Equal(t, "int64", orderID.Format)
+	assert.Equal(t, "Identifier of the order", orderID.Documentation)
+
+	order := get.Output.child("order")
+	require.NotNil(t, order)
+	assert.Equal(t, "object", order.Type)
+	id := order.child("id")
+	require.NotNil(t, id)
+	assert.True(t, id.Attribute)
+	assert.False(t, id.Optional)
+	status := order.child("status")
+	assert.Equal(t, []string{"pending", "shipped", "cancelled"}, status.Enum)
+	line := order.child("line")
+	assert.True(t, line.Repeated)
+	assert.Equal(t, "number", line.child("price").Type)
+	assert.True(t, order.child("note").Optional)
+}
+
+func TestParse_RPCStyle(t *testing.T) {
+	defs, err := Parse([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"
+    xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/"
+    xmlns:xsd="http://www.w3.org/2001/XMLSchema"
+    xmlns:tns="urn:calc" targetNamespace="urn:calc">
+  <message name="AddRequest"><part name="a" type="xsd:int"/><part name="b" type="xsd:int"/></message>
+  <message name="AddResponse"><part name="sum" type="xsd:int"/></message>
+  <portType name="Calc">
+    <operation name="Add"><input message="tns:AddRequest"/><output message="tns:AddResponse"/></operation>
+  </portType>
+  <binding name="CalcBinding" type="tns:Calc">
+    <soap:binding style="rpc" transport="http://schemas.xmlsoap.org/soap/http"/>
+    <operation name="Add">
+      <soap:operation soapAction="urn:calc#Add"/>
+      <input><soap:body use="literal" namespace="urn:calc"/></input>
+      <output><soap:body use="literal" namespace="urn:calc"/></output>
+    </operation>
+  </binding>
+  <service name="CalcService">
+    <port name="CalcPort" binding="tns:CalcBinding"><soap:address location="http://localhost/calc"/></port>
+  </service>
+</definitions>`))
+	require.NoError(t, err)
+
+	add := defs.Services[0].Ports[0].Operations[0]
+	assert.Equal(t, "rpc", add.Style)
+	assert.Equal(t, xml.Name{Space: "urn:calc", Local: "Add"}, add.Input.Name)
+	require.Len(t, add.Input.Children, 2)
+	assert.Equal(t, xml.Name{Local: "a"}, add.Input.Children[0].Name)
+	assert.Equal(t, "integer", add.Input.Children[0].Type)
+	assert.Equal(t, xml.Name{Space: "urn:calc", Local: "AddResponse"}, add.Output.Name)
+}
+
+func TestParse_RecursiveAndExtendedTypes(t *testing.T) {
+	defs, err := Parse([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"
+    xmlns:soap="http://schemas.xmlsoap.org/wsdl/soap/"
+    xmlns:xsd="http://www.w3.org/2001/XMLSchema"
+    xmlns:tns="urn:tree" targetNamespace="urn:tree">
+  <types>
+    <xsd:schema targetNamespace="urn:tree">
+      <xsd:complexType name="Base">
+        <xsd:sequence><xsd:element name="id" type="xsd:string"/></xsd:sequence>
+      </xsd:complexType>
+      <xsd:complexType name="Node">
+        <xsd:complexContent>
+          <xsd:extension base="tns:Base">
+            <xsd:sequence>
+              <xsd:choice>
+                <xsd:element name="leaf" type="xsd:string"/>
+                <xsd:element name="child" type="tns:Node" maxOccurs="unbounded"/>
+              </xsd:choice>
+            </xsd:sequence>
+          </xsd:extension>
+        </xsd:complexContent>
+      </xsd:complexType>
+      <xsd:element name="Walk" type="tns:Node"/>
+    </xsd:schema>
+  </types>
+  <message name="WalkRequest"><part name="body" element="tns:Walk"/></message>
+  <portType name="Tree"><operation name="Walk"><input message="tns:WalkRequest"/></operation></portType>
+  <binding name="TreeBinding" type="tns:Tree">
+    <soap:binding style="document" transport="http://schemas.xmlsoap.org/soap/http"/>
+    <operation name="Walk"><input><soap:body use="literal"/></input></operation>
+  </binding>
+  <service name="TreeService">
+    <port name="TreePort" binding="tns:TreeBinding"><soap:address location="http://localhost/tree"/></port>
+  </service>
+</definitions>`))
+	require.NoError(t, err)
+
+	walk := defs.Services[0].Ports[0].Operations[0]
+	require.Len(t, walk.Input.Children, 3)
+	// Local elements are unqualified unless elementFormDefault is qualified
+	assert.Equal(t, xml.Name{Local: "id"}, walk.Input.Children[0].Name)
+	assert.True(t, walk.Input.child("leaf").Optional)
+	child := walk.Input.child("child")
+	assert.True(t, child.Repeated)
+	assert.Equal(t, "object", child.Type)
+	// The recursive reference stops expanding rather than looping
+	assert.Nil(t, child.child("child"))
+}
+
+func TestParse_Errors(t *testing.T) {
+	_, err := Parse([]byte(`<schema xmlns="http://www.w3.org/2001/XMLSchema"/>`))
+	assert.ErrorContains(t, err, "no WSDL definitions")
+
+	_, err = Parse([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"/>`))
+	assert.ErrorContains(t, err, "no service with a SOAP port")
+
+	_, err = Parse([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/">
+  <import location="https://example.com/types.wsdl"/>
+</definitions>`))
+	assert.ErrorContains(t, err, "remote import")
+}
+
+func TestLoad_Imports(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "types.xsd"), []byte(`<xsd:schema
+    xmlns:xsd="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:ping" elementFormDefault="qualified">
+  <xsd:element name="Ping"><xsd:complexType><xsd:sequence>
+    <xsd:element name="message" type="xsd:string"/>
+  </xsd:sequence></xsd:complexType></xsd:element>
+</xsd:schema>`), 0644))
+	path := filepath.Join(dir, "ping.wsdl")
+	require.NoError(t, os.WriteFile(path, []byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"
+    xmlns:soap12="http://schemas.xmlsoap.org/wsdl/soap12/"
+    xmlns:xsd="http://www.w3.org/2001/XMLSchema"
+    xmlns:p="urn:ping" xmlns:tns="urn:ping:wsdl" targetNamespace="urn:ping:wsdl">
+  <types><xsd:schema><xsd:import namespace="urn:ping" schemaLocation="types.xsd"/></xsd:schema></types>
+  <message name="PingRequest"><part name="parameters" element="p:Ping"/></message>
+  <portType name="Ping"><operation name="Ping"><input message="tns:PingRequest"/></operation></portType>
+  <binding name="PingBinding" type="tns:Ping">
+    <soap12:binding transport="http://schemas.xmlsoap.org/soap/http"/>
+    <operation name="Ping"><input><soap12:body use="literal"/></input></operation>
+  </binding>
+  <service name="PingService">
+    <port name="PingPort" binding="tns:PingBinding"><soap12:address location="http://localhost/ping"/></port>
+  </service>
+</definitions>`), 0644))
+
+	assert.True(t, IsWSDL(path))
+	defs, err := Load(path)
+	require.NoError(t, err)
+	ping := defs.Services[0].Ports[0].Operations[0]
+	assert.Equal(t, Version12, defs.Services[0].Ports[0].Version)
+	assert.Equal(t, xml.Name{Space: "urn:ping", Local: "message"}, ping.Input.Children[0].Name)
+}
+
+func TestIsWSDL(t *testing.T) {
+	assert.True(t, IsWSDL(exampleWSDL))
+	assert.False(t, IsWSDL("../../examples/petstore.yaml"))
+	assert.False(t, IsWSDL("missing.wsdl"))
+}
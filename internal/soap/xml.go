@@ -0,0 +1,133 @@
+package soap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// node is a parsed XML element that keeps the namespace declarations in scope, so QName
+// attribute values such as type="tns:Order" can be resolved
+type node struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	scope    map[string]string
+	children []*node
+	text     string
+	// schema is the XML schema the node was declared in, for nodes inside xsd:schema
+	schema *schema
+}
+
+// parseXML parses a document into a node tree
+func parseXML(data []byte) (*node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var root *node
+	stack := make([]*node, 0)
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			parentScope := map[string]string{}
+			if len(stack) > 0 {
+				parentScope = stack[len(stack)-1].scope
+			}
+			n := &node{name: t.Name, scope: parentScope}
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "xmlns":
+					n.declare(attr.Name.Local, attr.Value)
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					n.declare("", attr.Value)
+				default:
+					n.attrs = append(n.attrs, attr)
+				}
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, n)
+			} else {
+				root = n
+			}
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += string(t)
+			}
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("document has no root element")
+	}
+	return root, nil
+}
+
+// declare binds a namespace prefix, copying the inherited scope on first change
+func (n *node) declare(prefix, namespace string) {
+	scope := make(map[string]string, len(n.scope)+1)
+	for k, v := range n.scope {
+		scope[k] = v
+	}
+	scope[prefix] = namespace
+	n.scope = scope
+}
+
+// attr returns the value of an unqualified attribute
+func (n *node) attr(name string) string {
+	for _, attr := range n.attrs {
+		if attr.Name.Local == name && attr.Name.Space == "" {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// qname resolves a prefixed attribute value against the namespaces in scope
+func (n *node) qname(value string) xml.Name {
+	value = strings.TrimSpace(value)
+	if prefix, local, ok := strings.Cut(value, ":"); ok {
+		return xml.Name{Space: n.scope[prefix], Local: local}
+	}
+	return xml.Name{Space: n.scope[""], Local: value}
+}
+
+// child returns the first child element with the given namespace and local name
+func (n *node) child(space, local string) *node {
+	for _, c := range n.children {
+		if c.name.Space == space && c.name.Local == local {
+			return c
+		}
+	}
+	return nil
+}
+
+// all returns the child elements with the given namespace and local name
+func (n *node) all(space, local string) []*node {
+	matches := make([]*node, 0)
+	for _, c := range n.children {
+		if c.name.Space == space && c.name.Local == local {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+// documentation returns the trimmed text of the node's documentation child
+func (n *node) documentation(space string) string {
+	if doc := n.child(space, "documentation"); doc != nil {
+		return strings.Join(strings.Fields(doc.text), " ")
+	}
+	if annotation := n.child(xsdNamespace, "annotation"); annotation != nil {
+		return annotation.documentation(xsdNamespace)
+	}
+	return ""
+}
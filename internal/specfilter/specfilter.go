@@ -0,0 +1,331 @@
+package specfilter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"api-to-mcp/pkg/mcp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ToolExtension names the extension recording the tool generated from each kept operation
+const ToolExtension = "x-mcp-tool"
+
+// methods are the operation keys of a path item
+var methods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// Report describes what filtering removed
+type Report struct {
+	Operations int
+	Removed    int
+	// Components lists pruned components as kind/name, e.g. schemas/Order
+	Components []string
+}
+
+// Document is an OpenAPI document reduced to the operations tools were generated from
+type Document struct {
+	root *yaml.Node
+}
+
+// Filter removes the operations of an OpenAPI 3 document that no tool was generated from,
+// then the components, path items and tags nothing references anymore. Each kept operation is
+// annotated with x-mcp-tool. The document's key order and comments are preserved
+func Filter(data []byte, tools []mcp.Tool) (*Document, *Report, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse specification: %w", err)
+	}
+	if root.Kind != yaml.DocumentNode || len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("specification is not a YAML or JSON object")
+	}
+	doc := root.Content[0]
+	if value(doc, "openapi") == nil {
+		return nil, nil, fmt.Errorf("only OpenAPI 3 documents can be filtered")
+	}
+
+	kept := make(map[string]string, len(tools))
+	for _, tool := range tools {
+		if tool.Operation != nil {
+			kept[strings.ToUpper(tool.Operation.Method)+" "+tool.Operation.Path] = tool.Name
+		}
+	}
+
+	report := &Report{Components: make([]string, 0)}
+	if paths := value(doc, "paths"); paths != nil && paths.Kind == yaml.MappingNode {
+		filterPaths(paths, kept, report)
+	}
+	pruneComponents(doc, report)
+	pruneTags(doc)
+	return &Document{root: &root}, report, nil
+}
+
+// filterPaths removes operations without a tool and path items left without operations
+func filterPaths(paths *yaml.Node, kept map[string]string, report *Report) {
+	items := make([]*yaml.Node, 0, len(paths.Content))
+	for i := 0; i+1 < len(paths.Content); i += 2 {
+		path, item := paths.Content[i], paths.Content[i+1]
+		if item.Kind != yaml.MappingNode {
+			continue
+		}
+
+		fields := make([]*yaml.Node, 0, len(item.Content))
+		operations := 0
+		for j := 0; j+1 < len(item.Content); j += 2 {
+			key, operation := item.Content[j], item.Content[j+1]
+			if !methods[key.Value] {
+				fields = append(fields, key, operation)
+				continue
+			}
+			report.Operations++
+			tool, ok := kept[strings.ToUpper(key.Value)+" "+path.Value]
+			if !ok {
+				report.Removed++
+				continue
+			}
+			if operation.Kind == yaml.MappingNode {
+				set(operation, ToolExtension, tool)
+			}
+			fields = append(fields, key, operation)
+			operations++
+		}
+		if operations == 0 {
+			continue
+		}
+		item.Content = fields
+		items = append(items, path, item)
+	}
+	paths.Content = items
+}
+
+// pruneComponents removes components nothing outside them references, directly or through
+// other referenced components; security schemes are named by security requirements rather
+// than referenced, so they are kept
+func pruneComponents(doc *yaml.Node, report *Report) {
+	components := value(doc, "components")
+	if components == nil || components.Kind != yaml.MappingNode {
+		return
+	}
+
+	// Collect references from everything but the components, then follow them
+	reachable := make(map[string]bool)
+	pending := make([]string, 0)
+	for i := 0; i+1 < len(doc.Content); i += 2 {
+		if doc.Content[i].Value != "components" {
+			pending = append(pending, refs(doc.Content[i+1])...)
+		}
+	}
+	for len(pending) > 0 {
+		ref := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		if reachable[ref] {
+			continue
+		}
+		reachable[ref] = true
+		if target := component(components, ref); target != nil {
+			pending = append(pending, refs(target)...)
+		}
+	}
+
+	for i := 0; i+1 < len(components.Content); i += 2 {
+		kind, entries := components.Content[i].Value, components.Content[i+1]
+		if kind == "securitySchemes" || entries.Kind != yaml.MappingNode {
+			continue
+		}
+		kept := make([]*yaml.Node, 0, len(entries.Content))
+		for j := 0; j+1 < len(entries.Content); j += 2 {
+			name := entries.Content[j].Value
+			if !reachable[kind+"/"+name] {
+				report.Components = append(report.Components, kind+"/"+name)
+				continue
+			}
+			kept = append(kept, entries.Content[j], entries.Content[j+1])
+		}
+		entries.Content = kept
+	}
+	sort.Strings(report.Components)
+}
+
+// refs returns the components referenced under a node as kind/name
+func refs(node *yaml.Node) []string {
+	found := make([]string, 0)
+	var walk func(*yaml.Node)
+	walk = func(n *yaml.Node) {
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == "$ref" && n.Content[i+1].Kind == yaml.ScalarNode {
+					if ref, ok := componentRef(n.Content[i+1].Value); ok {
+						found = append(found, ref)
+					}
+				}
+			}
+		}
+		if n.Kind == yaml.AliasNode && n.Alias != nil {
+			walk(n.Alias)
+		}
+		for _, child := range n.Content {
+			walk(child)
+		}
+	}
+	walk(node)
+	return found
+}
+
+// componentRef converts a local reference such as #/components/schemas/Order to
+// schemas/Order; references into a component, such as its properties, keep the component
+func componentRef(ref string) (string, bool) {
+	pointer, ok := strings.CutPrefix(ref, "#/components/")
+	if !ok {
+		return "", false
+	}
+	parts := strings.SplitN(pointer, "/", 3)
+	if len(parts) < 2 {
+		return "", false
+	}
+	return parts[0] + "/" + unescape(parts[1]), true
+}
+
+// unescape decodes a JSON pointer token
+func unescape(token string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+}
+
+// component returns the node of a component given as kind/name
+func component(components *yaml.Node, ref string) *yaml.Node {
+	kind, name, _ := strings.Cut(ref, "/")
+	entries := value(components, kind)
+	if entries == nil {
+		return nil
+	}
+	return value(entries, name)
+}
+
+// pruneTags removes tag definitions no kept operation uses
+func pruneTags(doc *yaml.Node) {
+	tags := value(doc, "tags")
+	if tags == nil || tags.Kind != yaml.SequenceNode {
+		return
+	}
+
+	used := make(map[string]bool)
+	if paths := value(doc, "paths"); paths != nil {
+		for i := 1; i < len(paths.Content); i += 2 {
+			item := paths.Content[i]
+			for j := 0; j+1 < len(item.Content); j += 2 {
+				if !methods[item.Content[j].Value] {
+					continue
+				}
+				if names := value(item.Content[j+1], "tags"); names != nil {
+					for _, name := range names.Content {
+						used[name.Value] = true
+					}
+				}
+			}
+		}
+	}
+
+	kept := make([]*yaml.Node, 0, len(tags.Content))
+	for _, tag := range tags.Content {
+		if name := value(tag, "name"); name == nil || used[name.Value] {
+			kept = append(kept, tag)
+		}
+	}
+	tags.Content = kept
+}
+
+// value returns the value of a key in a mapping node
+func value(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// set sets a string value in a mapping node, replacing an existing one
+func set(node *yaml.Node, key, text string) {
+	if existing := value(node, key); existing != nil {
+		existing.Kind, existing.Tag, existing.Value, existing.Content = yaml.ScalarNode, "!!str", text, nil
+		return
+	}
+	node.Content = append(node.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+		&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: text},
+	)
+}
+
+// YAML encodes the document as YAML
+func (d *Document) YAML() ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(d.root); err != nil {
+		return nil, fmt.Errorf("failed to encode specification: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode specification: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// JSON encodes the document as indented JSON; keys are sorted
+func (d *Document) JSON() ([]byte, error) {
+	decoded, err := plain(d.root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode specification: %w", err)
+	}
+	data, err := json.MarshalIndent(decoded, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode specification: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+// plain converts a node to values encoding/json accepts; mapping keys such as unquoted
+// response codes are always strings
+func plain(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return plain(node.Content[0])
+	case yaml.AliasNode:
+		return plain(node.Alias)
+	case yaml.MappingNode:
+		object := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			converted, err := plain(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			object[node.Content[i].Value] = converted
+		}
+		return object, nil
+	case yaml.SequenceNode:
+		items := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			converted, err := plain(child)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, converted)
+		}
+		return items, nil
+	}
+	var scalar interface{}
+	if err := node.Decode(&scalar); err != nil {
+		return nil, err
+	}
+	return scalar, nil
+}
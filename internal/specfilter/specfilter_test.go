@@ -0,0 +1,164 @@
+package specfilter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"api-to-mcp/pkg/mcp"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const ordersSpec = `openapi: 3.0.3
+info:
+  title: Orders
+  version: 1.0.0
+tags:
+  - name: orders
+  - name: admin
+paths:
+  /orders:
+    # Shared by every operation on the path
+    parameters:
+      - $ref: '#/components/parameters/Tenant'
+    get:
+      operationId: listOrders
+      tags: [orders]
+      responses:
+        200:
+          description: Orders
+          content:
+            application/json:
+              schema:
+                type: array
+                items:
+                  $ref: '#/components/schemas/Order'
+    post:
+      operationId: createOrder
+      tags: [orders]
+      requestBody:
+        $ref: '#/components/requestBodies/NewOrder'
+      responses:
+        '201':
+          description: Created
+  /admin/purge:
+    delete:
+      operationId: purge
+      tags: [admin]
+      responses:
+        '204':
+          $ref: '#/components/responses/Empty'
+components:
+  parameters:
+    Tenant:
+      name: X-Tenant
+      in: header
+      schema:
+        type: string
+  schemas:
+    Order:
+      type: object
+      properties:
+        id:
+          type: integer
+        lines:
+          type: array
+          items:
+            $ref: '#/components/schemas/Line'
+    Line:
+      type: object
+    NewOrder:
+      type: object
+    Unused:
+      type: string
+  requestBodies:
+    NewOrder:
+      content:
+        application/json:
+          schema:
+            $ref: '#/components/schemas/NewOrder'
+  responses:
+    Empty:
+      description: No content
+  securitySchemes:
+    token:
+      type: http
+      scheme: bearer
+security:
+  - token: []
+`
+
+func toolFor(name, method, path string) mcp.Tool {
+	return mcp.Tool{Name: name, Operation: &mcp.Operation{Method: method, Path: path}}
+}
+
+func TestFilter(t *testing.T) {
+	doc, report, err := Filter([]byte(ordersSpec), []mcp.Tool{
+		toolFor("listorders", "GET", "/orders"),
+		{Name: "no_operation"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Operations)
+	assert.Equal(t, 2, report.Removed)
+	assert.Equal(t, []string{
+		"requestBodies/NewOrder", "responses/Empty", "schemas/NewOrder", "schemas/Unused",
+	}, report.Components)
+
+	data, err := doc.YAML()
+	require.NoError(t, err)
+	var filtered map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &filtered))
+
+	paths := filtered["paths"].(map[string]interface{})
+	assert.Len(t, paths, 1)
+	orders := paths["/orders"].(map[string]interface{})
+	assert.Contains(t, orders, "parameters")
+	assert.NotContains(t, orders, "post")
+	assert.Equal(t, "listorders", orders["get"].(map[string]interface{})[ToolExtension])
+
+	components := filtered["components"].(map[string]interface{})
+	// Components referenced through other components are kept
+	assert.Len(t, components["schemas"], 2)
+	assert.Contains(t, components["schemas"], "Line")
+	assert.Contains(t, components["parameters"], "Tenant")
+	assert.Empty(t, components["requestBodies"])
+	assert.Contains(t, components["securitySchemes"], "token")
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "orders"}}, filtered["tags"])
+
+	// Key order and comments survive
+	assert.Regexp(t, `(?s)openapi: 3\.0\.3\ninfo:.*tags:.*paths:.*components:.*security:`, string(data))
+	assert.Contains(t, string(data), "# Shared by every operation on the path")
+}
+
+func TestFilter_JSON(t *testing.T) {
+	doc, _, err := Filter([]byte(ordersSpec), []mcp.Tool{
+		toolFor("listorders", "GET", "/orders"),
+		toolFor("purge", "DELETE", "/admin/purge"),
+	})
+	require.NoError(t, err)
+	data, err := doc.JSON()
+	require.NoError(t, err)
+
+	var filtered map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &filtered))
+	// Unquoted response codes become string keys
+	list := filtered["paths"].(map[string]interface{})["/orders"].(map[string]interface{})["get"].(map[string]interface{})
+	assert.Contains(t, list["responses"], "200")
+	purge := filtered["paths"].(map[string]interface{})["/admin/purge"].(map[string]interface{})["delete"].(map[string]interface{})
+	assert.Contains(t, purge["responses"], "204")
+	assert.Contains(t, filtered["components"].(map[string]interface{})["responses"], "Empty")
+}
+
+func TestFilter_Errors(t *testing.T) {
+	_, _, err := Filter([]byte("swagger: '2.0'\npaths: {}\n"), nil)
+	assert.EqualError(t, err, "only OpenAPI 3 documents can be filtered")
+
+	_, _, err = Filter([]byte("- a\n- b\n"), nil)
+	assert.EqualError(t, err, "specification is not a YAML or JSON object")
+
+	_, _, err = Filter([]byte("openapi: [unclosed"), nil)
+	assert.ErrorContains(t, err, "failed to parse specification")
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// contextKey namespaces values stored in request contexts
+type contextKey string
+
+const (
+	requestIDKey contextKey = "request_id"
+	toolNameKey  contextKey = "tool_name"
+)
+
+// WithRequestID returns a context carrying the correlation ID of a tool call
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the correlation ID carried by a context, if any
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// WithToolName returns a context carrying the name of the tool being executed
+func WithToolName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, toolNameKey, name)
+}
+
+// ToolName returns the name of the tool carried by a context, if any
+func ToolName(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	name, _ := ctx.Value(toolNameKey).(string)
+	return name
+}
+
+// NewRequestID generates a random correlation ID
+func NewRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(buf)
+}
@@ -1,19 +1,67 @@
 package utils
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
 	"time"
 
+	"api-to-mcp/internal/netguard"
+	"api-to-mcp/internal/secrets"
+
 	"github.com/go-resty/resty/v2"
 	"github.com/sirupsen/logrus"
 )
 
+// retryCount counts upstream retries performed by all HTTP clients
+var retryCount atomic.Int64
+
+// RetryCount returns the total number of upstream retries performed so far
+func RetryCount() int64 {
+	return retryCount.Load()
+}
+
+// HTTPError is returned when the upstream API answers with an error status
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, e.Body)
+}
+
 // HTTPClient handles HTTP requests
 type HTTPClient struct {
-	baseURL string
-	client  *resty.Client
-	logger  *logrus.Logger
+	baseURL         string
+	client          *resty.Client
+	logger          *logrus.Logger
+	requestIDHeader string
+
+	slowCallThreshold  time.Duration
+	largeResponseBytes int
+}
+
+// restyLogger routes resty's own messages, which embed request URLs, through the application
+// logger with credentials scrubbed
+type restyLogger struct {
+	logger *logrus.Logger
+}
+
+func (l *restyLogger) Errorf(format string, v ...interface{}) {
+	l.logger.Error(secrets.ScrubString(fmt.Sprintf(format, v...)))
+}
+
+func (l *restyLogger) Warnf(format string, v ...interface{}) {
+	l.logger.Warn(secrets.ScrubString(fmt.Sprintf(format, v...)))
+}
+
+func (l *restyLogger) Debugf(format string, v ...interface{}) {
+	l.logger.Debug(secrets.ScrubString(fmt.Sprintf(format, v...)))
 }
 
 // NewHTTPClient creates a new HTTP client
@@ -24,6 +72,10 @@ func NewHTTPClient(baseURL string, logger *logrus.Logger) *HTTPClient {
 	client.SetRetryCount(3)
 	client.SetRetryWaitTime(1 * time.Second)
 	client.SetRetryMaxWaitTime(5 * time.Second)
+	client.AddRetryHook(func(*resty.Response, error) {
+		retryCount.Add(1)
+	})
+	client.SetLogger(&restyLogger{logger: logger})
 
 	return &HTTPClient{
 		baseURL: baseURL,
@@ -33,39 +85,116 @@ func NewHTTPClient(baseURL string, logger *logrus.Logger) *HTTPClient {
 }
 
 // MakeRequest makes an HTTP request
-func (c *HTTPClient) MakeRequest(method, path string, params map[string]interface{}) (interface{}, error) {
+func (c *HTTPClient) MakeRequest(ctx context.Context, method, path string, params map[string]interface{}) (interface{}, error) {
+	requestID := RequestID(ctx)
 	c.logger.WithFields(logrus.Fields{
-		"method": method,
-		"path":   path,
-		"params": params,
+		"method":         method,
+		"path":           path,
+		"params":         params,
+		"correlation_id": requestID,
 	}).Debug("Making HTTP request")
 
 	// Create request
-	req := c.client.R()
+	req := c.client.R().SetContext(ctx)
 
 	// Set headers
 	req.SetHeader("Content-Type", "application/json")
 	req.SetHeader("Accept", "application/json")
+	if requestID != "" && c.requestIDHeader != "" {
+		req.SetHeader(c.requestIDHeader, requestID)
+	}
 
 	// Handle different HTTP methods
+	start := time.Now()
+	var resp *resty.Response
+	var err error
 	switch method {
 	case "GET":
-		return c.handleGET(req, path, params)
+		resp, err = c.handleGET(req, path, params)
 	case "POST":
-		return c.handlePOST(req, path, params)
+		resp, err = c.handlePOST(req, path, params)
 	case "PUT":
-		return c.handlePUT(req, path, params)
+		resp, err = c.handlePUT(req, path, params)
 	case "DELETE":
-		return c.handleDELETE(req, path, params)
+		resp, err = c.handleDELETE(req, path, params)
 	case "PATCH":
-		return c.handlePATCH(req, path, params)
+		resp, err = c.handlePATCH(req, path, params)
 	default:
 		return nil, fmt.Errorf("unsupported HTTP method: %s", method)
 	}
+	if err != nil {
+		// Transport errors embed the request URL, whose query may carry credentials
+		return nil, secrets.ScrubError(err)
+	}
+
+	c.checkThresholds(ctx, method, path, resp, time.Since(start))
+	return c.parseResponse(resp)
+}
+
+// MakeRawRequest sends a pre-encoded body with the given headers and returns the raw response
+// body, for upstreams that do not speak JSON; error statuses still return an *HTTPError
+func (c *HTTPClient) MakeRawRequest(ctx context.Context, method, path string, body []byte, headers map[string]string) ([]byte, error) {
+	requestID := RequestID(ctx)
+	c.logger.WithFields(logrus.Fields{
+		"method":         method,
+		"path":           path,
+		"size":           len(body),
+		"correlation_id": requestID,
+	}).Debug("Making raw HTTP request")
+
+	req := c.client.R().SetContext(ctx).SetHeaders(headers)
+	if requestID != "" && c.requestIDHeader != "" {
+		req.SetHeader(c.requestIDHeader, requestID)
+	}
+	if body != nil {
+		req.SetBody(body)
+	}
+
+	start := time.Now()
+	resp, err := req.Execute(method, path)
+	if err != nil {
+		return nil, secrets.ScrubError(err)
+	}
+	c.checkThresholds(ctx, method, path, resp, time.Since(start))
+
+	c.logger.WithFields(logrus.Fields{
+		"status_code":    resp.StatusCode(),
+		"size":           len(resp.Body()),
+		"correlation_id": requestID,
+	}).Debug("Received HTTP response")
+	if resp.StatusCode() >= 400 {
+		return resp.Body(), &HTTPError{StatusCode: resp.StatusCode(), Body: resp.String()}
+	}
+	return resp.Body(), nil
+}
+
+// checkThresholds warns about slow calls and large responses
+func (c *HTTPClient) checkThresholds(ctx context.Context, method, path string, resp *resty.Response, elapsed time.Duration) {
+	slow := c.slowCallThreshold > 0 && elapsed > c.slowCallThreshold
+	large := c.largeResponseBytes > 0 && len(resp.Body()) > c.largeResponseBytes
+	if !slow && !large {
+		return
+	}
+
+	entry := c.logger.WithFields(logrus.Fields{
+		"tool_name":      ToolName(ctx),
+		"correlation_id": RequestID(ctx),
+		"method":         method,
+		"path":           path,
+		"status_code":    resp.StatusCode(),
+		"duration_ms":    elapsed.Milliseconds(),
+		"size":           len(resp.Body()),
+	})
+	if slow {
+		entry.WithField("threshold_ms", c.slowCallThreshold.Milliseconds()).Warn("Slow tool call")
+	}
+	if large {
+		entry.WithField("threshold_bytes", c.largeResponseBytes).Warn("Large upstream response")
+	}
 }
 
 // handleGET handles GET requests
-func (c *HTTPClient) handleGET(req *resty.Request, path string, params map[string]interface{}) (interface{}, error) {
+func (c *HTTPClient) handleGET(req *resty.Request, path string, params map[string]interface{}) (*resty.Response, error) {
 	// Add query parameters
 	for key, value := range params {
 		req.SetQueryParam(key, fmt.Sprintf("%v", value))
@@ -76,11 +205,11 @@ func (c *HTTPClient) handleGET(req *resty.Request, path string, params map[strin
 		return nil, fmt.Errorf("GET request failed: %w", err)
 	}
 
-	return c.parseResponse(resp)
+	return resp, nil
 }
 
 // handlePOST handles POST requests
-func (c *HTTPClient) handlePOST(req *resty.Request, path string, params map[string]interface{}) (interface{}, error) {
+func (c *HTTPClient) handlePOST(req *resty.Request, path string, params map[string]interface{}) (*resty.Response, error) {
 	// Set request body
 	if body, exists := params["body"]; exists {
 		req.SetBody(body)
@@ -97,11 +226,11 @@ func (c *HTTPClient) handlePOST(req *resty.Request, path string, params map[stri
 		return nil, fmt.Errorf("POST request failed: %w", err)
 	}
 
-	return c.parseResponse(resp)
+	return resp, nil
 }
 
 // handlePUT handles PUT requests
-func (c *HTTPClient) handlePUT(req *resty.Request, path string, params map[string]interface{}) (interface{}, error) {
+func (c *HTTPClient) handlePUT(req *resty.Request, path string, params map[string]interface{}) (*resty.Response, error) {
 	// Set request body
 	if body, exists := params["body"]; exists {
 		req.SetBody(body)
@@ -118,11 +247,11 @@ func (c *HTTPClient) handlePUT(req *resty.Request, path string, params map[strin
 		return nil, fmt.Errorf("PUT request failed: %w", err)
 	}
 
-	return c.parseResponse(resp)
+	return resp, nil
 }
 
 // handleDELETE handles DELETE requests
-func (c *HTTPClient) handleDELETE(req *resty.Request, path string, params map[string]interface{}) (interface{}, error) {
+func (c *HTTPClient) handleDELETE(req *resty.Request, path string, params map[string]interface{}) (*resty.Response, error) {
 	// Add query parameters
 	for key, value := range params {
 		req.SetQueryParam(key, fmt.Sprintf("%v", value))
@@ -133,11 +262,11 @@ func (c *HTTPClient) handleDELETE(req *resty.Request, path string, params map[st
 		return nil, fmt.Errorf("DELETE request failed: %w", err)
 	}
 
-	return c.parseResponse(resp)
+	return resp, nil
 }
 
 // handlePATCH handles PATCH requests
-func (c *HTTPClient) handlePATCH(req *resty.Request, path string, params map[string]interface{}) (interface{}, error) {
+func (c *HTTPClient) handlePATCH(req *resty.Request, path string, params map[string]interface{}) (*resty.Response, error) {
 	// Set request body
 	if body, exists := params["body"]; exists {
 		req.SetBody(body)
@@ -154,19 +283,20 @@ func (c *HTTPClient) handlePATCH(req *resty.Request, path string, params map[str
 		return nil, fmt.Errorf("PATCH request failed: %w", err)
 	}
 
-	return c.parseResponse(resp)
+	return resp, nil
 }
 
 // parseResponse parses the HTTP response
 func (c *HTTPClient) parseResponse(resp *resty.Response) (interface{}, error) {
 	c.logger.WithFields(logrus.Fields{
-		"status_code": resp.StatusCode(),
-		"size":        len(resp.Body()),
+		"status_code":    resp.StatusCode(),
+		"size":           len(resp.Body()),
+		"correlation_id": RequestID(resp.Request.Context()),
 	}).Debug("Received HTTP response")
 
 	// Check for HTTP errors
 	if resp.StatusCode() >= 400 {
-		return nil, fmt.Errorf("HTTP error %d: %s", resp.StatusCode(), resp.String())
+		return nil, &HTTPError{StatusCode: resp.StatusCode(), Body: resp.String()}
 	}
 
 	// Try to parse as JSON
@@ -179,13 +309,26 @@ func (c *HTTPClient) parseResponse(resp *resty.Response) (interface{}, error) {
 	return result, nil
 }
 
-// SetAuth sets authentication for the client
-func (c *HTTPClient) SetAuth(authType, token string) {
+// Probe sends a single request without retries and returns the response status code
+func (c *HTTPClient) Probe(method, path string) (int, error) {
+	resp, err := c.client.R().
+		SetHeader("Accept", "application/json").
+		AddRetryCondition(func(*resty.Response, error) bool { return false }).
+		Execute(method, path)
+	if err != nil {
+		return 0, fmt.Errorf("probe request failed: %w", secrets.ScrubError(err))
+	}
+	return resp.StatusCode(), nil
+}
+
+// SetAuth sets authentication for the client; the token is resolved for each request and
+// never stored on the client
+func (c *HTTPClient) SetAuth(authType string, token secrets.Secret) {
 	switch authType {
 	case "bearer":
-		c.client.SetAuthToken(token)
+		c.client.OnBeforeRequest(credentialMiddleware(token, "Authorization", "Bearer "))
 	case "apikey":
-		c.client.SetHeader("X-API-Key", token)
+		c.client.OnBeforeRequest(credentialMiddleware(token, "X-API-Key", ""))
 	case "basic":
 		// TODO: Implement basic auth
 		c.logger.Warn("Basic authentication not implemented")
@@ -194,6 +337,63 @@ func (c *HTTPClient) SetAuth(authType, token string) {
 	}
 }
 
+// credentialMiddleware resolves a secret into a request header, wiping the revealed buffer
+func credentialMiddleware(token secrets.Secret, header, prefix string) resty.RequestMiddleware {
+	return func(_ *resty.Client, req *resty.Request) error {
+		value, err := token.Reveal()
+		if err != nil {
+			return fmt.Errorf("failed to resolve upstream credentials: %w", err)
+		}
+		defer secrets.Wipe(value)
+		req.Header.Set(header, prefix+string(value))
+		return nil
+	}
+}
+
+// SetRequestIDHeader sets the header used to propagate correlation IDs upstream
+func (c *HTTPClient) SetRequestIDHeader(name string) {
+	c.requestIDHeader = name
+}
+
+// SetWarningThresholds sets the latency and response size above which calls are logged as warnings
+func (c *HTTPClient) SetWarningThresholds(slowCall time.Duration, largeResponseBytes int) {
+	c.slowCallThreshold = slowCall
+	c.largeResponseBytes = largeResponseBytes
+}
+
+// SetTimeout sets the timeout applied to each request attempt
+func (c *HTTPClient) SetTimeout(timeout time.Duration) {
+	c.client.SetTimeout(timeout)
+}
+
+// SetOutboundPolicy restricts the hosts and addresses the client may reach, including redirects
+func (c *HTTPClient) SetOutboundPolicy(policy *netguard.Policy) {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   policy.DialControl,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dialer.DialContext
+	c.client.SetTransport(transport)
+
+	checkHost := func(req *http.Request) error {
+		return policy.CheckHost(req.URL.Hostname())
+	}
+	c.client.SetPreRequestHook(func(_ *resty.Client, req *http.Request) error {
+		return checkHost(req)
+	})
+	c.client.SetRedirectPolicy(resty.FlexibleRedirectPolicy(10), resty.RedirectPolicyFunc(func(req *http.Request, _ []*http.Request) error {
+		return checkHost(req)
+	}))
+
+	// Blocked destinations are refused at dial time and must not be retried
+	c.client.AddRetryCondition(func(_ *resty.Response, err error) bool {
+		var blocked *netguard.BlockedError
+		return err != nil && !errors.As(err, &blocked)
+	})
+}
+
 // SetBaseURL sets the base URL for the client
 func (c *HTTPClient) SetBaseURL(baseURL string) {
 	c.baseURL = baseURL
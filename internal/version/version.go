@@ -0,0 +1,64 @@
+package version
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// Build identity, overridden at build time with:
+//
+//	go build -ldflags "-X api-to-mcp/internal/version.Version=v1.2.3 \
+//	  -X api-to-mcp/internal/version.Commit=$(git rev-parse HEAD) \
+//	  -X api-to-mcp/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// Info describes the build identity of the running binary
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+	GoVersion string `json:"goVersion"`
+}
+
+// Get returns the build information, falling back to VCS stamps embedded by the Go toolchain
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+
+	if buildInfo, ok := debug.ReadBuildInfo(); ok && info.Commit == "" {
+		for _, setting := range buildInfo.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Commit = setting.Value
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			}
+		}
+	}
+
+	return info
+}
+
+// String formats the build information for --version output
+func (i Info) String() string {
+	s := i.Version
+	if i.Commit != "" {
+		s += fmt.Sprintf(" (commit %s", i.Commit)
+		if i.BuildDate != "" {
+			s += fmt.Sprintf(", built %s", i.BuildDate)
+		}
+		s += ")"
+	}
+	return s + " " + i.GoVersion
+}
@@ -1,11 +1,25 @@
 package mcp
 
+import "context"
+
 // Tool represents an MCP tool
 type Tool struct {
-	Name        string                                                   `json:"name"`
-	Description string                                                   `json:"description"`
-	InputSchema *InputSchema                                             `json:"inputSchema"`
-	Handler     func(params map[string]interface{}) (interface{}, error) `json:"-"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	InputSchema *InputSchema `json:"inputSchema"`
+	Handler     ToolHandler  `json:"-"`
+	Operation   *Operation   `json:"-"`
+}
+
+// ToolHandler executes a tool call with the given arguments
+type ToolHandler func(ctx context.Context, params map[string]interface{}) (interface{}, error)
+
+// Operation identifies the upstream HTTP operation a tool is bound to
+type Operation struct {
+	Method      string `json:"method"`
+	Path        string `json:"path"`
+	BaseURL     string `json:"baseUrl,omitempty"`
+	OperationID string `json:"operationId,omitempty"`
 }
 
 // InputSchema defines the input schema for a tool
@@ -80,19 +94,107 @@ type CallToolRequest struct {
 type CallToolParams struct {
 	Name      string                 `json:"name"`
 	Arguments map[string]interface{} `json:"arguments"`
+	Meta      map[string]interface{} `json:"_meta,omitempty"`
 }
 
 // CallToolResponse represents the response to a tool call
 type CallToolResponse struct {
-	JSONRPC string      `json:"jsonrpc"`
-	Result  interface{} `json:"result"`
-	ID      string      `json:"id"`
+	JSONRPC string                 `json:"jsonrpc"`
+	Result  interface{}            `json:"result"`
+	Meta    map[string]interface{} `json:"_meta,omitempty"`
+	ID      string                 `json:"id"`
+}
+
+// Resource represents an MCP resource
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents is the content of a resource at the time it was read
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
+
+// ResourceProvider serves resources alongside a namespace's tools
+type ResourceProvider interface {
+	Resources() []Resource
+	ReadResource(ctx context.Context, uri string) (*ResourceContents, error)
+	Subscribe(ctx context.Context, uri string) error
+	Unsubscribe(uri string) error
 }
 
+// ResourceParams identifies the resource of a read, subscribe or unsubscribe request
+type ResourceParams struct {
+	URI string `json:"uri"`
+}
+
+// ListResourcesResponse represents the response to list resources
+type ListResourcesResponse struct {
+	JSONRPC string `json:"jsonrpc"`
+	Result  struct {
+		Resources []Resource `json:"resources"`
+	} `json:"result"`
+	ID string `json:"id"`
+}
+
+// ReadResourceResponse represents the response to a resource read
+type ReadResourceResponse struct {
+	JSONRPC string `json:"jsonrpc"`
+	Result  struct {
+		Contents []ResourceContents `json:"contents"`
+	} `json:"result"`
+	ID string `json:"id"`
+}
+
+// EmptyResponse represents a response without a result payload
+type EmptyResponse struct {
+	JSONRPC string   `json:"jsonrpc"`
+	Result  struct{} `json:"result"`
+	ID      string   `json:"id"`
+}
+
+// MetaCorrelationID is the _meta key carrying a tool call's correlation ID
+const MetaCorrelationID = "correlationId"
+
 // ServerInfo represents information about the MCP server
 type ServerInfo struct {
-	Name    string `json:"name"`
-	Version string `json:"version"`
+	Name    string     `json:"name"`
+	Version string     `json:"version"`
+	Build   *BuildInfo `json:"build,omitempty"`
+}
+
+// BuildInfo identifies the exact build of the server binary
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+	GoVersion string `json:"goVersion,omitempty"`
+}
+
+// InitializeParams represents the parameters of an initialize request
+type InitializeParams struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities,omitempty"`
+	ClientInfo      *ServerInfo            `json:"clientInfo,omitempty"`
+}
+
+// InitializeResult represents the result of an initialize request
+type InitializeResult struct {
+	ProtocolVersion string                 `json:"protocolVersion"`
+	Capabilities    map[string]interface{} `json:"capabilities"`
+	ServerInfo      ServerInfo             `json:"serverInfo"`
+}
+
+// InitializeResponse represents the response to an initialize request
+type InitializeResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	Result  InitializeResult `json:"result"`
+	ID      string           `json:"id"`
 }
 
 // NewError creates a new JSON-RPC error
@@ -113,8 +215,23 @@ const (
 	InternalError  = -32603
 )
 
+// Server-defined error codes
+const (
+	// PolicyDenied is returned when a safety policy refuses to execute a tool
+	PolicyDenied = -32001
+)
+
 // MCP method names
 const (
-	MethodListTools = "tools/list"
-	MethodCallTool  = "tools/call"
+	MethodInitialize = "initialize"
+	MethodListTools  = "tools/list"
+	MethodCallTool   = "tools/call"
+
+	MethodListResources       = "resources/list"
+	MethodReadResource        = "resources/read"
+	MethodSubscribeResource   = "resources/subscribe"
+	MethodUnsubscribeResource = "resources/unsubscribe"
 )
+
+// ProtocolVersion is the MCP protocol revision implemented by the server
+const ProtocolVersion = "2024-11-05"
@@ -6,6 +6,18 @@ type ParsedSpec struct {
 	Servers    []Server             `json:"servers"`
 	Endpoints  []Endpoint           `json:"endpoints"`
 	Components map[string]Component `json:"components"`
+	// Auth is the authentication the API expects, when the source format declares it
+	Auth *AuthScheme `json:"auth,omitempty"`
+}
+
+// AuthScheme describes how an API expects requests to be authenticated
+type AuthScheme struct {
+	// Type is bearer, apikey or basic
+	Type string `json:"type"`
+	// Name is the header or query parameter carrying an API key
+	Name string `json:"name,omitempty"`
+	// In is header or query for API keys
+	In string `json:"in,omitempty"`
 }
 
 // Info represents the API information
@@ -40,6 +52,8 @@ type Parameter struct {
 	Description string `json:"description"`
 	Required    bool   `json:"required"`
 	Schema      Schema `json:"schema"`
+	// AllowReserved permits reserved characters such as / in path parameter values
+	AllowReserved bool `json:"allowReserved,omitempty"`
 }
 
 // RequestBody represents a request body